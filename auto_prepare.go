@@ -0,0 +1,429 @@
+package bob
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/stephenafamo/scan"
+)
+
+// AutoPrepareOptions configures an [AutoPrepareExecutor]
+type AutoPrepareOptions struct {
+	// Threshold is the number of times a query is run before it is prepared.
+	// A query is prepared the next time it is run after that.
+	// A zero value prepares every query the first time it is run
+	Threshold int
+
+	// MaxStatements is the number of prepared statements to keep open.
+	// When it is reached, the least recently used statement is closed.
+	// if zero, it fallsback to 100
+	MaxStatements int
+
+	// IsInvalidated reports whether an error means that a prepared statement
+	// can no longer be used and has to be prepared again.
+	// if nil, it fallsback to [IsStatementInvalidated]
+	IsInvalidated func(error) bool
+}
+
+// AutoPrepareStats are the counters of an [AutoPrepareExecutor]
+type AutoPrepareStats struct {
+	// Hits is the number of queries run with an already prepared statement
+	Hits int64
+	// Misses is the number of queries run without an already prepared statement
+	Misses int64
+	// Prepares is the number of statements that were prepared
+	Prepares int64
+	// Evictions is the number of statements closed to stay under MaxStatements
+	Evictions int64
+	// Invalidations is the number of statements prepared again after
+	// an invalidation error
+	Invalidations int64
+	// Open is the number of prepared statements currently open
+	Open int
+}
+
+// IsStatementInvalidated reports whether the error means that a prepared statement
+// has to be prepared again. This is the case when the schema has changed in a way
+// that changes the result type ("cached plan must not change result type" in PostgreSQL,
+// error 1615 in MySQL), or when the statement no longer exists on the connection (26000)
+func IsStatementInvalidated(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var stateErr interface{ SQLState() string }
+	if errors.As(err, &stateErr) {
+		switch stateErr.SQLState() {
+		case "26000":
+			return true
+		case "0A000":
+			return strings.Contains(err.Error(), "cached plan must not change result type")
+		}
+	}
+
+	return strings.Contains(err.Error(), "Prepared statement needs to be re-prepared")
+}
+
+// AutoPrepare wraps a [Preparer] such as [DB] or [Conn] and transparently prepares
+// every query that is run more than opts.Threshold times.
+// The prepared statements are kept in an LRU cache of up to opts.MaxStatements
+// and are used for later runs of the same SQL, so that existing code
+// gets the performance of prepared statements without managing them.
+//
+// If running a prepared statement fails with an invalidation error,
+// the statement is prepared again and the query is retried once.
+//
+// Statements prepared on a [DB] are prepared on each connection of the pool as needed
+// by [database/sql], so each connection has at most opts.MaxStatements of them.
+// To keep a separate LRU cache for each connection, wrap each [Conn] instead.
+//
+// Evicted statements are only closed once the queries using them are done,
+// for queries that return rows this is when the rows are closed.
+// Close the executor to close all the statements
+func AutoPrepare[P PreparedExecutor](exec Preparer[P], opts AutoPrepareOptions) *AutoPrepareExecutor[P] {
+	if opts.MaxStatements <= 0 {
+		opts.MaxStatements = 100
+	}
+
+	if opts.IsInvalidated == nil {
+		opts.IsInvalidated = IsStatementInvalidated
+	}
+
+	return &AutoPrepareExecutor[P]{
+		exec:  exec,
+		opts:  opts,
+		seen:  newLRU[int](opts.MaxStatements * 4),
+		stmts: newLRU[*cachedStmt[P]](opts.MaxStatements),
+	}
+}
+
+// AutoPrepareExecutor is the [Executor] returned by [AutoPrepare].
+// It is safe for concurrent use
+type AutoPrepareExecutor[P PreparedExecutor] struct {
+	exec Preparer[P]
+	opts AutoPrepareOptions
+
+	mu sync.Mutex
+	// seen counts how often each query that is not prepared was run
+	seen   *lru[int]
+	stmts  *lru[*cachedStmt[P]]
+	nextID uint64
+	stats  AutoPrepareStats
+}
+
+// cachedStmt is a prepared statement with an id
+// to tell apart statements prepared for the same query.
+// refs and removed are guarded by the mutex of the executor
+type cachedStmt[P PreparedExecutor] struct {
+	id   uint64
+	stmt P
+	// refs is the number of queries using the statement
+	refs int
+	// removed is set once the statement is no longer cached,
+	// it is closed when refs drops to zero
+	removed bool
+}
+
+// remove marks the statement as no longer cached and reports whether
+// it can be closed now. a.mu must be held
+func (c *cachedStmt[P]) remove() bool {
+	c.removed = true
+	return c.refs == 0
+}
+
+// Stats returns the current counters of the executor
+func (a *AutoPrepareExecutor[P]) Stats() AutoPrepareStats {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	stats := a.stats
+	stats.Open = a.stmts.len()
+	return stats
+}
+
+// Close closes all the prepared statements.
+// The executor can still be used afterwards
+// Statements that are in use are closed once the queries using them are done
+func (a *AutoPrepareExecutor[P]) Close() error {
+	a.mu.Lock()
+	stmts := a.stmts.clear()
+	a.seen.clear()
+
+	unused := stmts[:0]
+	for _, cached := range stmts {
+		if cached.remove() {
+			unused = append(unused, cached)
+		}
+	}
+	a.mu.Unlock()
+
+	var errs []error
+	for _, cached := range unused {
+		errs = append(errs, cached.stmt.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// release ends the use of a statement returned by stmt,
+// closing it if it was removed from the cache in the meantime
+func (a *AutoPrepareExecutor[P]) release(cached *cachedStmt[P]) {
+	a.mu.Lock()
+	cached.refs--
+	closeStmt := cached.removed && cached.refs == 0
+	a.mu.Unlock()
+
+	if closeStmt {
+		cached.stmt.Close()
+	}
+}
+
+// PrepareContext prepares the query with the wrapped executor,
+// so that [Prepare] and [PrepareQuery] can be used with it.
+// The statement is not cached
+func (a *AutoPrepareExecutor[P]) PrepareContext(ctx context.Context, query string) (P, error) {
+	return a.exec.PrepareContext(ctx, query)
+}
+
+// stmt returns the prepared statement to run the query with.
+// ok is false if the query should not be prepared yet.
+// The statement is not closed until it is given to release
+func (a *AutoPrepareExecutor[P]) stmt(ctx context.Context, query string) (*cachedStmt[P], bool, error) {
+	a.mu.Lock()
+	if cached, ok := a.stmts.get(query); ok {
+		a.stats.Hits++
+		cached.refs++
+		a.mu.Unlock()
+		return cached, true, nil
+	}
+
+	a.stats.Misses++
+	count, _ := a.seen.get(query)
+	if count < a.opts.Threshold {
+		a.seen.add(query, count+1)
+		a.mu.Unlock()
+		return nil, false, nil
+	}
+	a.mu.Unlock()
+
+	// The query is prepared without holding the lock, so that other queries can run
+	stmt, err := a.exec.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return a.store(query, stmt), true, nil
+}
+
+// store caches the statement, unless the query was prepared at the same time,
+// and returns the statement to use, which must be given to release
+func (a *AutoPrepareExecutor[P]) store(query string, stmt P) *cachedStmt[P] {
+	a.mu.Lock()
+	if existing, ok := a.stmts.get(query); ok {
+		existing.refs++
+		a.mu.Unlock()
+		stmt.Close()
+		return existing
+	}
+
+	a.nextID++
+	cached := &cachedStmt[P]{id: a.nextID, stmt: stmt, refs: 1}
+
+	a.stats.Prepares++
+	a.seen.remove(query)
+	evicted, ok := a.stmts.add(query, cached)
+	closeEvicted := false
+	if ok {
+		a.stats.Evictions++
+		closeEvicted = evicted.remove()
+	}
+	a.mu.Unlock()
+
+	if closeEvicted {
+		evicted.stmt.Close()
+	}
+
+	return cached
+}
+
+// reprepare replaces an invalidated statement with a new one.
+// The old statement is released, and the new one must be given to release
+func (a *AutoPrepareExecutor[P]) reprepare(ctx context.Context, query string, old *cachedStmt[P]) (*cachedStmt[P], error) {
+	a.mu.Lock()
+	current, ok := a.stmts.get(query)
+	if ok && current.id != old.id {
+		// another query has already prepared it again
+		current.refs++
+		a.mu.Unlock()
+		a.release(old)
+		return current, nil
+	}
+
+	if ok {
+		a.stmts.remove(query)
+		old.remove()
+	}
+	a.stats.Invalidations++
+	a.mu.Unlock()
+
+	// closes the old statement unless another query is still using it
+	a.release(old)
+
+	stmt, err := a.exec.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.store(query, stmt), nil
+}
+
+func (a *AutoPrepareExecutor[P]) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	stmt, ok, err := a.stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return a.exec.ExecContext(ctx, query, args...)
+	}
+
+	result, err := stmt.stmt.ExecContext(ctx, args...)
+	if err == nil || !a.opts.IsInvalidated(err) {
+		a.release(stmt)
+		return result, err
+	}
+
+	stmt, err = a.reprepare(ctx, query, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer a.release(stmt)
+
+	return stmt.stmt.ExecContext(ctx, args...)
+}
+
+func (a *AutoPrepareExecutor[P]) QueryContext(ctx context.Context, query string, args ...any) (scan.Rows, error) {
+	stmt, ok, err := a.stmt(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return a.exec.QueryContext(ctx, query, args...)
+	}
+
+	rows, err := stmt.stmt.QueryContext(ctx, args...)
+	if err != nil && a.opts.IsInvalidated(err) {
+		stmt, err = a.reprepare(ctx, query, stmt)
+		if err != nil {
+			return nil, err
+		}
+
+		rows, err = stmt.stmt.QueryContext(ctx, args...)
+	}
+
+	if err != nil {
+		a.release(stmt)
+		return nil, err
+	}
+
+	return &preparedRows[P]{Rows: rows, a: a, stmt: stmt}, nil
+}
+
+// preparedRows releases the statement of the query when the rows are closed
+type preparedRows[P PreparedExecutor] struct {
+	scan.Rows
+	a      *AutoPrepareExecutor[P]
+	stmt   *cachedStmt[P]
+	closed bool
+}
+
+func (r *preparedRows[P]) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	defer r.a.release(r.stmt)
+	return r.Rows.Close()
+}
+
+// lru is a map that keeps up to capacity entries,
+// removing the least recently used one when it is full
+type lru[V any] struct {
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+type lruEntry[V any] struct {
+	key   string
+	value V
+}
+
+func newLRU[V any](capacity int) *lru[V] {
+	return &lru[V]{
+		capacity: capacity,
+		items:    map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+func (l *lru[V]) len() int {
+	return l.order.Len()
+}
+
+func (l *lru[V]) get(key string) (V, bool) {
+	el, ok := l.items[key]
+	if !ok {
+		return *new(V), false
+	}
+
+	l.order.MoveToFront(el)
+	return el.Value.(*lruEntry[V]).value, true
+}
+
+// add sets the value of the key and returns the evicted value, if any
+func (l *lru[V]) add(key string, value V) (V, bool) {
+	if el, ok := l.items[key]; ok {
+		el.Value.(*lruEntry[V]).value = value
+		l.order.MoveToFront(el)
+		return *new(V), false
+	}
+
+	l.items[key] = l.order.PushFront(&lruEntry[V]{key: key, value: value})
+	if l.order.Len() <= l.capacity {
+		return *new(V), false
+	}
+
+	oldest := l.order.Back()
+	l.order.Remove(oldest)
+	entry := oldest.Value.(*lruEntry[V])
+	delete(l.items, entry.key)
+
+	return entry.value, true
+}
+
+func (l *lru[V]) remove(key string) {
+	if el, ok := l.items[key]; ok {
+		l.order.Remove(el)
+		delete(l.items, key)
+	}
+}
+
+// clear removes all the entries and returns their values
+func (l *lru[V]) clear() []V {
+	values := make([]V, 0, l.order.Len())
+	for el := l.order.Front(); el != nil; el = el.Next() {
+		values = append(values, el.Value.(*lruEntry[V]).value)
+	}
+
+	l.items = map[string]*list.Element{}
+	l.order.Init()
+
+	return values
+}
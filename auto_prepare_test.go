@@ -0,0 +1,249 @@
+package bob
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stephenafamo/scan"
+	_ "modernc.org/sqlite"
+)
+
+func TestAutoPrepare(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	exec := AutoPrepare(NewDB(db), AutoPrepareOptions{Threshold: 1, MaxStatements: 1})
+	defer exec.Close()
+
+	if _, err := exec.ExecContext(ctx, "CREATE TABLE users (id INTEGER PRIMARY KEY)"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range 3 {
+		if _, err := exec.ExecContext(ctx, "INSERT INTO users (id) VALUES (?1)", i+1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	countQuery := BaseQuery[Expression]{
+		Expression: ExpressionFunc(func(_ context.Context, w io.StringWriter, _ Dialect, _ int) ([]any, error) {
+			w.WriteString("SELECT count(*) FROM users")
+			return nil, nil
+		}),
+	}
+
+	count := func() int64 {
+		n, err := One(ctx, exec, countQuery, scan.SingleColumnMapper[int64])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	for range 3 {
+		if n := count(); n != 3 {
+			t.Fatalf("expected 3 users, got %d", n)
+		}
+	}
+
+	if diff := cmp.Diff(AutoPrepareStats{
+		Hits:      2,
+		Misses:    5,
+		Prepares:  2,
+		Evictions: 1,
+		Open:      1,
+	}, exec.Stats()); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if err := exec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if open := exec.Stats().Open; open != 0 {
+		t.Fatalf("expected no open statements, got %d", open)
+	}
+}
+
+var errInvalidated = errors.New("invalidated")
+
+// invalidPreparer prepares statements that fail with errInvalidated
+// if they were prepared before the schema changed
+type invalidPreparer struct {
+	NoopExecutor
+	schema   int
+	prepared []int
+	closed   int
+}
+
+func (p *invalidPreparer) PrepareContext(ctx context.Context, query string) (*invalidStmt, error) {
+	p.prepared = append(p.prepared, p.schema)
+	return &invalidStmt{p: p, schema: p.schema}, nil
+}
+
+type invalidStmt struct {
+	p      *invalidPreparer
+	schema int
+}
+
+func (s *invalidStmt) ExecContext(ctx context.Context, args ...any) (sql.Result, error) {
+	if s.schema != s.p.schema {
+		return nil, errInvalidated
+	}
+	return nil, nil
+}
+
+func (s *invalidStmt) QueryContext(ctx context.Context, args ...any) (scan.Rows, error) {
+	_, err := s.ExecContext(ctx, args...)
+	return nil, err
+}
+
+func (s *invalidStmt) Close() error {
+	s.p.closed++
+	return nil
+}
+
+func TestAutoPrepareInvalidated(t *testing.T) {
+	ctx := context.Background()
+
+	p := &invalidPreparer{}
+	exec := AutoPrepare(p, AutoPrepareOptions{
+		IsInvalidated: func(err error) bool { return errors.Is(err, errInvalidated) },
+	})
+
+	if _, err := exec.ExecContext(ctx, "UPDATE users SET a = 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	p.schema++
+	if _, err := exec.QueryContext(ctx, "UPDATE users SET a = 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]int{0, 1}, p.prepared); diff != "" {
+		t.Fatal(diff)
+	}
+	if p.closed != 1 {
+		t.Fatalf("expected the invalidated statement to be closed, got %d", p.closed)
+	}
+
+	stats := exec.Stats()
+	if stats.Invalidations != 1 || stats.Prepares != 2 || stats.Open != 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestAutoPrepareEvictedInUse(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(2)
+
+	p := &closeRecorder{DB: NewDB(db)}
+	exec := AutoPrepare(p, AutoPrepareOptions{MaxStatements: 1})
+	defer exec.Close()
+
+	rows, err := exec.QueryContext(ctx, "SELECT 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// evicts the statement of the open rows
+	if _, err := exec.ExecContext(ctx, "SELECT 2"); err != nil {
+		t.Fatal(err)
+	}
+	if exec.Stats().Evictions != 1 {
+		t.Fatalf("expected an eviction, got %+v", exec.Stats())
+	}
+	if len(p.closed) != 0 {
+		t.Fatalf("expected the statement in use not to be closed, got %v", p.closed)
+	}
+
+	if !rows.Next() {
+		t.Fatalf("expected a row, got %v", rows.Err())
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"SELECT 1"}, p.closed); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if err := exec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"SELECT 1", "SELECT 2"}, p.closed); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+// closeRecorder records the queries of the statements it prepared when they are closed
+type closeRecorder struct {
+	DB
+	mu     sync.Mutex
+	closed []string
+}
+
+func (c *closeRecorder) PrepareContext(ctx context.Context, query string) (*recordedStmt, error) {
+	stmt, err := c.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recordedStmt{StdPrepared: stmt, c: c, query: query}, nil
+}
+
+type recordedStmt struct {
+	StdPrepared
+	c     *closeRecorder
+	query string
+}
+
+func (s *recordedStmt) Close() error {
+	s.c.mu.Lock()
+	s.c.closed = append(s.c.closed, s.query)
+	s.c.mu.Unlock()
+
+	return s.StdPrepared.Close()
+}
+
+type stateMessageErr struct{ state, msg string }
+
+func (e stateMessageErr) Error() string    { return e.msg }
+func (e stateMessageErr) SQLState() string { return e.state }
+
+func TestIsStatementInvalidated(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"nil":         {err: nil},
+		"other":       {err: errors.New("syntax error")},
+		"result type": {err: stateMessageErr{"0A000", "cached plan must not change result type"}, expected: true},
+		"other 0A000": {err: stateMessageErr{"0A000", "feature not supported"}},
+		"missing":     {err: stateMessageErr{"26000", `prepared statement "s1" does not exist`}, expected: true},
+		"mysql":       {err: errors.New("Error 1615 (HY000): Prepared statement needs to be re-prepared"), expected: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := IsStatementInvalidated(tc.err); got != tc.expected {
+				t.Fatalf("expected %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package bobtest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/bobtest"
+	"github.com/stephenafamo/bob/dialect/sqlite"
+	"github.com/stephenafamo/bob/dialect/sqlite/dialect"
+	"github.com/stephenafamo/bob/dialect/sqlite/sm"
+	"github.com/stephenafamo/bob/dialect/sqlite/um"
+	_ "modernc.org/sqlite"
+)
+
+type user struct {
+	ID        int64     `db:"id,pk"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type userSetter struct {
+	Name *string `db:"name"`
+}
+
+func (s userSetter) SetColumns() []string { return []string{"name"} }
+
+func (s userSetter) Apply(q *dialect.InsertQuery) {}
+
+func (s userSetter) UpdateMod() bob.Mod[*dialect.UpdateQuery] {
+	return bob.ModFunc[*dialect.UpdateQuery](func(*dialect.UpdateQuery) {})
+}
+
+var userTable = sqlite.NewTable[user, userSetter]("", "users", sqlite.Quote("users", "*"))
+
+// fakeTB collects the errors reported to it
+type fakeTB struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (f *fakeTB) Helper()                   {}
+func (f *fakeTB) Cleanup(fn func())         { f.cleanups = append(f.cleanups, fn) }
+func (f *fakeTB) Error(args ...any)         { f.errors = append(f.errors, fmt.Sprint(args...)) }
+func (f *fakeTB) Fatalf(s string, a ...any) { panic(fmt.Sprintf(s, a...)) }
+
+func (f *fakeTB) end() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func findUser(ctx context.Context, exec bob.Executor, id int64) (user, error) {
+	return userTable.Query(sm.Where(sqlite.Quote("id").EQ(sqlite.Arg(id)))).One(ctx, exec)
+}
+
+func renameUser(ctx context.Context, exec bob.Transactor[bob.Transaction], id int64, name string) error {
+	tx, err := exec.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = userTable.Update(
+		um.SetCol("name").ToArg(name),
+		um.Where(sqlite.Quote("id").EQ(sqlite.Arg(id))),
+	).Exec(ctx, tx)
+	if err != nil {
+		return errors.Join(err, tx.Rollback(ctx))
+	}
+
+	return tx.Commit(ctx)
+}
+
+// transactor converts a Transactor with a concrete transaction type
+type transactor[Tx bob.Transaction] struct{ bob.Transactor[Tx] }
+
+func (t transactor[Tx]) Begin(ctx context.Context) (bob.Transaction, error) {
+	return t.Transactor.Begin(ctx)
+}
+
+const selectUser = `SELECT "users"."id" AS "id", "users"."name" AS "name", "users"."created_at" AS "created_at"
+	FROM "users" WHERE ("id" = ?1)`
+
+func TestMock(t *testing.T) {
+	ctx := context.Background()
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	mock := bobtest.New(t)
+	mock.ExpectQuery(selectUser).
+		WithArgs(1).
+		WillReturnRows(bobtest.NewRows("id", "name", "created_at").AddRow(1, "alice", created))
+	mock.ExpectQuery(selectUser).
+		WithArgs(bobtest.AnyArg()).
+		WillReturnRows(bobtest.NewRows("id", "name", "created_at"))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "users" SET "name" = ?1 WHERE ("id" = ?2)
+		RETURNING "users"."id" AS "id", "users"."name" AS "name", "users"."created_at" AS "created_at"`).
+		WithArgs("bob", 1).
+		WillReturnError(errors.New("failed"))
+	mock.ExpectRollback()
+
+	u, err := findUser(ctx, mock, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.ID != 1 || u.Name != "alice" || !u.CreatedAt.Equal(created) {
+		t.Fatalf("unexpected user %#v", u)
+	}
+
+	if _, err := findUser(ctx, mock, 2); err == nil {
+		t.Fatal("expected an error for no rows")
+	}
+
+	err = renameUser(ctx, transactor[*bobtest.Mock]{mock}, 1, "bob")
+	if err == nil || err.Error() != "failed" {
+		t.Fatalf("expected the canned error, got %v", err)
+	}
+}
+
+func TestMockUnexpected(t *testing.T) {
+	ctx := context.Background()
+	tb := &fakeTB{TB: t}
+
+	mock := bobtest.New(tb)
+	mock.ExpectQuery(selectUser).WithArgs(2)
+	mock.ExpectExec("DELETE FROM users")
+
+	_, err := findUser(ctx, mock, 1)
+	if !errors.Is(err, bobtest.ErrUnexpectedCall) {
+		t.Fatalf("expected an unexpected call error, got %v", err)
+	}
+
+	tb.end()
+	if len(tb.errors) != 2 {
+		t.Fatalf("expected 2 errors, got %q", tb.errors)
+	}
+	if !strings.Contains(tb.errors[1], "2 expectations were not met") {
+		t.Fatalf("unexpected error %q", tb.errors[1])
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	ctx := context.Background()
+	golden := filepath.Join(t.TempDir(), "testdata", "users.golden.json")
+
+	t.Run("record", func(t *testing.T) {
+		db, err := bob.Open("sqlite", ":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+
+		if _, err := db.ExecContext(ctx, `
+			CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, created_at DATETIME NOT NULL);
+			INSERT INTO users (name, created_at) VALUES ('alice', '2024-01-02 03:04:05');
+		`); err != nil {
+			t.Fatal(err)
+		}
+
+		exec := bobtest.RecordTransactor(t, db, golden)
+		if err := renameUser(ctx, transactor[*bobtest.Recorder]{exec}, 1, "bob"); err != nil {
+			t.Fatal(err)
+		}
+
+		u, err := findUser(ctx, exec, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Name != "bob" {
+			t.Fatalf("unexpected user %#v", u)
+		}
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		mock := bobtest.Replay(t, golden)
+		if err := renameUser(ctx, transactor[*bobtest.Mock]{mock}, 1, "bob"); err != nil {
+			t.Fatal(err)
+		}
+
+		u, err := findUser(ctx, mock, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Name != "bob" || u.CreatedAt.Year() != 2024 {
+			t.Fatalf("unexpected user %#v", u)
+		}
+	})
+
+	t.Run("replay with other args", func(t *testing.T) {
+		tb := &fakeTB{TB: t}
+		mock := bobtest.Replay(tb, golden)
+
+		err := renameUser(ctx, transactor[*bobtest.Mock]{mock}, 1, "carol")
+		if !errors.Is(err, bobtest.ErrUnexpectedCall) {
+			t.Fatalf("expected an unexpected call error, got %v", err)
+		}
+	})
+}
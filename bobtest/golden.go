@@ -0,0 +1,405 @@
+package bobtest
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/internal/replay"
+	"github.com/stephenafamo/scan"
+)
+
+var (
+	_ bob.Transactor[*Recorder] = (*Recorder)(nil)
+	_ bob.Transaction           = (*Recorder)(nil)
+)
+
+// errNoTransaction is returned when Commit or Rollback is called on a
+// Recorder that is not a transaction
+var errNoTransaction = errors.New("bobtest: not in a transaction")
+
+// goldenCall is a call saved in a golden file
+type goldenCall struct {
+	Kind    callKind        `json:"kind"`
+	SQL     string          `json:"sql,omitempty"`
+	Args    []goldenValue   `json:"args,omitempty"`
+	Columns []string        `json:"columns,omitempty"`
+	Rows    [][]goldenValue `json:"rows,omitempty"`
+	Result  *result         `json:"result,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// goldenValue is a value saved in a golden file along with its type,
+// so that it is restored with the same type
+type goldenValue struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+func encodeValue(v any) (goldenValue, error) {
+	v = normalizeArg(v)
+
+	var typ string
+	switch val := v.(type) {
+	case nil:
+		return goldenValue{Type: "null"}, nil
+	case int64:
+		typ = "int64"
+	case float64:
+		typ = "float64"
+	case bool:
+		typ = "bool"
+	case string:
+		typ = "string"
+	case []byte:
+		typ = "bytes"
+	case time.Time:
+		typ = "time"
+		v = val.Format(time.RFC3339Nano)
+	default:
+		// Other values, such as those from pgx, are saved as JSON
+		// and restored as the bytes of the JSON
+		typ = "json"
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return goldenValue{}, fmt.Errorf("encode %T: %w", v, err)
+	}
+
+	return goldenValue{Type: typ, Value: raw}, nil
+}
+
+func (g goldenValue) decode() (any, error) {
+	var err error
+	switch g.Type {
+	case "null":
+		return nil, nil
+	case "int64":
+		var v int64
+		err = json.Unmarshal(g.Value, &v)
+		return v, err
+	case "float64":
+		var v float64
+		err = json.Unmarshal(g.Value, &v)
+		return v, err
+	case "bool":
+		var v bool
+		err = json.Unmarshal(g.Value, &v)
+		return v, err
+	case "string":
+		var v string
+		err = json.Unmarshal(g.Value, &v)
+		return v, err
+	case "bytes":
+		var v []byte
+		err = json.Unmarshal(g.Value, &v)
+		return v, err
+	case "time":
+		var v string
+		if err = json.Unmarshal(g.Value, &v); err != nil {
+			return nil, err
+		}
+		return time.Parse(time.RFC3339Nano, v)
+	case "json":
+		var buf bytes.Buffer
+		err = json.Compact(&buf, g.Value)
+		return buf.Bytes(), err
+	default:
+		return nil, fmt.Errorf("unknown type %q", g.Type)
+	}
+}
+
+// Match implements [ArgMatcher] by comparing the encoded arg
+func (g goldenValue) Match(arg any) bool {
+	encoded, err := encodeValue(arg)
+	if err != nil || encoded.Type != g.Type {
+		return false
+	}
+
+	var want, got bytes.Buffer
+	if json.Compact(&want, g.Value) != nil || json.Compact(&got, encoded.Value) != nil {
+		return false
+	}
+
+	return bytes.Equal(want.Bytes(), got.Bytes())
+}
+
+func (g goldenValue) String() string {
+	return string(g.Value)
+}
+
+// Replay returns a [Mock] that expects the calls saved in the golden file
+// by a [Recorder], and returns the saved results
+func Replay(t testing.TB, file string) *Mock {
+	t.Helper()
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("bobtest: read golden file: %v", err)
+	}
+
+	var calls []goldenCall
+	if err := json.Unmarshal(content, &calls); err != nil {
+		t.Fatalf("bobtest: decode golden file %s: %v", file, err)
+	}
+
+	m := New(t)
+	for i, call := range calls {
+		e := &Expectation{kind: call.Kind, query: NormalizeSQL(call.SQL)}
+		if call.Kind == kindQuery || call.Kind == kindExec {
+			args := make([]any, len(call.Args))
+			for i, arg := range call.Args {
+				args[i] = arg
+			}
+			e.WithArgs(args...)
+		}
+
+		if call.Error != "" {
+			e.WillReturnError(errors.New(call.Error))
+		}
+
+		if call.Result != nil {
+			e.WillReturnResult(*call.Result)
+		}
+
+		if call.Columns != nil {
+			rows := NewRows(call.Columns...)
+			for _, row := range call.Rows {
+				values := make([]any, len(row))
+				for j, val := range row {
+					if values[j], err = val.decode(); err != nil {
+						t.Fatalf("bobtest: decode call %d in %s: %v", i, file, err)
+					}
+				}
+				rows.AddRow(values...)
+			}
+			e.WillReturnRows(rows)
+		}
+
+		m.expect(e)
+	}
+
+	return m
+}
+
+// Record wraps an executor and saves every call to the golden file
+// when the test ends, so that it can be served with [Replay]
+func Record(t testing.TB, exec bob.Executor, file string) *Recorder {
+	t.Helper()
+
+	log := &goldenLog{}
+	t.Cleanup(func() {
+		if err := log.write(file); err != nil {
+			t.Error(err)
+		}
+	})
+
+	return &Recorder{exec: exec, log: log}
+}
+
+// RecordTransactor works like [Record], and also records the transactions
+// started with Begin
+func RecordTransactor[Tx bob.Transaction](t testing.TB, transactor bob.Transactor[Tx], file string) *Recorder {
+	t.Helper()
+
+	r := Record(t, transactor, file)
+	r.begin = func(ctx context.Context) (bob.Transaction, error) {
+		return transactor.Begin(ctx)
+	}
+
+	return r
+}
+
+type goldenLog struct {
+	mu    sync.Mutex
+	calls []goldenCall
+}
+
+func (l *goldenLog) add(call goldenCall, err error) {
+	if err != nil {
+		call.Error = err.Error()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls = append(l.calls, call)
+}
+
+func (l *goldenLog) write(file string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	content, err := json.MarshalIndent(l.calls, "", "  ")
+	if err != nil {
+		return fmt.Errorf("bobtest: encode golden file: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return fmt.Errorf("bobtest: create golden file directory: %w", err)
+	}
+
+	if err := os.WriteFile(file, append(content, '\n'), 0o644); err != nil {
+		return fmt.Errorf("bobtest: write golden file: %w", err)
+	}
+
+	return nil
+}
+
+// Recorder is a [bob.Executor] that records the calls made to the wrapped executor.
+// The calls are written to the golden file when the test ends
+type Recorder struct {
+	exec  bob.Executor
+	begin func(context.Context) (bob.Transaction, error)
+	tx    bob.Transaction
+	log   *goldenLog
+}
+
+func encodeArgs(args []any) ([]goldenValue, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	encoded := make([]goldenValue, len(args))
+	for i, arg := range args {
+		var err error
+		if encoded[i], err = encodeValue(arg); err != nil {
+			return nil, fmt.Errorf("bobtest: arg %d: %w", i+1, err)
+		}
+	}
+
+	return encoded, nil
+}
+
+// ExecContext executes the query and records it with the result
+func (r *Recorder) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	encoded, err := encodeArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	call := goldenCall{Kind: kindExec, SQL: query, Args: encoded}
+
+	res, err := r.exec.ExecContext(ctx, query, args...)
+	if err == nil {
+		call.Result = &result{}
+		call.Result.LastInsertID, _ = res.LastInsertId()
+		call.Result.Affected, _ = res.RowsAffected()
+	}
+
+	r.log.add(call, err)
+	return res, err
+}
+
+// QueryContext executes the query, reads all the rows and records them
+func (r *Recorder) QueryContext(ctx context.Context, query string, args ...any) (scan.Rows, error) {
+	encoded, err := encodeArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	call := goldenCall{Kind: kindQuery, SQL: query, Args: encoded}
+
+	columns, rows, err := r.fetch(ctx, query, args)
+	if err != nil {
+		r.log.add(call, err)
+		return nil, err
+	}
+
+	call.Columns = columns
+	call.Rows = make([][]goldenValue, len(rows))
+	for i, row := range rows {
+		call.Rows[i] = make([]goldenValue, len(row))
+		for j, val := range row {
+			if call.Rows[i][j], err = encodeValue(val); err != nil {
+				return nil, fmt.Errorf("bobtest: column %s: %w", columns[j], err)
+			}
+		}
+	}
+
+	r.log.add(call, nil)
+	return replay.Rows(ctx, columns, rows)
+}
+
+// fetch runs the query and reads all the rows
+func (r *Recorder) fetch(ctx context.Context, query string, args []any) ([]string, [][]any, error) {
+	rows, err := r.exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var values [][]any
+	for rows.Next() {
+		row := make([]any, len(columns))
+		dest := make([]any, len(columns))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return nil, nil, err
+		}
+
+		values = append(values, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return columns, values, rows.Close()
+}
+
+// Begin starts a transaction on the wrapped [bob.Transactor] and records it.
+// The calls made in the transaction are recorded in the same golden file
+func (r *Recorder) Begin(ctx context.Context) (*Recorder, error) {
+	if r.begin == nil {
+		return nil, errors.New("bobtest: the recorded executor cannot begin transactions, use RecordTransactor")
+	}
+
+	tx, err := r.begin(ctx)
+	r.log.add(goldenCall{Kind: kindBegin}, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Recorder{exec: tx, tx: tx, log: r.log}, nil
+}
+
+// Commit commits the transaction and records it
+func (r *Recorder) Commit(ctx context.Context) error {
+	if r.tx == nil {
+		return errNoTransaction
+	}
+
+	err := r.tx.Commit(ctx)
+	r.log.add(goldenCall{Kind: kindCommit}, err)
+	return err
+}
+
+// Rollback rolls back the transaction and records it
+func (r *Recorder) Rollback(ctx context.Context) error {
+	if r.tx == nil {
+		return errNoTransaction
+	}
+
+	err := r.tx.Rollback(ctx)
+	r.log.add(goldenCall{Kind: kindRollback}, err)
+	return err
+}
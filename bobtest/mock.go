@@ -0,0 +1,374 @@
+// Package bobtest provides a mock [bob.Executor] to unit test code that runs
+// queries without a database.
+//
+// Expectations are set on a [Mock] and must be met in order. The SQL is compared
+// after normalizing whitespace, and the args after converting them to driver values.
+// A [Recorder] captures the queries run with a real executor to a golden file
+// that can later be served with [Replay].
+package bobtest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/internal/replay"
+	"github.com/stephenafamo/scan"
+)
+
+var (
+	_ bob.Transactor[*Mock] = (*Mock)(nil)
+	_ bob.Transaction       = (*Mock)(nil)
+)
+
+// ErrUnexpectedCall is returned when a call does not match the next expectation
+var ErrUnexpectedCall = errors.New("bobtest: unexpected call")
+
+var (
+	oneOrMoreSpace   = regexp.MustCompile(`\s+`)
+	spaceAroundPunct = regexp.MustCompile(`\s*([(),])\s*`)
+)
+
+// NormalizeSQL collapses whitespace and removes it around brackets and commas,
+// so that queries that only differ in formatting are equal
+func NormalizeSQL(query string) string {
+	query = strings.TrimSpace(query)
+	query = strings.TrimSuffix(query, ";")
+	query = oneOrMoreSpace.ReplaceAllLiteralString(query, " ")
+	query = spaceAroundPunct.ReplaceAllString(query, "$1")
+	return strings.TrimSpace(query)
+}
+
+// ArgMatcher can be passed to [Expectation.WithArgs] to match an arg
+// with something other than equality
+type ArgMatcher interface {
+	Match(arg any) bool
+}
+
+// AnyArg matches any arg
+func AnyArg() ArgMatcher {
+	return anyArg{}
+}
+
+type anyArg struct{}
+
+func (anyArg) Match(any) bool { return true }
+func (anyArg) String() string { return "<any>" }
+
+// normalizeArg converts the arg to a driver value, e.g. an int to an int64
+func normalizeArg(arg any) any {
+	if v, err := driver.DefaultParameterConverter.ConvertValue(arg); err == nil {
+		return v
+	}
+
+	return arg
+}
+
+func argsEqual(expected, got any) bool {
+	if m, ok := expected.(ArgMatcher); ok {
+		return m.Match(got)
+	}
+
+	expected, got = normalizeArg(expected), normalizeArg(got)
+	if e, ok := expected.(time.Time); ok {
+		g, ok := got.(time.Time)
+		return ok && e.Equal(g)
+	}
+
+	return reflect.DeepEqual(expected, got)
+}
+
+// Rows are the canned rows returned by an expected query
+type Rows struct {
+	columns []string
+	rows    [][]any
+}
+
+// NewRows returns empty rows with the given columns
+func NewRows(columns ...string) *Rows {
+	return &Rows{columns: columns}
+}
+
+// AddRow adds a row with a value for each column.
+// The values are scanned with the same conversions as database/sql
+func (r *Rows) AddRow(values ...any) *Rows {
+	r.rows = append(r.rows, values)
+	return r
+}
+
+// NewResult returns a result for [Expectation.WillReturnResult]
+func NewResult(lastInsertID, rowsAffected int64) sql.Result {
+	return result{LastInsertID: lastInsertID, Affected: rowsAffected}
+}
+
+type result struct {
+	LastInsertID int64 `json:"last_insert_id"`
+	Affected     int64 `json:"rows_affected"`
+}
+
+func (r result) LastInsertId() (int64, error) { return r.LastInsertID, nil }
+func (r result) RowsAffected() (int64, error) { return r.Affected, nil }
+
+type callKind string
+
+const (
+	kindQuery    callKind = "query"
+	kindExec     callKind = "exec"
+	kindBegin    callKind = "begin"
+	kindCommit   callKind = "commit"
+	kindRollback callKind = "rollback"
+)
+
+// Expectation is a call that the [Mock] expects
+type Expectation struct {
+	kind      callKind
+	query     string
+	args      []any
+	checkArgs bool
+	rows      *Rows
+	result    sql.Result
+	err       error
+}
+
+// WithArgs sets the args that the query is expected to be run with.
+// If it is not called, the args are not checked
+func (e *Expectation) WithArgs(args ...any) *Expectation {
+	e.args = args
+	e.checkArgs = true
+	return e
+}
+
+// WillReturnRows sets the rows returned by an expected query
+func (e *Expectation) WillReturnRows(rows *Rows) *Expectation {
+	e.rows = rows
+	return e
+}
+
+// WillReturnResult sets the result of an expected exec
+func (e *Expectation) WillReturnResult(result sql.Result) *Expectation {
+	e.result = result
+	return e
+}
+
+// WillReturnError makes the expected call return the error
+func (e *Expectation) WillReturnError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+func (e *Expectation) String() string {
+	if e.query == "" {
+		return string(e.kind)
+	}
+
+	if !e.checkArgs {
+		return fmt.Sprintf("%s %q", e.kind, e.query)
+	}
+
+	return fmt.Sprintf("%s %q with args %v", e.kind, e.query, e.args)
+}
+
+func (e *Expectation) match(kind callKind, query string, args []any) error {
+	if e.kind != kind {
+		return fmt.Errorf("expected %s", e)
+	}
+
+	if e.query != "" && e.query != NormalizeSQL(query) {
+		return fmt.Errorf("expected %s", e)
+	}
+
+	if !e.checkArgs {
+		return nil
+	}
+
+	if len(e.args) != len(args) {
+		return fmt.Errorf("expected %s", e)
+	}
+
+	for i := range args {
+		if !argsEqual(e.args[i], args[i]) {
+			return fmt.Errorf("expected %s", e)
+		}
+	}
+
+	return nil
+}
+
+// New returns a [Mock] that reports an error to t if any expectation
+// was not met when the test ends
+func New(t testing.TB) *Mock {
+	t.Helper()
+
+	m := &Mock{t: t}
+	t.Cleanup(func() {
+		if err := m.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	return m
+}
+
+// Mock is a [bob.Executor], [bob.Transactor] and [bob.Transaction] that
+// returns canned results for the expected calls.
+// Begin returns the same Mock, so the calls in a transaction are expected
+// on it as well. It is safe for concurrent use
+type Mock struct {
+	t            testing.TB
+	mu           sync.Mutex
+	expectations []*Expectation
+	next         int
+}
+
+func (m *Mock) expect(e *Expectation) *Expectation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.expectations = append(m.expectations, e)
+	return e
+}
+
+// ExpectQuery expects QueryContext to be called with the query
+func (m *Mock) ExpectQuery(query string) *Expectation {
+	return m.expect(&Expectation{kind: kindQuery, query: NormalizeSQL(query)})
+}
+
+// ExpectExec expects ExecContext to be called with the query
+func (m *Mock) ExpectExec(query string) *Expectation {
+	return m.expect(&Expectation{kind: kindExec, query: NormalizeSQL(query)})
+}
+
+// ExpectBegin expects a transaction to be started
+func (m *Mock) ExpectBegin() *Expectation {
+	return m.expect(&Expectation{kind: kindBegin})
+}
+
+// ExpectCommit expects the transaction to be committed
+func (m *Mock) ExpectCommit() *Expectation {
+	return m.expect(&Expectation{kind: kindCommit})
+}
+
+// ExpectRollback expects the transaction to be rolled back
+func (m *Mock) ExpectRollback() *Expectation {
+	return m.expect(&Expectation{kind: kindRollback})
+}
+
+// ExpectationsWereMet returns an error listing the expected calls that were not made
+func (m *Mock) ExpectationsWereMet() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.next >= len(m.expectations) {
+		return nil
+	}
+
+	unmet := make([]string, 0, len(m.expectations)-m.next)
+	for _, e := range m.expectations[m.next:] {
+		unmet = append(unmet, "\t"+e.String())
+	}
+
+	return fmt.Errorf("bobtest: %d expectations were not met:\n%s", len(unmet), strings.Join(unmet, "\n"))
+}
+
+// call returns the next expectation if it matches the call
+func (m *Mock) call(kind callKind, query string, args []any) (*Expectation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	got := string(kind)
+	if query != "" {
+		got = fmt.Sprintf("%s %q with args %v", kind, NormalizeSQL(query), args)
+	}
+
+	if m.next >= len(m.expectations) {
+		err := fmt.Errorf("%w: %s, no more calls were expected", ErrUnexpectedCall, got)
+		m.t.Error(err)
+		return nil, err
+	}
+
+	e := m.expectations[m.next]
+	if matchErr := e.match(kind, query, args); matchErr != nil {
+		err := fmt.Errorf("%w: %s, %w", ErrUnexpectedCall, got, matchErr)
+		m.t.Error(err)
+		return nil, err
+	}
+
+	m.next++
+	return e, nil
+}
+
+// QueryContext returns the rows of the next expectation
+func (m *Mock) QueryContext(ctx context.Context, query string, args ...any) (scan.Rows, error) {
+	e, err := m.call(kindQuery, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	rows := e.rows
+	if rows == nil {
+		rows = NewRows()
+	}
+
+	return replay.Rows(ctx, rows.columns, rows.rows)
+}
+
+// ExecContext returns the result of the next expectation
+func (m *Mock) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	e, err := m.call(kindExec, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	if e.result == nil {
+		return result{}, nil
+	}
+
+	return e.result, nil
+}
+
+// Begin checks that a transaction was expected and returns the same Mock
+func (m *Mock) Begin(ctx context.Context) (*Mock, error) {
+	e, err := m.call(kindBegin, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, e.err
+}
+
+// Commit checks that a commit was expected
+func (m *Mock) Commit(ctx context.Context) error {
+	e, err := m.call(kindCommit, "", nil)
+	if err != nil {
+		return err
+	}
+
+	return e.err
+}
+
+// Rollback checks that a rollback was expected
+func (m *Mock) Rollback(ctx context.Context) error {
+	e, err := m.call(kindRollback, "", nil)
+	if err != nil {
+		return err
+	}
+
+	return e.err
+}
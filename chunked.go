@@ -0,0 +1,227 @@
+package bob
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/stephenafamo/scan"
+)
+
+var (
+	// ErrInvalidChunkSize is returned when the chunk size given to
+	// [CursorChunked] or [EachChunked] is less than 1
+	ErrInvalidChunkSize = errors.New("chunk size must be greater than zero")
+
+	// ErrChunkedLoadOnTransaction is returned by [CursorChunked] and [EachChunked]
+	// when the loaders or AfterQueryHook would run on a transaction
+	// while the rows of the query are read from a transaction
+	ErrChunkedLoadOnTransaction = errors.New("chunked loaders cannot run on a transaction while the rows of a transaction are open")
+)
+
+// CursorChunked works like [Cursor], but reads the rows in chunks of up to size rows.
+// Instead of running the loaders and AfterQueryHook for every row, they are run once
+// for each chunk, with the rows transformed by Tr as [Allx] does.
+// This keeps the batching of eager loading while only holding one chunk in memory
+//
+// The query is run on exec, and the loaders and AfterQueryHook on loadExec
+// while the rows of the query are still open. loadExec must be able to run queries
+// at the same time as exec, so it cannot be the same transaction or single connection.
+// Both can be the same pool, such as [DB], if it has a connection to spare for the loaders.
+// If loadExec is nil, exec is used.
+//
+// If both are a [Transaction], such as the same transaction or a nested one,
+// [ErrChunkedLoadOnTransaction] is returned when the query has loaders or a hook,
+// since the loaders would have to run on the connection that is reading the rows
+func CursorChunked[Tr Transformer[T, V], T, V any](ctx context.Context, exec, loadExec Executor, q Query, m scan.Mapper[T], size int) (scan.ICursor[T], error) {
+	if size < 1 {
+		return nil, ErrInvalidChunkSize
+	}
+
+	if loadExec == nil {
+		loadExec = exec
+	}
+
+	var typedSlice V
+	_, isTransformedHookable := any(typedSlice).(HookableType)
+	_, isSingleHookable := any(*new(T)).(HookableType)
+	// If the transformed type is not hookable, but the single type is,
+	// return an error
+	if !isTransformedHookable && isSingleHookable {
+		return nil, ErrHookableTypeMismatch
+	}
+
+	var loaders []Loader
+	if l, ok := q.(Loadable); ok {
+		loaders = l.GetLoaders()
+	}
+
+	if (len(loaders) > 0 || isTransformedHookable) && bothTransactions(exec, loadExec) {
+		return nil, ErrChunkedLoadOnTransaction
+	}
+
+	var err error
+
+	if h, ok := q.(HookableQuery); ok {
+		ctx, err = h.RunHooks(ctx, exec)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ctx = withQueryType(ctx, q.Type())
+
+	sql, args, err := Build(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if l, ok := q.(MapperModder); ok {
+		if loaders := l.GetMapperMods(); len(loaders) > 0 {
+			m = scan.Mod(m, loaders...)
+		}
+	}
+
+	cursor, err := scan.Cursor(ctx, timeoutExecutor(q, exec), m, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chunkedCursor[Tr, T, V]{
+		ctx:     LoaderContext(ctx, q),
+		exec:    loadExec,
+		typ:     q.Type(),
+		size:    size,
+		loaders: loaders,
+		cursor:  cursor,
+	}, nil
+}
+
+// bothTransactions reports whether both executors are a [Transaction]
+func bothTransactions(exec, loadExec Executor) bool {
+	_, execTx := exec.(Transaction)
+	_, loadTx := loadExec.(Transaction)
+	return execTx && loadTx
+}
+
+// EachChunked works like [Each], but reads the rows in chunks of up to size rows
+// and runs the loaders and AfterQueryHook on loadExec once for each chunk. See [CursorChunked]
+func EachChunked[Tr Transformer[T, V], T, V any](ctx context.Context, exec, loadExec Executor, q Query, m scan.Mapper[T], size int) (func(func(T, error) bool), error) {
+	cursor, err := CursorChunked[Tr](ctx, exec, loadExec, q, m, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(T, error) bool) {
+		defer cursor.Close()
+
+		for cursor.Next() {
+			if !yield(cursor.Get()) {
+				return
+			}
+		}
+
+		if err := cursor.Err(); err != nil {
+			yield(*new(T), err)
+		}
+	}, nil
+}
+
+type chunkedCursor[Tr Transformer[T, V], T, V any] struct {
+	// the context to run the loaders and hooks with, see [LoaderContext]
+	ctx context.Context
+	// the executor to run the loaders and hooks on
+	exec    Executor
+	typ     QueryType
+	size    int
+	loaders []Loader
+	cursor  scan.ICursor[T]
+
+	chunk []T
+	idx   int
+	done  bool
+	err   error
+}
+
+func (c *chunkedCursor[Tr, T, V]) Close() error {
+	return c.cursor.Close()
+}
+
+func (c *chunkedCursor[Tr, T, V]) Next() bool {
+	if c.idx < len(c.chunk) {
+		c.idx++
+		return true
+	}
+
+	if c.done || c.err != nil {
+		return false
+	}
+
+	c.err = c.fill()
+	if c.err != nil || len(c.chunk) == 0 {
+		return false
+	}
+
+	c.idx = 1
+	return true
+}
+
+// fill reads the next chunk of rows and runs the loaders and hooks on it
+func (c *chunkedCursor[Tr, T, V]) fill() error {
+	// a new slice is used, since the rows of the previous chunk may still be in use
+	c.chunk = make([]T, 0, c.size)
+	c.idx = 0
+
+	for len(c.chunk) < c.size {
+		if !c.cursor.Next() {
+			c.done = true
+			if err := c.cursor.Err(); err != nil {
+				return err
+			}
+			break
+		}
+
+		row, err := c.cursor.Get()
+		if err != nil {
+			return err
+		}
+
+		c.chunk = append(c.chunk, row)
+	}
+
+	if len(c.chunk) == 0 {
+		return nil
+	}
+
+	var transformer Tr
+	typedSlice, err := transformer.TransformScanned(c.chunk)
+	if err != nil {
+		return err
+	}
+
+	for _, loader := range c.loaders {
+		if err := loader.Load(c.ctx, c.exec, typedSlice); err != nil {
+			return err
+		}
+	}
+
+	if h, ok := any(typedSlice).(HookableType); ok {
+		if err := h.AfterQueryHook(c.ctx, c.exec, c.typ); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *chunkedCursor[Tr, T, V]) Get() (T, error) {
+	if c.idx == 0 || c.idx > len(c.chunk) {
+		return *new(T), sql.ErrNoRows
+	}
+
+	return c.chunk[c.idx-1], nil
+}
+
+func (c *chunkedCursor[Tr, T, V]) Err() error {
+	return c.err
+}
@@ -0,0 +1,213 @@
+package bob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stephenafamo/scan"
+	_ "modernc.org/sqlite"
+)
+
+type chunkedRow struct {
+	ID     int64 `db:"id"`
+	Loaded bool  `db:"-"`
+}
+
+type chunkedRows []*chunkedRow
+
+func (r chunkedRows) AfterQueryHook(ctx context.Context, exec Executor, typ QueryType) error {
+	hooked, _ := ctx.Value(chunkedEventsKey{}).(*[]string)
+	*hooked = append(*hooked, "hook")
+	return nil
+}
+
+type chunkedEventsKey struct{}
+
+// loadedQuery records the size of every chunk that its loader is run on
+type loadedQuery struct {
+	BaseQuery[Expression]
+	events *[]string
+}
+
+func (q loadedQuery) GetLoaders() []Loader {
+	return []Loader{LoaderFunc(func(ctx context.Context, exec Executor, retrieved any) error {
+		rows, ok := retrieved.(chunkedRows)
+		if !ok {
+			return errors.New("expected a chunk of rows")
+		}
+
+		// query the executor while the rows of the chunked query are open
+		ids := make([]any, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ID
+		}
+		loaded, err := scan.All(ctx, exec, scan.SingleColumnMapper[int64],
+			"SELECT id FROM items WHERE id IN (?"+strings.Repeat(", ?", len(ids)-1)+")", ids...)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			row.Loaded = slices.Contains(loaded, row.ID)
+		}
+
+		*q.events = append(*q.events, fmt.Sprintf("load %d", len(rows)))
+		return nil
+	})}
+}
+
+func TestChunked(t *testing.T) {
+	db, err := Open("sqlite", filepath.Join(t.TempDir(), "chunked.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// one connection for the rows and one for the loaders
+	db.SetMaxOpenConns(2)
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE items (id INTEGER PRIMARY KEY);
+		INSERT INTO items (id) VALUES (1), (2), (3), (4), (5);
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	var events []string
+	ctx = context.WithValue(ctx, chunkedEventsKey{}, &events)
+	q := loadedQuery{
+		BaseQuery: BaseQuery[Expression]{
+			Expression: ExpressionFunc(func(_ context.Context, w io.StringWriter, _ Dialect, _ int) ([]any, error) {
+				w.WriteString("SELECT id FROM items ORDER BY id")
+				return nil, nil
+			}),
+			QueryType: QueryTypeSelect,
+		},
+		events: &events,
+	}
+
+	t.Run("cursor", func(t *testing.T) {
+		events = nil
+		cursor, err := CursorChunked[SliceTransformer[*chunkedRow, chunkedRows]](ctx, db, db, q, scan.StructMapper[*chunkedRow](), 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cursor.Close()
+
+		var ids []int64
+		for cursor.Next() {
+			row, err := cursor.Get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !row.Loaded {
+				t.Fatalf("row %d was not loaded", row.ID)
+			}
+			ids = append(ids, row.ID)
+			events = append(events, "row")
+		}
+		if err := cursor.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		if diff := cmp.Diff([]int64{1, 2, 3, 4, 5}, ids); diff != "" {
+			t.Fatal(diff)
+		}
+
+		if diff := cmp.Diff([]string{
+			"load 2", "hook", "row", "row",
+			"load 2", "hook", "row", "row",
+			"load 1", "hook", "row",
+		}, events); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("each", func(t *testing.T) {
+		events = nil
+		each, err := EachChunked[SliceTransformer[*chunkedRow, chunkedRows]](ctx, db, db, q, scan.StructMapper[*chunkedRow](), 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for row, err := range each {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if row.ID == 4 {
+				break
+			}
+		}
+
+		if diff := cmp.Diff([]string{"load 3", "hook", "load 2", "hook"}, events); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("same transaction", func(t *testing.T) {
+		tx, err := db.Begin(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback(ctx)
+
+		_, err = CursorChunked[SliceTransformer[*chunkedRow, chunkedRows]](ctx, tx, tx, q, scan.StructMapper[*chunkedRow](), 2)
+		if !errors.Is(err, ErrChunkedLoadOnTransaction) {
+			t.Fatalf("expected ErrChunkedLoadOnTransaction, got %v", err)
+		}
+
+		nested, err := tx.Begin(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer nested.Rollback(ctx)
+
+		_, err = EachChunked[SliceTransformer[*chunkedRow, chunkedRows]](ctx, tx, nested, q, scan.StructMapper[*chunkedRow](), 2)
+		if !errors.Is(err, ErrChunkedLoadOnTransaction) {
+			t.Fatalf("expected ErrChunkedLoadOnTransaction, got %v", err)
+		}
+	})
+
+	t.Run("transaction with separate loader executor", func(t *testing.T) {
+		events = nil
+		tx, err := db.Begin(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback(ctx)
+
+		each, err := EachChunked[SliceTransformer[*chunkedRow, chunkedRows]](ctx, tx, db, q, scan.StructMapper[*chunkedRow](), 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var count int
+		for row, err := range each {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !row.Loaded {
+				t.Fatalf("row %d was not loaded", row.ID)
+			}
+			count++
+		}
+
+		if count != 5 {
+			t.Fatalf("expected 5 rows, got %d", count)
+		}
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		_, err := CursorChunked[SliceTransformer[*chunkedRow, chunkedRows]](ctx, db, db, q, scan.StructMapper[*chunkedRow](), 0)
+		if !errors.Is(err, ErrInvalidChunkSize) {
+			t.Fatalf("expected ErrInvalidChunkSize, got %v", err)
+		}
+	})
+}
@@ -42,6 +42,9 @@ func (f Lock) WriteSQL(ctx context.Context, w io.StringWriter, d bob.Dialect, st
 		return nil, nil
 	}
 
+	// the rows are locked, so the query has to run on the primary
+	bob.MarkLocking(ctx)
+
 	w.WriteString("FOR ")
 	w.WriteString(fmt.Sprintf("%s ", f.Strength))
 
@@ -2,11 +2,20 @@ package clause
 
 import (
 	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
 	"io"
+	"slices"
+	"strings"
 
 	"github.com/stephenafamo/bob"
 )
 
+// ErrChunkNotSplit is returned when writing more rows than the ChunkSize of [Values].
+// Only queries executed by the ORM are split into chunks
+var ErrChunkNotSplit = errors.New("the rows must be split into chunks")
+
 type Values struct {
 	// Query takes the highest priority
 	// If present, will attempt to insert from this query
@@ -15,6 +24,24 @@ type Values struct {
 	// for multiple inserts
 	// each sub-slice is one set of values
 	Vals []Value
+
+	// ChunkSize is the maximum number of rows inserted by a single statement.
+	// When executed by the ORM, the rows are split into multiple statements.
+	// Writing more rows than ChunkSize in a single statement returns [ErrChunkNotSplit].
+	// 0 means no limit
+	ChunkSize int
+
+	// ParamLimit is the maximum number of parameters in a single statement.
+	// The ORM splits the rows so that each statement stays below it,
+	// counting the parameters of the rows and of the rest of the statement.
+	// 0 means no limit
+	ParamLimit int
+
+	// SizeLimit is the maximum size in bytes of a single statement and its parameters,
+	// such as the max_allowed_packet of MySQL.
+	// The ORM splits the rows so that each statement stays below it, see [Values.Chunks].
+	// 0 means no limit
+	SizeLimit int
 }
 
 type Value []bob.Expression
@@ -31,12 +58,113 @@ func (v *Values) AppendValues(vals ...bob.Expression) {
 	v.Vals = append(v.Vals, vals)
 }
 
+func (v *Values) SetChunkSize(size int) {
+	v.ChunkSize = size
+}
+
+// SetRows replaces the rows to insert
+func (v *Values) SetRows(rows ...Value) {
+	v.Vals = rows
+}
+
+func (v *Values) SetParamLimit(limit int) {
+	v.ParamLimit = limit
+}
+
+func (v *Values) SetSizeLimit(limit int) {
+	v.SizeLimit = limit
+}
+
+// Chunks splits the rows into groups so that each statement inserts at most ChunkSize rows,
+// and stays within ParamLimit parameters and SizeLimit bytes.
+// Every row is written with the dialect to count its parameters and size, and restSQL
+// and restArgs are the rest of the statement built without the rows, so that the parameters
+// of clauses such as ON CONFLICT or RETURNING are counted too.
+// The size is an estimate of the SQL and the parameters as sent to the database.
+// A row that does not fit in a statement on its own is inserted in its own chunk.
+// It returns nil if the rows do not need to be split
+func (v Values) Chunks(ctx context.Context, d bob.Dialect, restSQL string, restArgs []any) ([][]Value, error) {
+	if v.Query != nil || len(v.Vals) < 2 {
+		return nil, nil
+	}
+
+	if v.ParamLimit <= 0 && v.SizeLimit <= 0 {
+		if v.ChunkSize <= 0 || len(v.Vals) <= v.ChunkSize {
+			return nil, nil
+		}
+
+		return slices.Collect(slices.Chunk(v.Vals, v.ChunkSize)), nil
+	}
+
+	restParams, restSize := len(restArgs), len(restSQL)
+	for _, arg := range restArgs {
+		restSize += argSize(arg)
+	}
+
+	var chunks [][]Value
+	var b strings.Builder
+	start, params, size := 0, restParams, restSize
+
+	for i, row := range v.Vals {
+		b.Reset()
+		args, err := row.WriteSQL(ctx, &b, d, params+1)
+		if err != nil {
+			return nil, err
+		}
+
+		rowSize := b.Len() + len(", ")
+		for _, arg := range args {
+			rowSize += argSize(arg)
+		}
+
+		full := (v.ChunkSize > 0 && i-start >= v.ChunkSize) ||
+			(v.ParamLimit > 0 && params+len(args) > v.ParamLimit) ||
+			(v.SizeLimit > 0 && size+rowSize > v.SizeLimit)
+		if full && i > start {
+			chunks = append(chunks, v.Vals[start:i])
+			start, params, size = i, restParams, restSize
+		}
+
+		params += len(args)
+		size += rowSize
+	}
+
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+
+	return append(chunks, v.Vals[start:]), nil
+}
+
+// argSize estimates the size of a parameter as sent to the database
+func argSize(arg any) int {
+	// the length or type of the value
+	const overhead = 9
+
+	if val, err := driver.DefaultParameterConverter.ConvertValue(arg); err == nil {
+		arg = val
+	}
+
+	switch arg := arg.(type) {
+	case string:
+		return len(arg) + overhead
+	case []byte:
+		return len(arg) + overhead
+	default:
+		return len(fmt.Sprint(arg)) + overhead
+	}
+}
+
 func (v Values) WriteSQL(ctx context.Context, w io.StringWriter, d bob.Dialect, start int) ([]any, error) {
 	// If a query is present, use it
 	if v.Query != nil {
 		return v.Query.WriteQuery(ctx, w, start)
 	}
 
+	if v.ChunkSize > 0 && len(v.Vals) > v.ChunkSize {
+		return nil, fmt.Errorf("%w: %d rows with a chunk size of %d", ErrChunkNotSplit, len(v.Vals), v.ChunkSize)
+	}
+
 	// If values are present, use them
 	if len(v.Vals) > 0 {
 		return bob.ExpressSlice(ctx, w, d, start, v.Vals, "VALUES ", ", ", "")
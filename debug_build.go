@@ -0,0 +1,104 @@
+package bob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// LiteralDialect is a [Dialect] that can write values as SQL literals.
+// It is used by [BuildDebug] to inline args
+type LiteralDialect interface {
+	Dialect
+	// WriteLiteral writes the value as a literal, escaped for the dialect.
+	// It returns an error if the value cannot be written as a literal
+	WriteLiteral(w io.StringWriter, value any) error
+}
+
+// DebugOptions configures [BuildDebug]
+type DebugOptions struct {
+	// Indent is used for each level of indentation. Defaults to two spaces
+	Indent string
+	// InlineArgs replaces the placeholders with the args written as literals.
+	// The dialect of the query must be a [LiteralDialect]
+	InlineArgs bool
+}
+
+// DebugSQL is a query formatted for reading by [BuildDebug].
+// It is NOT meant to be executed, and cannot be passed to bob to execute.
+// Use [DebugSQL.String] to print it
+type DebugSQL struct {
+	sql string
+}
+
+// String returns the formatted query
+func (d DebugSQL) String() string {
+	return d.sql
+}
+
+// BuildDebug builds the query and formats it over multiple indented lines.
+// If [DebugOptions.InlineArgs] is set, the args are written in place of their placeholders.
+// The result is only meant to be read, use [Build] to get a query to execute
+func BuildDebug(ctx context.Context, q Query, opts DebugOptions) (DebugSQL, error) {
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+
+	b := &bytes.Buffer{}
+	args, err := q.WriteQuery(ctx, b, 1)
+	if err != nil {
+		return DebugSQL{}, err
+	}
+
+	var d Dialect
+	if dq, ok := q.(interface{ GetDialect() Dialect }); ok {
+		d = dq.GetDialect()
+	}
+
+	if !opts.InlineArgs || len(args) == 0 {
+		var p *placeholders
+		if d != nil {
+			p = newPlaceholders(d)
+		}
+		return DebugSQL{sql: prettyPrinter{indent: opts.Indent}.print(tokenize(b.String(), p))}, nil
+	}
+
+	ld, ok := d.(LiteralDialect)
+	if !ok {
+		return DebugSQL{}, errors.New("bob: cannot inline args, the dialect of the query cannot write literals")
+	}
+
+	p := newPlaceholders(ld)
+	if p == nil {
+		return DebugSQL{}, errors.New("bob: cannot inline args, unknown placeholder format")
+	}
+
+	tokens := tokenize(b.String(), p)
+	for i, t := range tokens {
+		if t.kind != tokenPlaceholder {
+			continue
+		}
+
+		if t.arg < 1 || t.arg > len(args) {
+			return DebugSQL{}, fmt.Errorf("bob: cannot inline args, no arg for placeholder %s", t.text)
+		}
+
+		arg := args[t.arg-1]
+		// named args are only bound when the query is executed
+		if _, ok := arg.(namedArg); ok {
+			continue
+		}
+
+		lit := &bytes.Buffer{}
+		if err := ld.WriteLiteral(lit, arg); err != nil {
+			return DebugSQL{}, fmt.Errorf("bob: inline arg %d: %w", t.arg, err)
+		}
+
+		tokens[i].kind = tokenQuoted
+		tokens[i].text = lit.String()
+	}
+
+	return DebugSQL{sql: prettyPrinter{indent: opts.Indent}.print(tokens)}, nil
+}
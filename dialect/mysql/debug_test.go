@@ -0,0 +1,96 @@
+package mysql_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/mysql"
+	"github.com/stephenafamo/bob/dialect/mysql/dialect"
+	"github.com/stephenafamo/bob/dialect/mysql/im"
+	"github.com/stephenafamo/bob/dialect/mysql/sm"
+)
+
+func TestWriteLiteral(t *testing.T) {
+	tests := map[string]struct {
+		value    any
+		expected string
+	}{
+		"null":    {value: nil, expected: "NULL"},
+		"bool":    {value: false, expected: "FALSE"},
+		"int":     {value: int8(-3), expected: "-3"},
+		"string":  {value: "it's \"a\"\\\n\x00\x1a", expected: `'it\'s \"a\"\\\n\0\Z'`},
+		"bytes":   {value: []byte("ab"), expected: "X'6162'"},
+		"time":    {value: time.Date(2024, 1, 2, 4, 4, 5, 0, time.FixedZone("", 3600)), expected: "'2024-01-02 03:04:05'"},
+		"pointer": {value: ptr("x"), expected: "'x'"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b strings.Builder
+			if err := dialect.Dialect.WriteLiteral(&b, tc.value); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expected, b.String()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestBuildDebug(t *testing.T) {
+	tests := map[string]struct {
+		query    bob.Query
+		expected string
+	}{
+		"select": {
+			query: mysql.Select(
+				sm.Columns("u.id", "o.total"),
+				sm.From("users").As("u"),
+				sm.LeftJoin("orders").As("o").On(mysql.Quote("o", "user_id").EQ(mysql.Quote("u", "id"))),
+				sm.Where(mysql.Quote("u", "name").EQ(mysql.Arg(`it's`))),
+				sm.Where(mysql.Quote("o", "total").GT(mysql.Arg(10))),
+				sm.ForUpdate(),
+			),
+			expected: "SELECT\n" +
+				"  u.id,\n" +
+				"  o.total\n" +
+				"FROM\n" +
+				"  users AS `u`\n" +
+				"  LEFT JOIN orders AS `o` ON (`o`.`user_id` = `u`.`id`)\n" +
+				"WHERE\n" +
+				"  (`u`.`name` = 'it\\'s')\n" +
+				"  AND (`o`.`total` > 10)\n" +
+				"FOR UPDATE",
+		},
+		"insert on duplicate key update": {
+			query: mysql.Insert(
+				im.Into("users", "id", "name"),
+				im.Values(mysql.Arg(1, "alice")),
+				im.OnDuplicateKeyUpdate(im.UpdateWithValues("name")),
+			),
+			expected: "INSERT INTO users(`id`, `name`)\n" +
+				"VALUES\n" +
+				"  (1, 'alice')\n" +
+				"ON DUPLICATE KEY UPDATE\n" +
+				"  `name` = VALUES(`name`)",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := bob.BuildDebug(context.Background(), tc.query, bob.DebugOptions{InlineArgs: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expected, got.String()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package dialect
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"strings"
 
 	"github.com/stephenafamo/bob"
@@ -24,3 +26,37 @@ func (x Expression) String() string {
 	x.WriteSQL(context.Background(), &w, Dialect, 1) //nolint:errcheck
 	return w.String()
 }
+
+// -> path
+// Gets the value at the JSON path, such as '$.a'.
+// MySQL only accepts a string literal path, so it is written as one
+func (x Expression) JSONGet(path string) Expression {
+	return x.OP("->", jsonPath(path))
+}
+
+// ->> path
+// Gets the unquoted value at the JSON path, such as '$.a'.
+// MySQL only accepts a string literal path, so it is written as one
+func (x Expression) JSONGetText(path string) Expression {
+	return x.OP("->>", jsonPath(path))
+}
+
+// jsonPath is a JSON path written as a string literal
+type jsonPath string
+
+// ShouldOmitParens reports that jsonPath is a literal
+func (jsonPath) ShouldOmitParens() bool { return true }
+
+// WriteSQL writes the path with single quotes doubled, which is read the same way
+// whether or not NO_BACKSLASH_ESCAPES is set.
+// Backslashes are read differently depending on that mode, so paths with one are rejected
+func (p jsonPath) WriteSQL(ctx context.Context, w io.StringWriter, d bob.Dialect, start int) ([]any, error) {
+	if strings.Contains(string(p), `\`) {
+		return nil, fmt.Errorf("JSON path %q cannot contain a backslash", string(p))
+	}
+
+	w.WriteString("'")
+	w.WriteString(strings.ReplaceAll(string(p), "'", "''"))
+	w.WriteString("'")
+	return nil, nil
+}
@@ -2,12 +2,15 @@ package dialect
 
 import (
 	"context"
+	"errors"
 	"io"
 
 	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/clause"
 )
 
+var ErrSoftDeleteMultiTable = errors.New("soft delete is only supported for single table DELETE queries without partitions")
+
 // Trying to represent the query structure as documented in
 // https://dev.mysql.com/doc/refman/8.0/en/delete.html
 type DeleteQuery struct {
@@ -25,6 +28,12 @@ type DeleteQuery struct {
 	clause.Where
 	clause.OrderBy
 	clause.Limit
+
+	// SoftDelete holds the assignments used to soft delete rows.
+	// If it is not empty, the query is written as an UPDATE of the
+	// rows that would otherwise have been deleted
+	SoftDelete clause.Set
+
 	bob.Load
 	bob.EmbeddedHook
 	bob.ContextualModdable[*DeleteQuery]
@@ -48,6 +57,13 @@ func (d DeleteQuery) WriteSQL(ctx context.Context, w io.StringWriter, dl bob.Dia
 		return nil, err
 	}
 
+	if len(d.SoftDelete.Set) > 0 {
+		if len(d.Tables) != 1 || len(d.Partitions) > 0 || d.TableRef.Expression != nil {
+			return nil, ErrSoftDeleteMultiTable
+		}
+		return d.softDeleteQuery().WriteSQL(ctx, w, dl, start)
+	}
+
 	withArgs, err := bob.ExpressIf(ctx, w, dl, start+len(args), d.With,
 		len(d.With.CTEs) > 0, "\n", "")
 	if err != nil {
@@ -111,3 +127,17 @@ func (d DeleteQuery) WriteSQL(ctx context.Context, w io.StringWriter, dl bob.Dia
 
 	return args, nil
 }
+
+// softDeleteQuery returns the UPDATE query used to soft delete the rows
+// matched by the DELETE query
+func (d DeleteQuery) softDeleteQuery() UpdateQuery {
+	return UpdateQuery{
+		hints:    d.hints,
+		With:     d.With,
+		TableRef: d.Tables[0],
+		Set:      d.SoftDelete,
+		Where:    d.Where,
+		OrderBy:  d.OrderBy,
+		Limit:    d.Limit,
+	}
+}
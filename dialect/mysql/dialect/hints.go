@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/stephenafamo/bob"
 )
@@ -56,6 +57,20 @@ func MaxExecutionTime[Q hintable](n int) bob.Mod[Q] {
 	})
 }
 
+// StatementTimeout adds the MAX_EXECUTION_TIME hint with the timeout in milliseconds
+// and sets the timeout of the query, so that errors from the hint are returned
+// as a [*bob.StatementTimeoutError]
+func StatementTimeout[Q interface {
+	hintable
+	SetStatementTimeout(time.Duration)
+}](d time.Duration) bob.Mod[Q] {
+	hint := fmt.Sprintf("MAX_EXECUTION_TIME(%d)", max(d.Milliseconds(), 1))
+	return bob.ModFunc[Q](func(q Q) {
+		q.AppendHint(hint)
+		q.SetStatementTimeout(d)
+	})
+}
+
 func ResourceGroup[Q hintable](name string) bob.Mod[Q] {
 	hint := fmt.Sprintf("RESOURCE_GROUP(%s)", name)
 	return bob.ModFunc[Q](func(q Q) {
@@ -0,0 +1,73 @@
+package dialect
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteLiteral writes the value as a MySQL literal.
+// Strings are escaped with backslashes and byte slices are written as hex X'...' literals.
+// It is used to print queries for debugging and must not be used to build queries to execute
+func (d dialect) WriteLiteral(w io.StringWriter, value any) error {
+	v, err := driver.DefaultParameterConverter.ConvertValue(value)
+	if err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case nil:
+		w.WriteString("NULL")
+	case bool:
+		if v {
+			w.WriteString("TRUE")
+		} else {
+			w.WriteString("FALSE")
+		}
+	case int64:
+		w.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("cannot write %v as a literal", v)
+		}
+		w.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	case string:
+		writeString(w, v)
+	case []byte:
+		w.WriteString("X'")
+		w.WriteString(hex.EncodeToString(v))
+		w.WriteString("'")
+	case time.Time:
+		w.WriteString("'")
+		w.WriteString(v.UTC().Format("2006-01-02 15:04:05.999999"))
+		w.WriteString("'")
+	default:
+		return fmt.Errorf("cannot write %T as a literal", v)
+	}
+
+	return nil
+}
+
+//nolint:gochecknoglobals
+var stringEscaper = strings.NewReplacer(
+	"\x00", `\0`,
+	"'", `\'`,
+	`"`, `\"`,
+	"\b", `\b`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+	"\x1a", `\Z`,
+	`\`, `\\`,
+)
+
+func writeString(w io.StringWriter, s string) {
+	w.WriteString("'")
+	w.WriteString(stringEscaper.Replace(s))
+	w.WriteString("'")
+}
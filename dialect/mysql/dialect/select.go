@@ -30,6 +30,7 @@ type SelectQuery struct {
 	clause.Locks
 	bob.Load
 	bob.EmbeddedHook
+	bob.StatementTimeout
 	bob.ContextualModdable[*SelectQuery]
 
 	CombinedOrder  clause.OrderBy
@@ -0,0 +1,64 @@
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/scan"
+)
+
+// errMaxExecutionTime is the number of the error returned when a query
+// is stopped by the MAX_EXECUTION_TIME hint (ER_QUERY_TIMEOUT)
+const errMaxExecutionTime = 3024
+
+// WithStatementTimeout returns an executor that returns a [*bob.StatementTimeoutError]
+// when a query is stopped by the MAX_EXECUTION_TIME hint, which is a [*mysql.MySQLError]
+// with the number 3024. The hint itself is added to the query by [StatementTimeout]
+func (d dialect) WithStatementTimeout(exec bob.Executor, timeout time.Duration) bob.Executor {
+	return timeoutExecutor{exec: exec, timeout: timeout}
+}
+
+type timeoutExecutor struct {
+	exec    bob.Executor
+	timeout time.Duration
+}
+
+func (t timeoutExecutor) convert(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errMaxExecutionTime {
+		return &bob.StatementTimeoutError{Timeout: t.timeout, Err: err}
+	}
+
+	return err
+}
+
+func (t timeoutExecutor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	result, err := t.exec.ExecContext(ctx, query, args...)
+	return result, t.convert(err)
+}
+
+func (t timeoutExecutor) QueryContext(ctx context.Context, query string, args ...any) (scan.Rows, error) {
+	rows, err := t.exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, t.convert(err)
+	}
+
+	return timeoutRows{Rows: rows, t: t}, nil
+}
+
+type timeoutRows struct {
+	scan.Rows
+	t timeoutExecutor
+}
+
+func (r timeoutRows) Err() error {
+	return r.t.convert(r.Rows.Err())
+}
+
+func (r timeoutRows) Close() error {
+	return r.t.convert(r.Rows.Close())
+}
@@ -46,6 +46,23 @@ func Rows(rows ...[]bob.Expression) bob.Mod[*dialect.InsertQuery] {
 	return mods.Rows[*dialect.InsertQuery](rows)
 }
 
+// ChunkSize sets the maximum number of rows inserted by a single statement.
+// When the query is executed through a generated table, larger inserts are split
+// into multiple statements run in a single transaction. Inserts through a generated table
+// are already split to stay below the parameter limit of the database, so this is only needed
+// for a smaller limit. Executing more rows without a generated table returns [clause.ErrChunkNotSplit].
+func ChunkSize(rows int) bob.Mod[*dialect.InsertQuery] {
+	return mods.ChunkSize[*dialect.InsertQuery](rows)
+}
+
+// SizeLimit sets the maximum size in bytes of a single statement and its parameters.
+// Inserts through a generated table are split to stay below it, which defaults to 4MiB,
+// the smallest default max_allowed_packet of MySQL. Set it to the max_allowed_packet of the server
+// to send fewer statements.
+func SizeLimit(bytes int) bob.Mod[*dialect.InsertQuery] {
+	return mods.SizeLimit[*dialect.InsertQuery](bytes)
+}
+
 // Insert from a query
 func Query(q bob.Query) bob.Mod[*dialect.InsertQuery] {
 	return bob.ModFunc[*dialect.InsertQuery](func(i *dialect.InsertQuery) {
@@ -0,0 +1,40 @@
+package mysql
+
+import (
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/mysql/dialect"
+)
+
+// Extracts the values at the paths from the JSON document.
+// With more than one path, the values are returned as a JSON array
+// SQL: JSON_EXTRACT(`data`, ?, ?)
+// Go: mysql.JSONExtract(mysql.Quote("data"), mysql.Arg("$.a"), mysql.Arg("$.b"))
+func JSONExtract(doc bob.Expression, paths ...bob.Expression) *dialect.Function {
+	args := make([]any, 0, len(paths)+1)
+	args = append(args, doc)
+	for _, p := range paths {
+		args = append(args, p)
+	}
+
+	return dialect.NewFunction("JSON_EXTRACT", args...)
+}
+
+// Unquotes a JSON value and returns it as a string
+// SQL: JSON_UNQUOTE(JSON_EXTRACT(`data`, ?))
+// Go: mysql.JSONUnquote(mysql.JSONExtract(mysql.Quote("data"), mysql.Arg("$.a")))
+func JSONUnquote(json bob.Expression) *dialect.Function {
+	return dialect.NewFunction("JSON_UNQUOTE", json)
+}
+
+// Checks if the candidate is contained in the target JSON document,
+// or at the path in the target if one is given
+// SQL: JSON_CONTAINS(`data`, ?, ?)
+// Go: mysql.JSONContains(mysql.Quote("data"), mysql.Arg(`"go"`), mysql.Arg("$.tags"))
+func JSONContains(target, candidate bob.Expression, path ...bob.Expression) *dialect.Function {
+	args := []any{target, candidate}
+	for _, p := range path {
+		args = append(args, p)
+	}
+
+	return dialect.NewFunction("JSON_CONTAINS", args...)
+}
@@ -1,7 +1,11 @@
 package mysql_test
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/antlr4-go/antlr/v4"
 	"github.com/stephenafamo/bob"
@@ -11,6 +15,7 @@ import (
 	"github.com/stephenafamo/bob/dialect/mysql/sm"
 	"github.com/stephenafamo/bob/dialect/mysql/wm"
 	testutils "github.com/stephenafamo/bob/test/utils"
+	"github.com/stephenafamo/scan"
 	mysqlparser "github.com/stephenafamo/sqlparser/mysql"
 )
 
@@ -180,6 +185,14 @@ func TestSelect(t *testing.T) {
 			),
 			ExpectedSQL: `SELECT /*+ BKA(` + "`my table`" + `) */ id FROM t`,
 		},
+		"statement timeout": {
+			Query: mysql.Select(
+				sm.Columns("id"),
+				sm.From("t"),
+				sm.StatementTimeout(1500*time.Millisecond),
+			),
+			ExpectedSQL: `SELECT /*+ MAX_EXECUTION_TIME(1500) */ id FROM t`,
+		},
 		"from partition with spaced names": {
 			Query: mysql.Select(
 				sm.Columns("id"),
@@ -209,11 +222,65 @@ func TestSelect(t *testing.T) {
 				SELECT id
 				FROM orders USE INDEX (` + "`idx a`" + `, ` + "`idx b`" + `)`,
 		},
+		"json operators": {
+			Query: mysql.Select(
+				sm.Columns(
+					mysql.Quote("data").JSONGet("$.address"),
+					mysql.JSONUnquote(mysql.JSONExtract(mysql.Quote("data"), mysql.Arg("$.a"), mysql.Arg("$.b"))),
+				),
+				sm.From("users"),
+				sm.Where(mysql.Quote("data").JSONGetText(`$."it's"`).EQ(mysql.Arg("yes"))),
+				sm.Where(mysql.JSONContains(mysql.Quote("data"), mysql.Arg(`"go"`), mysql.Arg("$.tags"))),
+			),
+			ExpectedSQL: "SELECT (`data` -> '$.address'), JSON_UNQUOTE(JSON_EXTRACT(`data`, ?, ?))" +
+				" FROM users" +
+				" WHERE ((`data` ->> '$.\"it''s\"') = ?)" +
+				" AND JSON_CONTAINS(`data`, ?, ?)",
+			ExpectedArgs: []any{"$.a", "$.b", "yes", `"go"`, "$.tags"},
+		},
 	}
 
 	testutils.RunTests(t, examples, formatter)
 }
 
+func TestJSONPathBackslash(t *testing.T) {
+	_, _, err := bob.Build(context.Background(), mysql.Select(
+		sm.Columns(mysql.Quote("data").JSONGet(`$."a\\"`)),
+		sm.From("users"),
+	))
+	if err == nil {
+		t.Fatal("expected an error for a JSON path with a backslash")
+	}
+}
+
+// lockingExecutor records whether each query locks the rows it reads
+type lockingExecutor struct {
+	locking *[]bool
+}
+
+func (e lockingExecutor) ExecContext(ctx context.Context, _ string, _ ...any) (sql.Result, error) {
+	*e.locking = append(*e.locking, bob.IsLocking(ctx))
+	return nil, errors.New("not executed")
+}
+
+func (e lockingExecutor) QueryContext(ctx context.Context, _ string, _ ...any) (scan.Rows, error) {
+	*e.locking = append(*e.locking, bob.IsLocking(ctx))
+	return nil, errors.New("not executed")
+}
+
+func TestSelectLocking(t *testing.T) {
+	var locking []bool
+	exec := lockingExecutor{locking: &locking}
+
+	_, _ = bob.All(context.Background(), exec, mysql.Select(sm.From("users")), scan.SingleColumnMapper[int])
+	_, _ = bob.All(context.Background(), exec, mysql.Select(sm.From("users"), sm.ForUpdate()), scan.SingleColumnMapper[int])
+	_, _ = bob.All(context.Background(), exec, mysql.Select(sm.From("users"), sm.ForShare()), scan.SingleColumnMapper[int])
+
+	if len(locking) != 3 || locking[0] || !locking[1] || !locking[2] {
+		t.Fatalf("expected only the queries with a locking clause to lock, got %v", locking)
+	}
+}
+
 func formatter(s string) (string, error) {
 	input := antlr.NewInputStream(s)
 	lexer := mysqlparser.NewMySqlLexer(input)
@@ -1,6 +1,8 @@
 package sm
 
 import (
+	"time"
+
 	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/dialect/mysql/dialect"
 )
@@ -17,6 +19,13 @@ func MaxExecutionTime(n int) bob.Mod[*dialect.SelectQuery] {
 	return dialect.MaxExecutionTime[*dialect.SelectQuery](n)
 }
 
+// StatementTimeout makes the database cancel the query if it runs for longer than d.
+// It adds the MAX_EXECUTION_TIME optimizer hint, which MySQL only supports for SELECT queries.
+// A query that is cancelled returns a [*bob.StatementTimeoutError]
+func StatementTimeout(d time.Duration) bob.Mod[*dialect.SelectQuery] {
+	return dialect.StatementTimeout[*dialect.SelectQuery](d)
+}
+
 func ResourceGroup(name string) bob.Mod[*dialect.SelectQuery] {
 	return dialect.ResourceGroup[*dialect.SelectQuery](name)
 }
@@ -26,6 +26,14 @@ import (
 
 type setter[T any] = orm.Setter[T, *dialect.InsertQuery, *dialect.UpdateQuery]
 
+// maxParams is the maximum number of parameters in a single statement in MySQL.
+// Inserts with more are split into chunks, see [clause.Values.ParamLimit]
+const maxParams = 65535
+
+// maxPacket is the default max_allowed_packet of MySQL 5.7, the smallest of the supported versions.
+// Inserts larger than it are split into chunks, see [clause.Values.SizeLimit]
+const maxPacket = 4 << 20
+
 func NewTable[T any, Tset setter[T], C bob.Expression](tableName string, columns C, uniques ...[]string) *Table[T, []T, Tset, C] {
 	return NewTablex[T, []T, Tset](tableName, columns, nil, uniques...)
 }
@@ -91,31 +99,133 @@ func (t *Table[T, Tslice, Tset, C]) Insert(queryMods ...bob.Mod[*dialect.InsertQ
 		ExecQuery: orm.ExecQuery[*dialect.InsertQuery]{
 			BaseQuery: Insert(im.Into(t.NameExpr(), t.nonGeneratedCols...)),
 			Hooks:     &t.InsertQueryHooks,
+			Table:     t.alias,
 		},
 		table: t,
 	}
 
+	q.Expression.SetParamLimit(maxParams)
+	q.Expression.SetSizeLimit(maxPacket)
 	q.Apply(queryMods...)
 
 	return q
 }
 
+// Upsert starts an insert query for the setters that updates the existing row
+// instead when an inserted row conflicts on a unique key.
+// Only the columns set in the setters are updated, so all setters must set
+// the same columns, otherwise the query fails with [orm.ErrMixedUpsertSetters].
+// The new values are referenced with the row alias if one is set with [im.As]
+// (MySQL 8.0.19 and later), otherwise with the VALUES() function.
+// conflictCols should be a unique key of the table, it is used to retrieve the rows.
+// If the table has a tenant column, it is never updated, and conflictCols must include it,
+// otherwise the query fails with [orm.ErrUpsertWithoutTenant], since the row that
+// conflicts could belong to another tenant
+func (t *Table[T, Tslice, Tset, C]) Upsert(conflictCols []string, setters ...Tset) *insertQuery[T, Tslice, Tset, C] {
+	q := t.Insert(bob.ToMods(setters...))
+	q.upsert = true
+	q.conflictIdx = slices.IndexFunc(t.uniqueIdx, func(idx []int) bool {
+		return len(idx) == len(conflictCols) && !slices.ContainsFunc(idx, func(i int) bool {
+			return !slices.Contains(conflictCols, t.setterMapping.All[i])
+		})
+	})
+
+	updateCols, err := orm.UpsertColumns(slices.Concat(conflictCols, []string{t.tenantCol}), setters...)
+	if t.tenantCol != "" && !slices.Contains(conflictCols, t.tenantCol) {
+		err = orm.ErrUpsertWithoutTenant
+	}
+
+	q.Expression.AppendContextualModFunc(
+		func(ctx context.Context, q *dialect.InsertQuery) (context.Context, error) {
+			if err != nil {
+				return ctx, err
+			}
+
+			if len(updateCols) == 0 && len(conflictCols) > 0 {
+				// Nothing to update, but the conflict is still ignored
+				q.DuplicateKeyUpdate.Set = append(q.DuplicateKeyUpdate.Set,
+					dialect.Set{Col: conflictCols[0], Val: Quote(conflictCols[0])})
+				return ctx, nil
+			}
+
+			if q.RowAlias != "" {
+				im.OnDuplicateKeyUpdate(im.UpdateWithAlias(q.RowAlias, updateCols...)).Apply(q)
+			} else {
+				im.OnDuplicateKeyUpdate(im.UpdateWithValues(updateCols...)).Apply(q)
+			}
+			return ctx, nil
+		},
+	)
+
+	return q
+}
+
 // Starts an update query for this table
 func (t *Table[T, Tslice, Tset, C]) Update(queryMods ...bob.Mod[*dialect.UpdateQuery]) *orm.ExecQuery[*dialect.UpdateQuery] {
 	q := &orm.ExecQuery[*dialect.UpdateQuery]{
 		BaseQuery: Update(um.Table(t.NameAsExpr())),
 		Hooks:     &t.UpdateQueryHooks,
+		Table:     t.alias,
+	}
+	if t.tenantCol != "" {
+		q.Expression.AppendContextualMod(orm.TenantMod[*dialect.UpdateQuery](t.alias, t.tenantCol))
 	}
+
 	q.Apply(queryMods...)
 
 	return q
 }
 
+// WithSoftDelete sets the column used to soft delete rows.
+// Delete sets the column to the current time instead of removing the rows,
+// and queries exclude rows where it is not NULL.
+// Use HardDelete to remove rows permanently
+func (t *Table[T, Tslice, Tset, C]) WithSoftDelete(column string) *Table[T, Tslice, Tset, C] {
+	t.softDeleteCol = column
+	return t
+}
+
+// WithTenant sets the column that holds the tenant of each row.
+// Queries, updates and deletes only affect the rows of the tenant set with [orm.WithTenant],
+// and generated setters insert the rows with it.
+// They fail with [orm.ErrMissingTenant] if there is none
+func (t *Table[T, Tslice, Tset, C]) WithTenant(column string) *Table[T, Tslice, Tset, C] {
+	t.tenantCol = column
+	return t
+}
+
 // Starts a delete query for this table
+// If the table has a soft delete column, the matching rows that are not
+// deleted yet are updated to set the column to the current time instead, as given by orm.Now
 func (t *Table[T, Tslice, Tset, C]) Delete(queryMods ...bob.Mod[*dialect.DeleteQuery]) *orm.ExecQuery[*dialect.DeleteQuery] {
+	q := t.HardDelete()
+
+	if t.softDeleteCol != "" {
+		q.Expression.AppendContextualModFunc(
+			func(ctx context.Context, q *dialect.DeleteQuery) (context.Context, error) {
+				q.SoftDelete.AppendSet(Quote(t.softDeleteCol).EQ(Arg(orm.Now(ctx))))
+				q.AppendWhere(Quote(t.alias, t.softDeleteCol).IsNull())
+				return ctx, nil
+			},
+		)
+	}
+
+	q.Apply(queryMods...)
+
+	return q
+}
+
+// Starts a Delete query for this table that removes the rows
+// even if the table has a soft delete column
+func (t *Table[T, Tslice, Tset, C]) HardDelete(queryMods ...bob.Mod[*dialect.DeleteQuery]) *orm.ExecQuery[*dialect.DeleteQuery] {
 	q := &orm.ExecQuery[*dialect.DeleteQuery]{
 		BaseQuery: Delete(dm.From(t.NameAsExpr())),
 		Hooks:     &t.DeleteQueryHooks,
+		Table:     t.alias,
+	}
+
+	if t.tenantCol != "" {
+		q.Expression.AppendContextualMod(orm.TenantMod[*dialect.DeleteQuery](t.alias, t.tenantCol))
 	}
 
 	q.Apply(queryMods...)
@@ -126,6 +236,11 @@ func (t *Table[T, Tslice, Tset, C]) Delete(queryMods ...bob.Mod[*dialect.DeleteQ
 type insertQuery[T any, Ts ~[]T, Tset setter[T], C bob.Expression] struct {
 	orm.ExecQuery[*dialect.InsertQuery]
 	table *Table[T, Ts, Tset, C]
+
+	// upsert is true if the query was started with [Table.Upsert]
+	upsert bool
+	// the unique index used to retrieve upserted rows, -1 if any can be used
+	conflictIdx int
 }
 
 // Insert One Row
@@ -175,11 +290,11 @@ func (t *insertQuery[T, Tslice, Tset, C]) retrievable() error {
 		return fmt.Errorf("inserting from query: %w", orm.ErrCannotRetrieveRow)
 	}
 
-	if len(t.Expression.DuplicateKeyUpdate.Set) > 0 {
+	if len(t.Expression.DuplicateKeyUpdate.Set) > 0 && !t.upsert {
 		return fmt.Errorf("has duplicate key update: %w", orm.ErrCannotRetrieveRow)
 	}
 
-	if t.table.autoIncrementColumn != "" {
+	if t.table.autoIncrementColumn != "" && !t.upsert {
 		return nil
 	}
 
@@ -253,7 +368,7 @@ func (t *insertQuery[T, Tslice, Tset, C]) getInserted(vals []clause.Value, resul
 	idArgs := make([][]bob.Expression, len(t.table.uniqueIdx))
 
 	for i, val := range vals {
-		if t.table.autoIncrementColumn != "" && len(t.Expression.DuplicateKeyUpdate.Set) == 0 {
+		if t.table.autoIncrementColumn != "" && len(t.Expression.DuplicateKeyUpdate.Set) == 0 && !t.upsert {
 			lastID, err := results[i].LastInsertId()
 			if err != nil {
 				return nil, err
@@ -347,6 +462,10 @@ func isDefaultOrNull(w *bytes.Buffer, e bob.Expression) bool {
 func (t *insertQuery[T, Tslice, Tset, C]) uniqueSet(w *bytes.Buffer, row []bob.Expression) (int, []bob.Expression) {
 Outer:
 	for whichUnique, unique := range t.table.uniqueIdx {
+		if t.upsert && t.conflictIdx >= 0 && whichUnique != t.conflictIdx {
+			continue
+		}
+
 		colVals := make([]bob.Expression, 0, len(unique))
 
 		for _, uniqueCol := range unique {
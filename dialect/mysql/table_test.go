@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"errors"
 	"reflect"
 	"testing"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/clause"
 	"github.com/stephenafamo/bob/dialect/mysql/dialect"
+	"github.com/stephenafamo/bob/dialect/mysql/im"
 	"github.com/stephenafamo/bob/expr"
 	"github.com/stephenafamo/bob/internal"
 	"github.com/stephenafamo/bob/orm"
@@ -284,3 +286,195 @@ func TestIsDefaultOrNull(t *testing.T) {
 		})
 	}
 }
+
+type Page struct {
+	ID    int    `db:"id,pk"`
+	Slug  string `db:"slug"`
+	Title string `db:"title"`
+}
+
+type PageSetter struct {
+	ID    *int    `db:"id,pk"`
+	Slug  *string `db:"slug"`
+	Title *string `db:"title"`
+}
+
+func (s *PageSetter) SetColumns() []string {
+	var cols []string
+	if s.ID != nil {
+		cols = append(cols, "id")
+	}
+	if s.Slug != nil {
+		cols = append(cols, "slug")
+	}
+	if s.Title != nil {
+		cols = append(cols, "title")
+	}
+	return cols
+}
+
+func (s *PageSetter) Apply(q *dialect.InsertQuery) {
+	vals := make([]bob.Expression, 0, 3)
+	for _, v := range []any{s.ID, s.Slug, s.Title} {
+		if reflect.ValueOf(v).IsNil() {
+			vals = append(vals, Raw("DEFAULT"))
+			continue
+		}
+		vals = append(vals, Arg(v))
+	}
+	q.AppendValues(vals...)
+}
+
+func (s *PageSetter) UpdateMod() bob.Mod[*dialect.UpdateQuery] {
+	return bob.ModFunc[*dialect.UpdateQuery](func(*dialect.UpdateQuery) {})
+}
+
+var pagesTable = NewTablex[*Page, []*Page, *PageSetter](
+	"pages", expr.ColsForStruct[Page]("pages"), nil, []string{"id"}, []string{"slug"},
+)
+
+func TestUpsert(t *testing.T) {
+	slug, title := internal.Pointer("home"), internal.Pointer("Home")
+
+	cases := map[string]struct {
+		mods        []bob.Mod[*dialect.InsertQuery]
+		setter      *PageSetter
+		expectedSQL string
+		args        []any
+	}{
+		"values": {
+			setter:      &PageSetter{Slug: slug, Title: title},
+			expectedSQL: "INSERT INTO `pages` (`id`, `slug`, `title`) VALUES (DEFAULT, ?, ?) ON DUPLICATE KEY UPDATE `title` = VALUES(`title`)",
+			args:        []any{slug, title},
+		},
+		"row alias": {
+			mods:        []bob.Mod[*dialect.InsertQuery]{im.As("new")},
+			setter:      &PageSetter{Slug: slug, Title: title},
+			expectedSQL: "INSERT INTO `pages` (`id`, `slug`, `title`) VALUES (DEFAULT, ?, ?) AS `new` ON DUPLICATE KEY UPDATE `title` = `new`.`title`",
+			args:        []any{slug, title},
+		},
+		"only conflict columns": {
+			setter:      &PageSetter{Slug: slug},
+			expectedSQL: "INSERT INTO `pages` (`id`, `slug`, `title`) VALUES (DEFAULT, ?, DEFAULT) ON DUPLICATE KEY UPDATE `slug` = `slug`",
+			args:        []any{slug},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			q := pagesTable.Upsert([]string{"slug"}, tc.setter)
+			q.Apply(tc.mods...)
+
+			if q.conflictIdx != 1 {
+				t.Fatalf("expected the slug index to be used, got %d", q.conflictIdx)
+			}
+
+			gotSQL, args, err := bob.Build(t.Context(), q)
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+
+			if diff, err := testutils.QueryDiff(tc.expectedSQL, gotSQL, nil); err != nil {
+				t.Fatalf("QueryDiff: %v", err)
+			} else if diff != "" {
+				t.Fatalf("sql diff: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.args, args); diff != "" {
+				t.Fatalf("args diff: %s", diff)
+			}
+
+			// the rows are retrieved with the conflict columns
+			// even when another unique index is set
+			q.Expression.Values.Vals[0][0] = Arg(10)
+			query, err := q.getInserted(q.Expression.Values.Vals, nil, nil)
+			if err != nil {
+				t.Fatalf("getInserted: %v", err)
+			}
+
+			gotSQL, args, err = bob.Build(t.Context(), query)
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+
+			expectedSQL := "SELECT `pages`.`id` AS `id`, `pages`.`slug` AS `slug`, `pages`.`title` AS `title` FROM `pages` WHERE ((`slug` IN ((?))))"
+			if diff, err := testutils.QueryDiff(expectedSQL, gotSQL, nil); err != nil {
+				t.Fatalf("QueryDiff: %v", err)
+			} else if diff != "" {
+				t.Fatalf("sql diff: %s", diff)
+			}
+
+			if diff := cmp.Diff([]any{slug}, args); diff != "" {
+				t.Fatalf("args diff: %s", diff)
+			}
+		})
+	}
+}
+
+func TestUpsertMixedSetters(t *testing.T) {
+	slug, title := internal.Pointer("home"), internal.Pointer("Home")
+
+	q := pagesTable.Upsert([]string{"slug"}, &PageSetter{Slug: slug, Title: title}, &PageSetter{Slug: slug})
+	if _, _, err := bob.Build(t.Context(), q); !errors.Is(err, orm.ErrMixedUpsertSetters) {
+		t.Fatalf("expected ErrMixedUpsertSetters, got %v", err)
+	}
+}
+
+func TestUpsertTenant(t *testing.T) {
+	// the title is used as the tenant of the pages
+	table := NewTablex[*Page, []*Page, *PageSetter](
+		"pages", expr.ColsForStruct[Page]("pages"), nil, []string{"id"}, []string{"slug", "title"},
+	).WithTenant("title")
+
+	slug, title := internal.Pointer("home"), internal.Pointer("Home")
+	ctx := orm.WithAllTenants(t.Context())
+
+	q := table.Upsert([]string{"slug"}, &PageSetter{Slug: slug, Title: title})
+	if _, _, err := bob.Build(ctx, q); !errors.Is(err, orm.ErrUpsertWithoutTenant) {
+		t.Fatalf("expected ErrUpsertWithoutTenant, got %v", err)
+	}
+
+	q = table.Upsert([]string{"slug", "title"}, &PageSetter{Slug: slug, Title: title})
+	gotSQL, _, err := bob.Build(ctx, q)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+
+	expectedSQL := "INSERT INTO `pages` (`id`, `slug`, `title`) VALUES (DEFAULT, ?, ?) ON DUPLICATE KEY UPDATE `slug` = `slug`"
+	if diff, err := testutils.QueryDiff(expectedSQL, gotSQL, nil); err != nil {
+		t.Fatalf("QueryDiff: %v", err)
+	} else if diff != "" {
+		t.Fatalf("sql diff: %s", diff)
+	}
+}
+
+func TestInsertSizeLimit(t *testing.T) {
+	ctx := context.Background()
+	title := string(bytes.Repeat([]byte("x"), 100))
+	setters := make([]bob.Mod[*dialect.InsertQuery], 3)
+	for i := range setters {
+		setters[i] = &PageSetter{Title: &title}
+	}
+
+	query := pagesTable.Insert(setters...)
+	if query.Expression.SizeLimit != maxPacket {
+		t.Fatalf("expected the default size limit %d, got %d", maxPacket, query.Expression.SizeLimit)
+	}
+
+	chunks, err := query.Expression.Chunks(ctx, dialect.Dialect, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chunks != nil {
+		t.Fatalf("expected the rows to fit in a statement, got %d chunks", len(chunks))
+	}
+
+	query.Apply(im.SizeLimit(300))
+	chunks, err = query.Expression.Chunks(ctx, dialect.Dialect, "INSERT INTO `pages` (`id`, `slug`, `title`) VALUES ", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 3 {
+		t.Fatalf("expected a chunk for each row, got %d", len(chunks))
+	}
+}
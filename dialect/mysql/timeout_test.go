@@ -0,0 +1,45 @@
+package mysql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/bobtest"
+	"github.com/stephenafamo/bob/dialect/mysql"
+	"github.com/stephenafamo/bob/dialect/mysql/sm"
+	"github.com/stephenafamo/scan"
+)
+
+func TestStatementTimeoutError(t *testing.T) {
+	driverErr := &mysqldriver.MySQLError{
+		Number:   3024,
+		SQLState: [5]byte{'H', 'Y', '0', '0', '0'},
+		Message:  "Query execution was interrupted, maximum statement execution time exceeded",
+	}
+
+	mock := bobtest.New(t)
+	mock.ExpectQuery("SELECT /*+ MAX_EXECUTION_TIME(10) */ * FROM users").WillReturnError(driverErr)
+
+	q := mysql.Select(sm.From("users"), sm.StatementTimeout(10*time.Millisecond))
+	_, err := bob.All(context.Background(), mock, q, scan.SingleColumnMapper[int64])
+	if !errors.Is(err, bob.ErrStatementTimeout) || !errors.Is(err, driverErr) {
+		t.Fatalf("expected a statement timeout, got %v", err)
+	}
+}
+
+func TestStatementTimeoutOtherError(t *testing.T) {
+	driverErr := errors.New("Error 3024 (HY000): Query execution was interrupted, maximum statement execution time exceeded")
+
+	mock := bobtest.New(t)
+	mock.ExpectQuery("SELECT /*+ MAX_EXECUTION_TIME(10) */ * FROM users").WillReturnError(driverErr)
+
+	q := mysql.Select(sm.From("users"), sm.StatementTimeout(10*time.Millisecond))
+	_, err := bob.All(context.Background(), mock, q, scan.SingleColumnMapper[int64])
+	if errors.Is(err, bob.ErrStatementTimeout) || !errors.Is(err, driverErr) {
+		t.Fatalf("expected the driver error as is, got %v", err)
+	}
+}
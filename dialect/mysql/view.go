@@ -5,6 +5,7 @@ import (
 	"reflect"
 
 	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/clause"
 	"github.com/stephenafamo/bob/dialect/mysql/dialect"
 	"github.com/stephenafamo/bob/dialect/mysql/sm"
 	"github.com/stephenafamo/bob/expr"
@@ -49,6 +50,9 @@ type View[T any, Tslice ~[]T, C bob.Expression] struct {
 
 	Columns C
 
+	softDeleteCol string
+	tenantCol     string
+
 	AfterSelectHooks bob.Hooks[Tslice, bob.SkipModelHooksKey]
 	SelectQueryHooks bob.Hooks[*dialect.SelectQuery, bob.SkipQueryHooksKey]
 }
@@ -82,6 +86,32 @@ func (v *View[T, Tslice, C]) ColumnsExpr() expr.ColumnsExpr {
 	return v.allCols
 }
 
+// WithSoftDelete sets the column used to soft delete rows.
+// Queries exclude rows where the column is not NULL,
+// unless the context was modified with [orm.WithDeleted]
+func (v *View[T, Tslice, C]) WithSoftDelete(column string) *View[T, Tslice, C] {
+	v.softDeleteCol = column
+	return v
+}
+
+// SoftDeleteColumn returns the column used to soft delete rows, if any
+func (v *View[T, Tslice, C]) SoftDeleteColumn() string {
+	return v.softDeleteCol
+}
+
+// WithTenant sets the column that holds the tenant of each row.
+// Queries only return the rows of the tenant set with [orm.WithTenant],
+// and fail with [orm.ErrMissingTenant] if there is none
+func (v *View[T, Tslice, C]) WithTenant(column string) *View[T, Tslice, C] {
+	v.tenantCol = column
+	return v
+}
+
+// TenantColumn returns the column that holds the tenant of each row, if any
+func (v *View[T, Tslice, C]) TenantColumn() string {
+	return v.tenantCol
+}
+
 // Query starts a select query on the view
 func (v *View[T, Tslice, C]) Query(queryMods ...bob.Mod[*dialect.SelectQuery]) *ViewQuery[T, Tslice] {
 	q := &ViewQuery[T, Tslice]{
@@ -89,6 +119,7 @@ func (v *View[T, Tslice, C]) Query(queryMods ...bob.Mod[*dialect.SelectQuery]) *
 			ExecQuery: orm.ExecQuery[*dialect.SelectQuery]{
 				BaseQuery: Select(sm.From(v.NameAsExpr())),
 				Hooks:     &v.SelectQueryHooks,
+				Table:     v.alias,
 			},
 			Scanner: v.scanner,
 		},
@@ -103,6 +134,21 @@ func (v *View[T, Tslice, C]) Query(queryMods ...bob.Mod[*dialect.SelectQuery]) *
 		},
 	)
 
+	if v.softDeleteCol != "" {
+		q.BaseQuery.Expression.AppendContextualModFunc(
+			func(ctx context.Context, q *dialect.SelectQuery) (context.Context, error) {
+				if !orm.DeletedIncluded(ctx) {
+					q.AppendWhere(Quote(v.alias, v.softDeleteCol).IsNull())
+				}
+				return ctx, nil
+			},
+		)
+	}
+
+	if v.tenantCol != "" {
+		q.BaseQuery.Expression.AppendContextualMod(orm.TenantMod[*dialect.SelectQuery](v.alias, v.tenantCol))
+	}
+
 	q.Apply(queryMods...)
 
 	return q
@@ -127,6 +173,19 @@ func (v *ViewQuery[T, Tslice]) Exists(ctx context.Context, exec bob.Executor) (b
 	return count > 0, err
 }
 
+// Paginate returns at most limit rows after the given cursor using keyset pagination.
+// The ORDER BY clause of the query determines the order of the rows and must be
+// unique and non-null, e.g. sm.OrderBy("created_at").Desc(), sm.OrderBy("id").Desc().
+// The predicate is expanded to an OR chain since MySQL does not use indexes
+// for row value comparisons such as (a, b) > (?, ?).
+// Pass the returned Next or Prev cursor to get the following or previous page.
+func (v *ViewQuery[T, Tslice]) Paginate(ctx context.Context, exec bob.Executor, after orm.Cursor, limit int) (orm.Page[T, Tslice], error) {
+	return orm.Paginate(ctx, exec, v.Query, orm.KeysetOptions[*dialect.SelectQuery]{
+		OrderBy:   func(q *dialect.SelectQuery) *clause.OrderBy { return &q.OrderBy },
+		RowValues: false,
+	}, after, limit)
+}
+
 // asCountQuery clones and rewrites an existing query to a count query
 func asCountQuery(query bob.BaseQuery[*dialect.SelectQuery]) bob.BaseQuery[*dialect.SelectQuery] {
 	// clone the original query, so it's not being modified silently
@@ -0,0 +1,65 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/bobtest"
+	"github.com/stephenafamo/bob/dialect/mysql/sm"
+	"github.com/stephenafamo/bob/expr"
+)
+
+type someStruct struct {
+	ID    int64  `db:"id,pk"`
+	Name  string `db:"name"`
+	Email string `db:"email"`
+}
+
+var someStructView = NewView[*someStruct, bob.Expression]("some_struct", expr.ColsForStruct[someStruct]("some_struct"))
+
+func TestViewQueryPaginate(t *testing.T) {
+	ctx := context.Background()
+	cols := []string{"id", "name", "email", "__bob_cursor_0", "__bob_cursor_1"}
+
+	mock := bobtest.New(t)
+	mock.ExpectQuery("SELECT `some_struct`.`id` AS `id`, `some_struct`.`name` AS `name`, `some_struct`.`email` AS `email`," +
+		" `name` AS `__bob_cursor_0`, `id` AS `__bob_cursor_1`" +
+		" FROM `some_struct`" +
+		" ORDER BY `name` COLLATE `utf8mb4_bin` DESC, `id` DESC" +
+		" LIMIT 3").
+		WillReturnRows(bobtest.NewRows(cols...).
+			AddRow(int64(2), "c", "x", "c", int64(2)).
+			AddRow(int64(3), "b", "y", "b", int64(3)).
+			AddRow(int64(1), "a", "x", "a", int64(1)))
+	mock.ExpectQuery("SELECT `some_struct`.`id` AS `id`, `some_struct`.`name` AS `name`, `some_struct`.`email` AS `email`,"+
+		" `name` AS `__bob_cursor_0`, `id` AS `__bob_cursor_1`"+
+		" FROM `some_struct`"+
+		" WHERE ((`name` COLLATE `utf8mb4_bin` < ?) OR (`name` COLLATE `utf8mb4_bin` = ? AND `id` < ?))"+
+		" ORDER BY `name` COLLATE `utf8mb4_bin` DESC, `id` DESC"+
+		" LIMIT 3").
+		WithArgs("b", "b", int64(3)).
+		WillReturnRows(bobtest.NewRows(cols...).
+			AddRow(int64(1), "a", "x", "a", int64(1)))
+
+	q := someStructView.Query(
+		sm.OrderBy(Quote("name")).Collate("utf8mb4_bin").Desc(),
+		sm.OrderBy(Quote("id")).Desc(),
+	)
+
+	page, err := q.Paginate(ctx, mock, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 2 || page.Next == "" {
+		t.Fatalf("unexpected first page: %d items, next %q", len(page.Items), page.Next)
+	}
+
+	page, err = q.Paginate(ctx, mock, page.Next, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 1 || page.Items[0].ID != 1 {
+		t.Fatalf("unexpected second page: %v", page.Items)
+	}
+}
@@ -0,0 +1,64 @@
+package psql
+
+import (
+	"context"
+	"io"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql/dialect"
+	"github.com/stephenafamo/bob/types/pgtypes"
+)
+
+// ArrayArg sends the slice as a single array argument,
+// so that it can be used with [Any], [All] and the array operators
+// SQL: $1
+// Go: psql.Quote("id").EQ(psql.Any(psql.ArrayArg([]int64{1, 2, 3})))
+func ArrayArg[T any](vals []T) Expression {
+	return Arg(pgtypes.Array[T](vals))
+}
+
+// Array creates an array from the expressions.
+// An empty array has no type, so it should be cast to the type of the array
+// SQL: ARRAY[$1, $2]
+// Go: psql.Array(psql.Arg("a"), psql.Arg("b"))
+func Array(exps ...bob.Expression) Expression {
+	return dialect.NewExpression(arrayConstructor{exps: exps})
+}
+
+// ArraySubquery creates an array from the results of a query with a single column
+// SQL: ARRAY(SELECT id FROM users)
+// Go: psql.ArraySubquery(psql.Select(sm.Columns("id"), sm.From("users")))
+func ArraySubquery(q bob.Expression) Expression {
+	return dialect.NewExpression(arrayConstructor{query: q})
+}
+
+// Expands the arrays to a set of rows
+// SQL: unnest($1)
+// Go: psql.Unnest(psql.ArrayArg([]int64{1, 2, 3}))
+func Unnest(arrays ...bob.Expression) *dialect.Function {
+	return dialect.NewFunction("unnest", toAny(arrays)...)
+}
+
+// arrayConstructor is ARRAY[...] or ARRAY(query)
+type arrayConstructor struct {
+	exps  []bob.Expression
+	query bob.Expression
+}
+
+// ShouldOmitParens reports that the array does not need to be grouped
+func (arrayConstructor) ShouldOmitParens() bool { return true }
+
+func (a arrayConstructor) WriteSQL(ctx context.Context, w io.StringWriter, d bob.Dialect, start int) ([]any, error) {
+	if a.query != nil {
+		return bob.ExpressIf(ctx, w, d, start, a.query, true, "ARRAY(", ")")
+	}
+
+	w.WriteString("ARRAY[")
+	args, err := bob.ExpressSlice(ctx, w, d, start, a.exps, "", ", ", "")
+	if err != nil {
+		return nil, err
+	}
+	w.WriteString("]")
+
+	return args, nil
+}
@@ -0,0 +1,155 @@
+package psql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql/dialect"
+	"github.com/stephenafamo/bob/orm"
+)
+
+// copier is implemented by the pgx backed executors in
+// [github.com/stephenafamo/bob/drivers/pgx]
+type copier interface {
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+// CopyFrom inserts the rows using the COPY protocol and returns the number of inserted rows.
+//
+// If the executor is not backed by pgx, the rows are inserted with
+// multi-row INSERT statements, split to stay below the parameter limit.
+//
+// The values of each row are the ones the setter inserts with a regular INSERT,
+// so the tenant of tables with a tenant column and the columns filled by the
+// generated code (e.g. timestamps) are set the same way.
+// COPY cannot use DEFAULT for individual rows, so the rows are grouped by the columns
+// they set and each group is copied separately.
+// Run it in a transaction if all rows should be inserted atomically.
+//
+// The BeforeInsertHooks are run for every row. Since the inserted rows are not
+// returned, the AfterInsertHooks are NOT run.
+// The copied rows also do not invalidate the results cached with the querycache package,
+// call Invalidate on the cache afterwards if needed.
+func (t *Table[T, Tslice, Tset, C]) CopyFrom(ctx context.Context, exec bob.Executor, rows ...Tset) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	c, ok := exec.(copier)
+	if !ok {
+		return t.insertChunked(ctx, exec, rows)
+	}
+
+	var err error
+	for _, row := range rows {
+		ctx, err = t.BeforeInsertHooks.RunHooks(ctx, exec, row)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	tableName := pgx.Identifier{t.name}
+	if t.schema != "" {
+		tableName = pgx.Identifier{t.schema, t.name}
+	} else if schema, _ := ctx.Value(orm.CtxUseSchema).(string); schema != "" {
+		tableName = pgx.Identifier{schema, t.name}
+	}
+
+	groups, err := t.copyGroups(ctx, rows)
+	if err != nil {
+		return 0, fmt.Errorf("copy into %s: %w", tableName.Sanitize(), err)
+	}
+
+	var total int64
+	for _, group := range groups {
+		n, err := c.CopyFrom(ctx, tableName, group.columns, pgx.CopyFromRows(group.values))
+		total += n
+		if err != nil {
+			return total, fmt.Errorf("copy into %s (%s): %w", tableName.Sanitize(), strings.Join(group.columns, ", "), err)
+		}
+	}
+
+	return total, nil
+}
+
+type copyGroup struct {
+	columns []string
+	values  [][]any
+}
+
+// copyGroups groups the rows by the columns they set.
+// Each setter is applied to an INSERT query and the values it adds are rendered,
+// so that the values are the same as those of a regular INSERT
+func (t *Table[T, Tslice, Tset, C]) copyGroups(ctx context.Context, rows []Tset) ([]copyGroup, error) {
+	var groups []copyGroup
+	index := map[string]int{}
+
+	for _, row := range rows {
+		q := &dialect.InsertQuery{}
+		row.Apply(q)
+
+		allColumns := q.TableRef.Columns
+		if len(allColumns) == 0 {
+			allColumns = t.nonGeneratedCols
+		}
+
+		if len(q.Values.Vals) != 1 || len(q.Values.Vals[0]) != len(allColumns) {
+			return nil, fmt.Errorf("setter %T does not add one value for each of the columns %v", row, allColumns)
+		}
+
+		columns := make([]string, 0, len(allColumns))
+		values := make([]any, 0, len(allColumns))
+		for i, val := range q.Values.Vals[0] {
+			value, isDefault, err := copyValue(ctx, val)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", allColumns[i], err)
+			}
+			if isDefault {
+				continue
+			}
+
+			columns = append(columns, allColumns[i])
+			values = append(values, value)
+		}
+
+		key := strings.Join(columns, ",")
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, copyGroup{columns: columns})
+		}
+
+		groups[i].values = append(groups[i].values, values)
+	}
+
+	return groups, nil
+}
+
+// copyValue renders a value added by a setter to an INSERT query.
+// It must be either DEFAULT or a single argument, since COPY only takes values
+func copyValue(ctx context.Context, val bob.Expression) (any, bool, error) {
+	buf := &strings.Builder{}
+	args, err := val.WriteSQL(ctx, buf, dialect.Dialect, 1)
+	if err != nil {
+		return nil, false, err
+	}
+
+	switch {
+	case strings.EqualFold(buf.String(), "DEFAULT") && len(args) == 0:
+		return nil, true, nil
+	case buf.String() == "$1" && len(args) == 1:
+		return args[0], false, nil
+	default:
+		return nil, false, fmt.Errorf("cannot copy the expression %q", buf.String())
+	}
+}
+
+// insertChunked inserts the rows with as many INSERT statements
+// as needed to stay below the parameter limit
+func (t *Table[T, Tslice, Tset, C]) insertChunked(ctx context.Context, exec bob.Executor, rows []Tset) (int64, error) {
+	return t.Insert(bob.ToMods(rows...)).Exec(ctx, exec)
+}
@@ -0,0 +1,168 @@
+package psql_test
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/dialect"
+	"github.com/stephenafamo/bob/dialect/psql/fm"
+	"github.com/stephenafamo/bob/dialect/psql/im"
+	"github.com/stephenafamo/bob/dialect/psql/mm"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+	"github.com/stephenafamo/bob/dialect/psql/wm"
+)
+
+func TestWriteLiteral(t *testing.T) {
+	tests := map[string]struct {
+		value    any
+		expected string
+	}{
+		"null":      {value: nil, expected: "NULL"},
+		"bool":      {value: true, expected: "TRUE"},
+		"int":       {value: 42, expected: "42"},
+		"float":     {value: 1.5, expected: "1.5"},
+		"nan":       {value: math.NaN(), expected: "'NaN'"},
+		"string":    {value: "it's", expected: "'it''s'"},
+		"backslash": {value: "a\\b\n'c'", expected: `E'a\\b\n\'c\''`},
+		"bytes":     {value: []byte{0xde, 0xad}, expected: `'\xdead'::bytea`},
+		"time": {
+			value:    time.Date(2024, 1, 2, 3, 4, 5, 6000, time.FixedZone("", 3600)),
+			expected: "'2024-01-02 03:04:05.000006+01:00'",
+		},
+		"array": {value: []string{"a", "b'"}, expected: "ARRAY['a', 'b''']"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b strings.Builder
+			if err := dialect.Dialect.WriteLiteral(&b, tc.value); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expected, b.String()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+
+	if err := dialect.Dialect.WriteLiteral(&strings.Builder{}, struct{}{}); err == nil {
+		t.Fatal("expected an error for an unsupported type")
+	}
+}
+
+func TestBuildDebug(t *testing.T) {
+	tests := map[string]struct {
+		query    bob.Query
+		opts     bob.DebugOptions
+		expected string
+	}{
+		"select with placeholders": {
+			query: psql.Select(
+				sm.Columns("id"),
+				sm.From("users"),
+				sm.Where(psql.Quote("name").EQ(psql.Arg("bob"))),
+			),
+			expected: `SELECT
+  id
+FROM
+  users
+WHERE
+  ("name" = $1)`,
+		},
+		"select with cte, join and window": {
+			query: psql.Select(
+				sm.With("recent").As(psql.Select(
+					sm.Columns("user_id"),
+					sm.From("orders"),
+					sm.Where(psql.Quote("created_at").GT(psql.Arg(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))),
+				)),
+				sm.Columns("u.id", psql.F("count", "r.user_id")(fm.Over(wm.PartitionBy("u.id"))).As("c")),
+				sm.From("users").As("u"),
+				sm.InnerJoin("recent").As("r").On(psql.Quote("r", "user_id").EQ(psql.Quote("u", "id"))),
+				sm.Where(psql.Quote("u", "name").EQ(psql.Arg(`it's a \ test`))),
+				sm.Where(psql.Quote("u", "avatar").EQ(psql.Arg([]byte{1, 2}))),
+				sm.Limit(10),
+			),
+			opts: bob.DebugOptions{InlineArgs: true, Indent: "\t"},
+			expected: `WITH
+	"recent" AS (
+		SELECT
+			user_id
+		FROM
+			orders
+		WHERE
+			("created_at" > '2024-01-01 00:00:00+00:00')
+	)
+SELECT
+	u.id,
+	count(r.user_id) OVER (PARTITION BY u.id) AS "c"
+FROM
+	users AS "u"
+	INNER JOIN recent AS "r" ON ("r"."user_id" = "u"."id")
+WHERE
+	("u"."name" = E'it\'s a \\ test')
+	AND ("u"."avatar" = '\x0102'::bytea)
+LIMIT 10`,
+		},
+		"insert on conflict": {
+			query: psql.Insert(
+				im.Into("users", "id", "name"),
+				im.Values(psql.Arg(1, "alice")),
+				im.OnConflict("id").DoUpdate(im.SetExcluded("name")),
+				im.Returning("id"),
+			),
+			opts: bob.DebugOptions{InlineArgs: true},
+			expected: `INSERT INTO users("id", "name")
+VALUES
+  (1, 'alice')
+ON CONFLICT (id) DO UPDATE SET "name" = EXCLUDED."name"
+RETURNING
+  id`,
+		},
+		"merge": {
+			query: psql.Merge(
+				mm.Into("accounts"),
+				mm.Using("changes").As("c").On(psql.Quote("c", "id").EQ(psql.Quote("accounts", "id"))),
+				mm.WhenMatched().And(psql.Quote("c", "delta").GT(psql.Arg(0))).ThenUpdate(
+					mm.SetCol("balance").To(psql.Raw("balance + c.delta")),
+				),
+				mm.WhenNotMatched().ThenInsert(mm.Values(psql.Quote("c", "id"), psql.Quote("c", "delta"))),
+			),
+			opts: bob.DebugOptions{InlineArgs: true},
+			expected: `MERGE INTO accounts
+USING changes AS "c" ON ("c"."id" = "accounts"."id")
+WHEN MATCHED AND ("c"."delta" > 0) THEN UPDATE SET "balance" = balance + c.delta
+WHEN NOT MATCHED THEN INSERT VALUES ("c"."id", "c"."delta")`,
+		},
+		"named args are kept": {
+			query: psql.Select(
+				sm.From("users"),
+				sm.Where(psql.Quote("id").EQ(bob.Named("id"))),
+			),
+			opts: bob.DebugOptions{InlineArgs: true},
+			expected: `SELECT
+  *
+FROM
+  users
+WHERE
+  ("id" = $1)`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := bob.BuildDebug(context.Background(), tc.query, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expected, got.String()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
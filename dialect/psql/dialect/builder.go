@@ -2,6 +2,7 @@ package dialect
 
 import (
 	"context"
+	"io"
 	"strings"
 
 	"github.com/stephenafamo/bob"
@@ -57,3 +58,127 @@ func (x Expression) ILike(val bob.Expression) Expression {
 		x.Base, iLike, val,
 	}})
 }
+
+// -> key
+// Gets a JSON object field or array element
+func (x Expression) JSONGet(key bob.Expression) Expression {
+	return x.OP("->", key)
+}
+
+// ->> key
+// Gets a JSON object field or array element as text
+func (x Expression) JSONGetText(key bob.Expression) Expression {
+	return x.OP("->>", key)
+}
+
+// #> path
+// Gets the JSON object at the path, given as a text array
+func (x Expression) JSONGetPath(path bob.Expression) Expression {
+	return x.OP("#>", path)
+}
+
+// #>> path
+// Gets the JSON object at the path, given as a text array, as text
+func (x Expression) JSONGetPathText(path bob.Expression) Expression {
+	return x.OP("#>>", path)
+}
+
+// @> val
+// Works with jsonb, arrays and ranges
+func (x Expression) Contains(val bob.Expression) Expression {
+	return x.OP("@>", val)
+}
+
+// <@ val
+// Works with jsonb, arrays and ranges
+func (x Expression) ContainedBy(val bob.Expression) Expression {
+	return x.OP("<@", val)
+}
+
+// ? key
+// Checks if the key exists as a top-level key or array element of the jsonb value
+func (x Expression) HasKey(key bob.Expression) Expression {
+	return x.OP("?", key)
+}
+
+// ?| keys
+// Checks if any of the keys, given as a text array, exist
+func (x Expression) HasAnyKey(keys bob.Expression) Expression {
+	return x.OP("?|", keys)
+}
+
+// ?& keys
+// Checks if all of the keys, given as a text array, exist
+func (x Expression) HasAllKeys(keys bob.Expression) Expression {
+	return x.OP("?&", keys)
+}
+
+// @? path
+// Checks if the jsonpath returns any item for the jsonb value
+func (x Expression) JSONPathExists(path bob.Expression) Expression {
+	return x.OP("@?", path)
+}
+
+// @@ val
+// Returns the result of a jsonpath predicate check, or matches a tsvector with a tsquery
+func (x Expression) Match(val bob.Expression) Expression {
+	return x.OP("@@", val)
+}
+
+// && val
+// Checks if the arrays or ranges have any element in common
+func (x Expression) Overlaps(val bob.Expression) Expression {
+	return x.OP("&&", val)
+}
+
+// [i]
+// Gets the array element at the index. Postgres arrays start at 1
+func (x Expression) Index(i bob.Expression) Expression {
+	return Expression{}.New(subscript{base: x.Base, lower: i})
+}
+
+// [lower:upper]
+// Gets the slice of the array between the bounds, inclusive.
+// A nil bound is omitted, and the slice starts or ends with the array
+func (x Expression) Slice(lower, upper bob.Expression) Expression {
+	return Expression{}.New(subscript{base: x.Base, lower: lower, upper: upper, slice: true})
+}
+
+// subscript is an array subscript or slice.
+// The base is always grouped, since only some expressions can be subscripted directly
+type subscript struct {
+	base  bob.Expression
+	lower bob.Expression
+	upper bob.Expression
+	slice bool
+}
+
+// ShouldOmitParens reports that the subscript does not need to be grouped
+func (subscript) ShouldOmitParens() bool { return true }
+
+func (s subscript) WriteSQL(ctx context.Context, w io.StringWriter, d bob.Dialect, start int) ([]any, error) {
+	args, err := bob.ExpressIf(ctx, w, d, start, s.base, true, "(", ")")
+	if err != nil {
+		return nil, err
+	}
+
+	w.WriteString("[")
+	lowerArgs, err := bob.ExpressIf(ctx, w, d, start+len(args), s.lower, s.lower != nil, "", "")
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, lowerArgs...)
+
+	if s.slice {
+		w.WriteString(":")
+	}
+
+	upperArgs, err := bob.ExpressIf(ctx, w, d, start+len(args), s.upper, s.upper != nil, "", "")
+	if err != nil {
+		return nil, err
+	}
+	args = append(args, upperArgs...)
+
+	w.WriteString("]")
+	return args, nil
+}
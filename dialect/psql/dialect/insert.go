@@ -29,6 +29,7 @@ type InsertQuery struct {
 
 	bob.Load
 	bob.EmbeddedHook
+	bob.StatementTimeout
 	bob.ContextualModdable[*InsertQuery]
 }
 
@@ -0,0 +1,117 @@
+package dialect
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteLiteral writes the value as a PostgreSQL literal.
+// Strings with backslashes or control characters are written as E'...' strings,
+// byte slices as bytea hex and other slices as arrays.
+// It is used to print queries for debugging and must not be used to build queries to execute
+func (d dialect) WriteLiteral(w io.StringWriter, value any) error {
+	if value != nil {
+		if rv := reflect.ValueOf(value); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+			return d.writeArray(w, rv)
+		}
+	}
+
+	v, err := driver.DefaultParameterConverter.ConvertValue(value)
+	if err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case nil:
+		w.WriteString("NULL")
+	case bool:
+		if v {
+			w.WriteString("TRUE")
+		} else {
+			w.WriteString("FALSE")
+		}
+	case int64:
+		w.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			w.WriteString("'NaN'")
+		case math.IsInf(v, 1):
+			w.WriteString("'Infinity'")
+		case math.IsInf(v, -1):
+			w.WriteString("'-Infinity'")
+		default:
+			w.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	case string:
+		writeString(w, v)
+	case []byte:
+		w.WriteString(`'\x`)
+		w.WriteString(hex.EncodeToString(v))
+		w.WriteString(`'::bytea`)
+	case time.Time:
+		w.WriteString("'")
+		w.WriteString(v.Format("2006-01-02 15:04:05.999999-07:00"))
+		w.WriteString("'")
+	default:
+		return fmt.Errorf("cannot write %T as a literal", v)
+	}
+
+	return nil
+}
+
+func (d dialect) writeArray(w io.StringWriter, rv reflect.Value) error {
+	w.WriteString("ARRAY[")
+	for i := range rv.Len() {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		if err := d.WriteLiteral(w, rv.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	w.WriteString("]")
+
+	return nil
+}
+
+func writeString(w io.StringWriter, s string) {
+	if !strings.ContainsFunc(s, func(r rune) bool { return r == '\\' || r < ' ' }) {
+		w.WriteString("'")
+		w.WriteString(strings.ReplaceAll(s, "'", "''"))
+		w.WriteString("'")
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("E'")
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\'':
+			b.WriteString(`\'`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < ' ' {
+				fmt.Fprintf(&b, `\x%02x`, r)
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteString("'")
+	w.WriteString(b.String())
+}
@@ -45,6 +45,7 @@ type MergeQuery struct {
 
 	bob.Load
 	bob.EmbeddedHook
+	bob.StatementTimeout
 	bob.ContextualModdable[*MergeQuery]
 }
 
@@ -28,6 +28,7 @@ type SelectQuery struct {
 
 	bob.Load
 	bob.EmbeddedHook
+	bob.StatementTimeout
 	bob.ContextualModdable[*SelectQuery]
 
 	CombinedOrder  clause.OrderBy
@@ -0,0 +1,127 @@
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/internal/txexec"
+	"github.com/stephenafamo/scan"
+)
+
+// WithStatementTimeout returns an executor that runs each query with a
+// transaction-local statement_timeout.
+//
+// If exec is a [bob.Transaction], the timeout is set for the query and the
+// previous value is restored after it. Otherwise, a transaction is started with
+// [bob.Begin] and committed once the query is done, or its rows are closed.
+// The query then runs where the transactions of exec run, e.g. on the primary
+// of a [bob.ReadWriteTransactor], and not through a cache.
+//
+// If exec cannot begin a transaction, such as an executor returned by [bob.SplitReads],
+// the setting cannot be kept to the connection of the query. The query is run on exec
+// with a context that is cancelled after the timeout instead, which cancels
+// it on the server.
+func (d dialect) WithStatementTimeout(exec bob.Executor, timeout time.Duration) bob.Executor {
+	return timeoutExecutor{exec: exec, timeout: timeout}
+}
+
+type timeoutExecutor struct {
+	exec    bob.Executor
+	timeout time.Duration
+}
+
+func (t timeoutExecutor) setting() string {
+	return strconv.FormatInt(max(t.timeout.Milliseconds(), 1), 10)
+}
+
+// begin sets the local statement_timeout, starting a transaction if needed.
+// It returns the context to run the query with
+func (t timeoutExecutor) begin(ctx context.Context) (context.Context, bob.Executor, txexec.EndFunc, error) {
+	if tx, ok := t.exec.(bob.Transaction); ok {
+		var previous string
+		rows, err := tx.QueryContext(ctx,
+			"SELECT current_setting('statement_timeout'), set_config('statement_timeout', $1, true)",
+			t.setting(),
+		)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		var current string
+		if rows.Next() {
+			err = rows.Scan(&previous, &current)
+		}
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = rows.Err()
+		}
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		return ctx, tx, func(ctx context.Context, err error) error {
+			if err != nil {
+				// the transaction is aborted, so the setting cannot be restored
+				return err
+			}
+
+			_, err = tx.ExecContext(ctx, "SELECT set_config('statement_timeout', $1, true)", previous)
+			return err
+		}, nil
+	}
+
+	tx, err := bob.Begin(ctx, t.exec)
+	if errors.Is(err, bob.ErrCannotBegin) {
+		ctx, cancel := context.WithTimeoutCause(ctx, t.timeout, bob.ErrStatementTimeout)
+		return ctx, t.exec, func(_ context.Context, err error) error {
+			cancel()
+			return err
+		}, nil
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "SET LOCAL statement_timeout = "+t.setting()); err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, nil, nil, err
+	}
+
+	return ctx, tx, txexec.Commit(tx), nil
+}
+
+// convert returns a [*bob.StatementTimeoutError] if the query was
+// cancelled because of the statement timeout, or its context was
+// cancelled by the timeout
+func (t timeoutExecutor) convert(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var sqlErr interface{ SQLState() string }
+	if errors.As(err, &sqlErr) && sqlErr.SQLState() == "57014" &&
+		strings.Contains(err.Error(), "statement timeout") {
+		return &bob.StatementTimeoutError{Timeout: t.timeout, Err: err}
+	}
+
+	if errors.Is(context.Cause(ctx), bob.ErrStatementTimeout) {
+		return &bob.StatementTimeoutError{Timeout: t.timeout, Err: err}
+	}
+
+	return err
+}
+
+func (t timeoutExecutor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return txexec.Exec(ctx, t.begin, t.convert, query, args...)
+}
+
+func (t timeoutExecutor) QueryContext(ctx context.Context, query string, args ...any) (scan.Rows, error) {
+	return txexec.Query(ctx, t.begin, t.convert, query, args...)
+}
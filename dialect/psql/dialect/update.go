@@ -22,6 +22,7 @@ type UpdateQuery struct {
 
 	bob.Load
 	bob.EmbeddedHook
+	bob.StatementTimeout
 	bob.ContextualModdable[*UpdateQuery]
 }
 
@@ -1,6 +1,8 @@
 package im
 
 import (
+	"time"
+
 	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/clause"
 	"github.com/stephenafamo/bob/dialect/psql/dialect"
@@ -59,6 +61,15 @@ func Rows(rows ...[]bob.Expression) bob.Mod[*dialect.InsertQuery] {
 	return mods.Rows[*dialect.InsertQuery](rows)
 }
 
+// ChunkSize sets the maximum number of rows inserted by a single statement.
+// When the query is executed through a generated table, larger inserts are split
+// into multiple statements run in a single transaction. Inserts through a generated table
+// are already split to stay below the parameter limit of the database, so this is only needed
+// for a smaller limit. Executing more rows without a generated table returns [clause.ErrChunkNotSplit].
+func ChunkSize(rows int) bob.Mod[*dialect.InsertQuery] {
+	return mods.ChunkSize[*dialect.InsertQuery](rows)
+}
+
 // Insert from a query
 func Query(q bob.Query) bob.Mod[*dialect.InsertQuery] {
 	return bob.ModFunc[*dialect.InsertQuery](func(i *dialect.InsertQuery) {
@@ -137,3 +148,10 @@ func Where(e bob.Expression) bob.Mod[*clause.ConflictClause] {
 		c.Where.Conditions = append(c.Where.Conditions, e)
 	})
 }
+
+// StatementTimeout makes the database cancel the query if it runs for longer than d.
+// The query is run in a transaction with a local statement_timeout, so the executor must be a transaction or able to begin one.
+// A query that is cancelled returns a [*bob.StatementTimeoutError]
+func StatementTimeout(d time.Duration) bob.Mod[*dialect.InsertQuery] {
+	return mods.StatementTimeout[*dialect.InsertQuery](d)
+}
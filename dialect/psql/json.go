@@ -0,0 +1,64 @@
+package psql
+
+import (
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql/dialect"
+)
+
+// JSONPath creates a jsonpath argument
+// SQL: CAST($1 AS jsonpath)
+// Go: psql.JSONPath("$.tags[*] ? (@ == \"go\")")
+func JSONPath(path string) Expression {
+	return Cast(Arg(path), "jsonpath")
+}
+
+// extra are the optional vars and silent arguments of the jsonpath function
+func jsonPathFunc(name string, target, path bob.Expression, extra []bob.Expression) *dialect.Function {
+	args := make([]any, 0, len(extra)+2)
+	args = append(args, target, path)
+	for _, e := range extra {
+		args = append(args, e)
+	}
+
+	return dialect.NewFunction(name, args...)
+}
+
+// Checks if the jsonpath returns any item for the jsonb value.
+// extra are the optional vars and silent arguments
+// SQL: jsonb_path_exists("data", CAST($1 AS jsonpath))
+// Go: psql.JSONBPathExists(psql.Quote("data"), psql.JSONPath("$.tags[*]"))
+func JSONBPathExists(target, path bob.Expression, extra ...bob.Expression) *dialect.Function {
+	return jsonPathFunc("jsonb_path_exists", target, path, extra)
+}
+
+// Returns the result of a jsonpath predicate check for the jsonb value.
+// extra are the optional vars and silent arguments
+// SQL: jsonb_path_match("data", CAST($1 AS jsonpath))
+// Go: psql.JSONBPathMatch(psql.Quote("data"), psql.JSONPath("exists($.tags[*])"))
+func JSONBPathMatch(target, path bob.Expression, extra ...bob.Expression) *dialect.Function {
+	return jsonPathFunc("jsonb_path_match", target, path, extra)
+}
+
+// Returns all the items returned by the jsonpath for the jsonb value as a set.
+// extra are the optional vars and silent arguments
+// SQL: jsonb_path_query("data", CAST($1 AS jsonpath))
+// Go: psql.JSONBPathQuery(psql.Quote("data"), psql.JSONPath("$.tags[*]"))
+func JSONBPathQuery(target, path bob.Expression, extra ...bob.Expression) *dialect.Function {
+	return jsonPathFunc("jsonb_path_query", target, path, extra)
+}
+
+// Returns all the items returned by the jsonpath for the jsonb value as a JSON array.
+// extra are the optional vars and silent arguments
+// SQL: jsonb_path_query_array("data", CAST($1 AS jsonpath))
+// Go: psql.JSONBPathQueryArray(psql.Quote("data"), psql.JSONPath("$.tags[*]"))
+func JSONBPathQueryArray(target, path bob.Expression, extra ...bob.Expression) *dialect.Function {
+	return jsonPathFunc("jsonb_path_query_array", target, path, extra)
+}
+
+// Returns the first item returned by the jsonpath for the jsonb value.
+// extra are the optional vars and silent arguments
+// SQL: jsonb_path_query_first("data", CAST($1 AS jsonpath))
+// Go: psql.JSONBPathQueryFirst(psql.Quote("data"), psql.JSONPath("$.tags[*]"))
+func JSONBPathQueryFirst(target, path bob.Expression, extra ...bob.Expression) *dialect.Function {
+	return jsonPathFunc("jsonb_path_query_first", target, path, extra)
+}
@@ -1,6 +1,8 @@
 package mm
 
 import (
+	"time"
+
 	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/clause"
 	"github.com/stephenafamo/bob/dialect/psql/dialect"
@@ -303,3 +305,10 @@ func OverridingUser() bob.Mod[*InsertAction] {
 func Returning(clauses ...any) mods.Returning[*dialect.MergeQuery] {
 	return mods.Returning[*dialect.MergeQuery](clauses)
 }
+
+// StatementTimeout makes the database cancel the query if it runs for longer than d.
+// The query is run in a transaction with a local statement_timeout, so the executor must be a transaction or able to begin one.
+// A query that is cancelled returns a [*bob.StatementTimeoutError]
+func StatementTimeout(d time.Duration) bob.Mod[*dialect.MergeQuery] {
+	return mods.StatementTimeout[*dialect.MergeQuery](d)
+}
@@ -0,0 +1,98 @@
+package psql
+
+import (
+	"strings"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql/dialect"
+)
+
+// searchFunc creates a text search function that takes an optional configuration
+// as its first argument. The configuration is written as a literal instead of an argument,
+// so that the expression can match the expression of an index
+func searchFunc(name, config string, args ...any) *dialect.Function {
+	if config != "" {
+		args = append([]any{S(strings.ReplaceAll(config, "'", "''"))}, args...)
+	}
+
+	return dialect.NewFunction(name, args...)
+}
+
+// Converts the document to a tsvector. If config is empty, the default configuration is used
+// SQL: to_tsvector('english', "body")
+// Go: psql.ToTSVector("english", psql.Quote("body"))
+func ToTSVector(config string, document bob.Expression) *dialect.Function {
+	return searchFunc("to_tsvector", config, document)
+}
+
+// Converts the query, written with tsquery operators, to a tsquery.
+// If config is empty, the default configuration is used
+// SQL: to_tsquery('english', $1)
+// Go: psql.ToTSQuery("english", psql.Arg("fat & rat"))
+func ToTSQuery(config string, query bob.Expression) *dialect.Function {
+	return searchFunc("to_tsquery", config, query)
+}
+
+// Converts the text to a tsquery that matches all its words.
+// If config is empty, the default configuration is used
+// SQL: plainto_tsquery('english', $1)
+// Go: psql.PlainToTSQuery("english", psql.Arg("fat rats"))
+func PlainToTSQuery(config string, query bob.Expression) *dialect.Function {
+	return searchFunc("plainto_tsquery", config, query)
+}
+
+// Converts the text to a tsquery that matches its words as a phrase.
+// If config is empty, the default configuration is used
+// SQL: phraseto_tsquery('english', $1)
+// Go: psql.PhraseToTSQuery("english", psql.Arg("fat rats"))
+func PhraseToTSQuery(config string, query bob.Expression) *dialect.Function {
+	return searchFunc("phraseto_tsquery", config, query)
+}
+
+// Converts the text, written like a web search, to a tsquery.
+// It supports quoted phrases, "or" and "-" to exclude words.
+// If config is empty, the default configuration is used
+// SQL: websearch_to_tsquery('english', $1)
+// Go: psql.WebSearchToTSQuery("english", psql.Arg(`"fat rat" or cat -dog`))
+func WebSearchToTSQuery(config string, query bob.Expression) *dialect.Function {
+	return searchFunc("websearch_to_tsquery", config, query)
+}
+
+// Ranks the vector for the query by the frequency of the matching words.
+// normalization is the optional normalization argument
+// SQL: ts_rank("search", websearch_to_tsquery($1))
+// Go: psql.TSRank(psql.Quote("search"), psql.WebSearchToTSQuery("", psql.Arg("fat rat")))
+func TSRank(vector, query bob.Expression, normalization ...bob.Expression) *dialect.Function {
+	return searchFunc("ts_rank", "", append([]any{vector, query}, toAny(normalization)...)...)
+}
+
+// Ranks the vector for the query by the proximity of the matching words.
+// normalization is the optional normalization argument
+// SQL: ts_rank_cd("search", websearch_to_tsquery($1))
+// Go: psql.TSRankCD(psql.Quote("search"), psql.WebSearchToTSQuery("", psql.Arg("fat rat")))
+func TSRankCD(vector, query bob.Expression, normalization ...bob.Expression) *dialect.Function {
+	return searchFunc("ts_rank_cd", "", append([]any{vector, query}, toAny(normalization)...)...)
+}
+
+// Returns the document with the words that match the query highlighted.
+// If config is empty, the default configuration is used.
+// options is the optional options string, such as "MaxWords=20, MinWords=5"
+// SQL: ts_headline('english', "body", websearch_to_tsquery('english', $1), $2)
+// Go: psql.TSHeadline("english", psql.Quote("body"), psql.WebSearchToTSQuery("english", psql.Arg("rat")), "MaxWords=20")
+func TSHeadline(config string, document, query bob.Expression, options string) *dialect.Function {
+	args := []any{document, query}
+	if options != "" {
+		args = append(args, Arg(options))
+	}
+
+	return searchFunc("ts_headline", config, args...)
+}
+
+func toAny(exps []bob.Expression) []any {
+	args := make([]any, len(exps))
+	for i, e := range exps {
+		args[i] = e
+	}
+
+	return args
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/stephenafamo/bob/dialect/psql/sm"
 	"github.com/stephenafamo/bob/dialect/psql/wm"
 	testutils "github.com/stephenafamo/bob/test/utils"
+	"github.com/stephenafamo/bob/types/pgtypes"
 	pgparse "github.com/wasilibs/go-pgquery"
 )
 
@@ -529,6 +530,88 @@ ORDER BY id LIMIT 1000`,
 			),
 			ExpectedSQL: `(SELECT id, name FROM users ORDER BY id LIMIT 1) UNION (SELECT id, name FROM admins ORDER BY id LIMIT 1)`,
 		},
+		"json operators": {
+			Query: psql.Select(
+				sm.Columns(
+					psql.Quote("data").JSONGet(psql.S("address")).JSONGetText(psql.S("city")),
+					psql.Quote("data").JSONGetPathText(psql.S("{address,zip}")),
+				),
+				sm.From("users"),
+				sm.Where(psql.Quote("data").Contains(psql.Arg(`{"active":true}`))),
+				sm.Where(psql.Quote("data").JSONGetPath(psql.S("{tags}")).HasKey(psql.S("go"))),
+				sm.Where(psql.Quote("data").HasAnyKey(psql.S("{a,b}")).Or(
+					psql.Quote("data").HasAllKeys(psql.S("{c,d}")),
+				)),
+				sm.Where(psql.Arg(`{"a":1}`).ContainedBy(psql.Quote("data"))),
+			),
+			ExpectedSQL: `SELECT (("data" -> 'address') ->> 'city'), ("data" #>> '{address,zip}')
+				FROM users
+				WHERE ("data" @> $1)
+				AND ((("data" #> '{tags}') ? 'go'))
+				AND (("data" ?| '{a,b}') OR ("data" ?& '{c,d}'))
+				AND ($2 <@ "data")`,
+			ExpectedArgs: []any{`{"active":true}`, `{"a":1}`},
+		},
+		"jsonpath": {
+			Query: psql.Select(
+				sm.Columns(psql.JSONBPathQueryArray(psql.Quote("data"), psql.JSONPath("$.tags[*]"))),
+				sm.From("users"),
+				sm.Where(psql.Quote("data").JSONPathExists(psql.JSONPath(`$.tags[*] ? (@ == "go")`))),
+				sm.Where(psql.Quote("data").Match(psql.JSONPath("$.age > 18"))),
+				sm.Where(psql.JSONBPathExists(psql.Quote("data"), psql.JSONPath("$.a"), psql.Arg(`{}`), psql.Raw("true"))),
+			),
+			ExpectedSQL: `SELECT jsonb_path_query_array("data", CAST($1 AS jsonpath))
+				FROM users
+				WHERE ("data" @? CAST($2 AS jsonpath))
+				AND ("data" @@ CAST($3 AS jsonpath))
+				AND jsonb_path_exists("data", CAST($4 AS jsonpath), $5, true)`,
+			ExpectedArgs: []any{"$.tags[*]", `$.tags[*] ? (@ == "go")`, "$.age > 18", "$.a", `{}`},
+		},
+		"array operators": {
+			Query: psql.Select(
+				sm.Columns(
+					psql.Quote("tags").Index(psql.Arg(1)),
+					psql.Quote("tags").Slice(psql.Arg(2), nil),
+					psql.Quote("tags").Concat(psql.Array(psql.S("a"), psql.S("b"))),
+					psql.ArraySubquery(psql.Select(sm.Columns("id"), sm.From("admins"))),
+				),
+				sm.From("users"),
+				sm.Where(psql.Quote("tags").Overlaps(psql.ArrayArg([]string{"go", "sql"}))),
+				sm.Where(psql.Quote("tags").Contains(psql.Array(psql.S("go")))),
+				sm.Where(psql.Quote("id").EQ(psql.Any(psql.ArrayArg([]int64{1, 2})))),
+				sm.Where(psql.Quote("id").NE(psql.All(psql.Select(sm.Columns(psql.Unnest(psql.ArrayArg([]int64{3}))))))),
+				sm.Where(psql.WhereArray[*dialect.SelectQuery, pgtypes.Array[string]](psql.Quote("tags")).ContainedBy(pgtypes.Array[string]{"go"}).E),
+			),
+			ExpectedSQL: `SELECT ("tags")[$1], ("tags")[$2:], ("tags" || ARRAY['a', 'b']), ARRAY(SELECT id FROM admins)
+				FROM users
+				WHERE ("tags" && $3)
+				AND ("tags" @> ARRAY['go'])
+				AND ("id" = ANY ($4))
+				AND ("id" <> ALL ((SELECT unnest($5))))
+				AND ("tags" <@ $6)`,
+			ExpectedArgs: []any{
+				1, 2, pgtypes.Array[string]{"go", "sql"}, pgtypes.Array[int64]{1, 2},
+				pgtypes.Array[int64]{3}, pgtypes.Array[string]{"go"},
+			},
+		},
+		"full text search": {
+			Query: psql.Select(
+				sm.Columns(
+					"id",
+					psql.TSHeadline("english", psql.Quote("body"), psql.PlainToTSQuery("english", psql.Arg("fat rats")), "MaxWords=20"),
+				),
+				sm.From("posts"),
+				sm.Where(psql.Group(psql.ToTSVector("english", psql.Quote("body"))).Match(psql.ToTSQuery("english", psql.Arg("fat & rat")))),
+				sm.Where(psql.WhereSearch[*dialect.SelectQuery, string](psql.Quote("search")).Search(`"fat rat" -cat`).E),
+				sm.OrderBy(psql.TSRank(psql.Quote("search"), psql.PhraseToTSQuery("", psql.Arg("fat rat")), psql.Arg(1))).Desc(),
+			),
+			ExpectedSQL: `SELECT id, ts_headline('english', "body", plainto_tsquery('english', $1), $2)
+				FROM posts
+				WHERE ((to_tsvector('english', "body")) @@ to_tsquery('english', $3))
+				AND ("search" @@ websearch_to_tsquery($4))
+				ORDER BY ts_rank("search", phraseto_tsquery($5), $6) DESC`,
+			ExpectedArgs: []any{"fat rats", "MaxWords=20", "fat & rat", `"fat rat" -cat`, "fat rat", 1},
+		},
 	}
 
 	testutils.RunTests(t, examples, formatter)
@@ -0,0 +1,195 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/internal/txexec"
+	"github.com/stephenafamo/scan"
+)
+
+type sessionCtxKey struct{}
+
+// sessionSettings are the settings applied to a transaction
+// by [BeginSession] and [SessionExecutor]
+type sessionSettings struct {
+	names  []string
+	values []string
+	role   string
+}
+
+func (s sessionSettings) empty() bool {
+	return len(s.names) == 0 && s.role == ""
+}
+
+func getSession(ctx context.Context) sessionSettings {
+	s, _ := ctx.Value(sessionCtxKey{}).(sessionSettings)
+	return s
+}
+
+// WithSessionVar returns a context with a setting that is applied with SET LOCAL
+// to transactions started with [BeginSession] or run through a [SessionExecutor].
+// The name is usually namespaced, such as "app.user_id", so that it can be read
+// in row-level security policies with current_setting('app.user_id')
+func WithSessionVar(ctx context.Context, name, value string) context.Context {
+	s := getSession(ctx)
+
+	names := make([]string, 0, len(s.names)+1)
+	values := make([]string, 0, len(s.values)+1)
+	for i, n := range s.names {
+		if n != name {
+			names = append(names, n)
+			values = append(values, s.values[i])
+		}
+	}
+
+	s.names = append(names, name)
+	s.values = append(values, value)
+	return context.WithValue(ctx, sessionCtxKey{}, s)
+}
+
+// WithSessionRole returns a context with a role that is switched to with
+// SET LOCAL ROLE in transactions started with [BeginSession] or run through
+// a [SessionExecutor]
+func WithSessionRole(ctx context.Context, role string) context.Context {
+	s := getSession(ctx)
+	s.role = role
+	return context.WithValue(ctx, sessionCtxKey{}, s)
+}
+
+// applySession sets the session variables and role of the context
+// for the rest of the transaction
+func applySession(ctx context.Context, tx bob.Executor, s sessionSettings) error {
+	if s.empty() {
+		return nil
+	}
+
+	// set_config with is_local = true is the same as SET LOCAL,
+	// but the names and values can be sent as arguments
+	var query strings.Builder
+	args := make([]any, 0, len(s.names)*2+2)
+	query.WriteString("SELECT ")
+
+	for i, name := range s.names {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		fmt.Fprintf(&query, "set_config($%d, $%d, true)", len(args)+1, len(args)+2)
+		args = append(args, name, s.values[i])
+	}
+
+	if s.role != "" {
+		if len(args) > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString("set_config('role', $" + strconv.Itoa(len(args)+1) + ", true)")
+		args = append(args, s.role)
+	}
+
+	if _, err := tx.ExecContext(ctx, query.String(), args...); err != nil {
+		return fmt.Errorf("applying session settings: %w", err)
+	}
+
+	return nil
+}
+
+// BeginSession starts a transaction on exec with [bob.Begin] and applies the
+// session variables and role in the context with SET LOCAL before returning it.
+// The settings only last until the transaction ends, so they are never left on
+// a connection that goes back to the pool.
+// exec can be a [bob.Transactor] such as [bob.DB] or a pool from drivers/pgx
+func BeginSession(ctx context.Context, exec bob.Executor) (bob.Transaction, error) {
+	return BeginSessionTx(ctx, exec, nil)
+}
+
+// BeginSessionTx is like [BeginSession], but starts the transaction with the
+// given options using [bob.BeginTx]
+func BeginSessionTx(ctx context.Context, exec bob.Executor, opts *sql.TxOptions) (bob.Transaction, error) {
+	tx, err := bob.BeginTx(ctx, exec, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applySession(ctx, tx, getSession(ctx)); err != nil {
+		_ = tx.Rollback(ctx)
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// WithSession wraps an executor so that every query run with session variables
+// or a role in the context is run in a transaction started with [BeginSession].
+// The transaction is committed once the query is done, or its rows are closed.
+//
+// If exec is a [bob.Transaction], the settings are applied to it with SET LOCAL
+// before each query with settings, and are not reset after it. They last until
+// the transaction ends, so queries run on exec afterwards without settings in
+// the context still use them.
+// Queries without settings in the context are run on exec directly
+func WithSession(exec bob.Executor) SessionExecutor {
+	return SessionExecutor{exec: exec}
+}
+
+// SessionExecutor is the [bob.Executor] returned by [WithSession]
+type SessionExecutor struct {
+	exec bob.Executor
+}
+
+func (s SessionExecutor) begin(ctx context.Context) (context.Context, bob.Executor, txexec.EndFunc, error) {
+	settings := getSession(ctx)
+	if settings.empty() {
+		return ctx, s.exec, txexec.Keep, nil
+	}
+
+	if _, ok := s.exec.(bob.Transaction); ok {
+		if err := applySession(ctx, s.exec, settings); err != nil {
+			return nil, nil, nil, err
+		}
+
+		return ctx, s.exec, txexec.Keep, nil
+	}
+
+	tx, err := BeginSession(ctx, s.exec)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return ctx, tx, txexec.Commit(tx), nil
+}
+
+func (s SessionExecutor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return txexec.Exec(ctx, s.begin, nil, query, args...)
+}
+
+func (s SessionExecutor) QueryContext(ctx context.Context, query string, args ...any) (scan.Rows, error) {
+	return txexec.Query(ctx, s.begin, nil, query, args...)
+}
+
+// Begin starts a transaction with [BeginSession], so that every query run in it
+// uses the session variables and role in the context
+func (s SessionExecutor) Begin(ctx context.Context) (bob.Transaction, error) {
+	return BeginSession(ctx, s.exec)
+}
+
+// BeginTx starts a transaction with [BeginSessionTx], so that every query run in it
+// uses the session variables and role in the context
+func (s SessionExecutor) BeginTx(ctx context.Context, opts *sql.TxOptions) (bob.Transaction, error) {
+	return BeginSessionTx(ctx, s.exec, opts)
+}
+
+// RunInTx runs the provided function in a transaction started with [BeginSessionTx].
+// If the function returns an error, the transaction is rolled back.
+// Otherwise, the transaction is committed.
+func (s SessionExecutor) RunInTx(ctx context.Context, txOptions *sql.TxOptions, fn func(context.Context, bob.Transaction) error) error {
+	tx, err := s.BeginTx(ctx, txOptions)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	return bob.RunTx(ctx, tx, fn)
+}
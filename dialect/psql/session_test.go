@@ -0,0 +1,156 @@
+package psql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/bobtest"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+	"github.com/stephenafamo/bob/dialect/psql/um"
+	"github.com/stephenafamo/scan"
+)
+
+func TestSession(t *testing.T) {
+	ctx := psql.WithSessionVar(context.Background(), "app.user_id", "1")
+	ctx = psql.WithSessionVar(ctx, "app.tenant_id", "7")
+	ctx = psql.WithSessionVar(ctx, "app.user_id", "2")
+	ctx = psql.WithSessionRole(ctx, "app_user")
+
+	t.Run("begin session", func(t *testing.T) {
+		mock := bobtest.New(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("SELECT set_config($1, $2, true), set_config($3, $4, true), set_config('role', $5, true)").
+			WithArgs("app.tenant_id", "7", "app.user_id", "2", "app_user")
+		mock.ExpectCommit()
+
+		tx, err := psql.BeginSession(ctx, mockDB{Executor: mock, mock: mock})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("runs each query in a transaction", func(t *testing.T) {
+		mock := bobtest.New(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("SELECT set_config($1, $2, true)").WithArgs("app.user_id", "1")
+		mock.ExpectQuery("SELECT id FROM users").
+			WillReturnRows(bobtest.NewRows("id").AddRow(1).AddRow(2))
+		mock.ExpectCommit()
+
+		exec := psql.WithSession(mockDB{Executor: mock, mock: mock})
+		ctx := psql.WithSessionVar(context.Background(), "app.user_id", "1")
+
+		q := psql.Select(sm.Columns("id"), sm.From("users"))
+		ids, err := bob.All(ctx, exec, q, scan.SingleColumnMapper[int64])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 2 {
+			t.Fatalf("expected 2 ids, got %v", ids)
+		}
+	})
+
+	t.Run("rolls back on error", func(t *testing.T) {
+		queryErr := errors.New("permission denied")
+
+		mock := bobtest.New(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("SELECT set_config('role', $1, true)").WithArgs("app_user")
+		mock.ExpectExec(`UPDATE users SET "active" = $1`).WillReturnError(queryErr)
+		mock.ExpectRollback()
+
+		exec := psql.WithSession(mockDB{Executor: mock, mock: mock})
+		ctx := psql.WithSessionRole(context.Background(), "app_user")
+
+		q := psql.Update(um.Table("users"), um.SetCol("active").ToArg(false))
+		if _, err := q.Exec(ctx, exec); !errors.Is(err, queryErr) {
+			t.Fatalf("expected the query error, got %v", err)
+		}
+	})
+
+	t.Run("applies to a transaction", func(t *testing.T) {
+		mock := bobtest.New(t)
+		mock.ExpectExec("SELECT set_config($1, $2, true)").WithArgs("app.user_id", "1")
+		mock.ExpectExec(`UPDATE users SET "active" = $1`)
+
+		ctx := psql.WithSessionVar(context.Background(), "app.user_id", "1")
+
+		q := psql.Update(um.Table("users"), um.SetCol("active").ToArg(false))
+		if _, err := q.Exec(ctx, psql.WithSession(mock)); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("settings stay on a transaction", func(t *testing.T) {
+		mock := bobtest.New(t)
+		mock.ExpectExec("SELECT set_config($1, $2, true)").WithArgs("app.user_id", "1")
+		mock.ExpectExec(`UPDATE users SET "active" = $1`)
+		mock.ExpectExec(`UPDATE users SET "active" = $1`)
+
+		exec := psql.WithSession(mock)
+		ctx := psql.WithSessionVar(context.Background(), "app.user_id", "1")
+
+		q := psql.Update(um.Table("users"), um.SetCol("active").ToArg(false))
+		if _, err := q.Exec(ctx, exec); err != nil {
+			t.Fatal(err)
+		}
+
+		// the settings are not reset, so they still apply to this query
+		if _, err := q.Exec(context.Background(), exec); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("run in tx", func(t *testing.T) {
+		mock := bobtest.New(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("SELECT set_config($1, $2, true)").WithArgs("app.user_id", "1")
+		mock.ExpectExec(`UPDATE users SET "active" = $1`)
+		mock.ExpectCommit()
+
+		exec := psql.WithSession(mockDB{Executor: mock, mock: mock})
+		ctx := psql.WithSessionVar(context.Background(), "app.user_id", "1")
+
+		err := exec.RunInTx(ctx, nil, func(ctx context.Context, tx bob.Transaction) error {
+			q := psql.Update(um.Table("users"), um.SetCol("active").ToArg(false))
+			_, err := q.Exec(ctx, tx)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("run in tx with options", func(t *testing.T) {
+		mock := bobtest.New(t)
+
+		// mockDB has no BeginTx, so the options cannot be used
+		exec := psql.WithSession(mockDB{Executor: mock, mock: mock})
+		err := exec.RunInTx(ctx, &sql.TxOptions{ReadOnly: true}, func(context.Context, bob.Transaction) error {
+			t.Fatal("did not expect the function to be called")
+			return nil
+		})
+		if err == nil {
+			t.Fatal("expected an error for unsupported options")
+		}
+	})
+
+	t.Run("without settings", func(t *testing.T) {
+		mock := bobtest.New(t)
+		mock.ExpectExec(`UPDATE users SET "active" = $1`)
+
+		q := psql.Update(um.Table("users"), um.SetCol("active").ToArg(false))
+		_, err := q.Exec(context.Background(), psql.WithSession(mockDB{Executor: mock, mock: mock}))
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -1,6 +1,8 @@
 package sm
 
 import (
+	"time"
+
 	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/clause"
 	"github.com/stephenafamo/bob/dialect/psql/dialect"
@@ -255,3 +257,10 @@ func FetchCombined(count any, withTies bool) bob.Mod[*dialect.SelectQuery] {
 		q.CombinedFetch.WithTies = withTies
 	})
 }
+
+// StatementTimeout makes the database cancel the query if it runs for longer than d.
+// The query is run in a transaction with a local statement_timeout, so the executor must be a transaction or able to begin one.
+// A query that is cancelled returns a [*bob.StatementTimeoutError]
+func StatementTimeout(d time.Duration) bob.Mod[*dialect.SelectQuery] {
+	return mods.StatementTimeout[*dialect.SelectQuery](d)
+}
@@ -2,6 +2,7 @@ package psql
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -11,11 +12,15 @@ import (
 	"syscall"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	pgxv5 "github.com/jackc/pgx/v5"
 	_ "github.com/lib/pq"
 	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/dialect/psql/dialect"
 	"github.com/stephenafamo/bob/dialect/psql/mm"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
 	"github.com/stephenafamo/bob/dialect/psql/um"
+	"github.com/stephenafamo/bob/drivers/pgx"
 	"github.com/stephenafamo/bob/expr"
 	"github.com/stephenafamo/bob/internal"
 	"github.com/stephenafamo/bob/orm"
@@ -24,7 +29,10 @@ import (
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 )
 
-var testDB bob.DB
+var (
+	testDB  bob.DB
+	testDSN string
+)
 
 func TestMain(m *testing.M) {
 	code := 1
@@ -54,13 +62,13 @@ func TestMain(m *testing.M) {
 		}
 	}()
 
-	dsn, err := postgresContainer.ConnectionString(ctx, "sslmode=disable")
+	testDSN, err = postgresContainer.ConnectionString(ctx, "sslmode=disable")
 	if err != nil {
 		fmt.Printf("could not get connection string: %v\n", err)
 		return
 	}
 
-	testDB, err = bob.Open("postgres", dsn)
+	testDB, err = bob.Open("postgres", testDSN)
 	if err != nil {
 		fmt.Printf("could not connect to db: %v\n", err)
 		return
@@ -98,6 +106,34 @@ func (s UserSetter) Overwrite(t *User) {
 	}
 }
 
+func (s UserSetter) SetColumns() []string {
+	vals := make([]string, 0, 3)
+	if s.ID != nil {
+		vals = append(vals, "id")
+	}
+	if s.Name != nil {
+		vals = append(vals, "name")
+	}
+	if s.Email != nil {
+		vals = append(vals, "email")
+	}
+	return vals
+}
+
+func (s UserSetter) Apply(q *dialect.InsertQuery) {
+	vals := []bob.Expression{Raw("DEFAULT"), Raw("DEFAULT"), Raw("DEFAULT")}
+	if s.ID != nil {
+		vals[0] = Arg(*s.ID)
+	}
+	if s.Name != nil {
+		vals[1] = Arg(*s.Name)
+	}
+	if s.Email != nil {
+		vals[2] = Arg(*s.Email)
+	}
+	q.AppendValues(vals...)
+}
+
 func (s UserSetter) UpdateMod() bob.Mod[*dialect.UpdateQuery] {
 	return um.Set(s.Expressions()...)
 }
@@ -379,3 +415,197 @@ func TestTableMergeWithVersion(t *testing.T) {
 		}
 	})
 }
+
+func TestCopyFrom(t *testing.T) {
+	ctx := t.Context()
+
+	pool, err := pgx.New(ctx, testDSN)
+	if err != nil {
+		t.Fatalf("could not connect with pgx: %v", err)
+	}
+	defer pool.Close()
+
+	tests := map[string]bob.Executor{
+		"pgx":    pool,
+		"stdlib": testDB,
+	}
+
+	for name, exec := range tests {
+		t.Run(name, func(t *testing.T) {
+			table := "copy_users_" + name
+			_, err := exec.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE %s (
+				id INTEGER PRIMARY KEY,
+				name TEXT NOT NULL DEFAULT 'unknown',
+				email TEXT UNIQUE NOT NULL
+			)`, table))
+			if err != nil {
+				t.Fatalf("could not create table: %v", err)
+			}
+			defer func() { _, _ = exec.ExecContext(ctx, "DROP TABLE "+table) }()
+
+			copyTable := NewTable[*User, *UserSetter, bob.Expression]("", table, expr.ColsForStruct[User](table))
+
+			rows := make([]*UserSetter, 0, 100)
+			for i := range int64(100) {
+				setter := &UserSetter{
+					ID:    internal.Pointer(i + 1),
+					Email: internal.Pointer(fmt.Sprintf("user%d@example.com", i+1)),
+				}
+				// leave some names unset to use the column default
+				if i%2 == 0 {
+					setter.Name = internal.Pointer(fmt.Sprintf("user %d", i+1))
+				}
+				rows = append(rows, setter)
+			}
+
+			count, err := copyTable.CopyFrom(ctx, exec, rows...)
+			if err != nil {
+				t.Fatalf("could not copy rows: %v", err)
+			}
+
+			if count != 100 {
+				t.Fatalf("expected 100 inserted rows, got %d", count)
+			}
+
+			users, err := copyTable.Query(sm.OrderBy("id")).All(ctx, exec)
+			if err != nil {
+				t.Fatalf("could not get users: %v", err)
+			}
+
+			if len(users) != 100 {
+				t.Fatalf("expected 100 users, got %d", len(users))
+			}
+
+			if *users[0] != (User{ID: 1, Name: "user 1", Email: "user1@example.com"}) {
+				t.Fatalf("unexpected first user: %#v", *users[0])
+			}
+
+			if *users[1] != (User{ID: 2, Name: "unknown", Email: "user2@example.com"}) {
+				t.Fatalf("unexpected second user: %#v", *users[1])
+			}
+		})
+	}
+}
+
+type tenantRow struct {
+	TenantID int64  `db:"tenant_id"`
+	Name     string `db:"name"`
+}
+
+type tenantRowSetter struct {
+	TenantID *int64  `db:"tenant_id"`
+	Name     *string `db:"name"`
+
+	orm.Setter[*tenantRow, *dialect.InsertQuery, *dialect.UpdateQuery]
+}
+
+func (s tenantRowSetter) SetColumns() []string {
+	if s.Name != nil {
+		return []string{"name"}
+	}
+	return nil
+}
+
+// Apply inserts the tenant in the context like the generated setters
+func (s tenantRowSetter) Apply(q *dialect.InsertQuery) {
+	name := Raw("DEFAULT")
+	if s.Name != nil {
+		name = Arg(*s.Name)
+	}
+
+	q.AppendValues(
+		bob.ExpressionFunc(func(ctx context.Context, w io.StringWriter, d bob.Dialect, start int) ([]any, error) {
+			tenant, _, err := orm.Tenant(ctx, "tenant_rows")
+			if err != nil {
+				return nil, err
+			}
+			return Arg(tenant).WriteSQL(ctx, w, d, start)
+		}),
+		name,
+	)
+}
+
+// fakeCopier records the rows copied with CopyFrom
+type fakeCopier struct {
+	bob.Executor
+	columns [][]string
+	rows    [][][]any
+}
+
+func (c *fakeCopier) CopyFrom(_ context.Context, _ pgxv5.Identifier, columns []string, src pgxv5.CopyFromSource) (int64, error) {
+	var rows [][]any
+	for src.Next() {
+		vals, err := src.Values()
+		if err != nil {
+			return 0, err
+		}
+		rows = append(rows, vals)
+	}
+
+	c.columns = append(c.columns, columns)
+	c.rows = append(c.rows, rows)
+	return int64(len(rows)), src.Err()
+}
+
+func TestCopyFromSetterValues(t *testing.T) {
+	table := NewTable[*tenantRow, *tenantRowSetter, bob.Expression]("", "tenant_rows", expr.ColsForStruct[tenantRow]("tenant_rows")).
+		WithTenant("tenant_id")
+
+	rows := []*tenantRowSetter{
+		{Name: internal.Pointer("a")},
+		{TenantID: internal.Pointer[int64](2)},
+		{Name: internal.Pointer("b")},
+	}
+
+	t.Run("with a tenant", func(t *testing.T) {
+		c := &fakeCopier{}
+		count, err := table.CopyFrom(orm.WithTenant(context.Background(), int64(7)), c, rows...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 3 {
+			t.Fatalf("expected 3 copied rows, got %d", count)
+		}
+
+		expectedColumns := [][]string{{"tenant_id", "name"}, {"tenant_id"}}
+		if diff := cmp.Diff(expectedColumns, c.columns); diff != "" {
+			t.Fatal(diff)
+		}
+
+		expectedRows := [][][]any{{{int64(7), "a"}, {int64(7), "b"}}, {{int64(7)}}}
+		if diff := cmp.Diff(expectedRows, c.rows); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("without a tenant", func(t *testing.T) {
+		_, err := table.CopyFrom(context.Background(), &fakeCopier{}, rows...)
+		if !errors.Is(err, orm.ErrMissingTenant) {
+			t.Fatalf("expected orm.ErrMissingTenant, got %v", err)
+		}
+	})
+}
+
+func TestUpsertTenant(t *testing.T) {
+	table := NewTable[*tenantRow, *tenantRowSetter, bob.Expression]("", "tenant_rows", expr.ColsForStruct[tenantRow]("tenant_rows")).
+		WithTenant("tenant_id")
+
+	ctx := orm.WithTenant(context.Background(), int64(7))
+	query, args, err := table.Upsert([]string{"name"}, &tenantRowSetter{Name: internal.Pointer("a")}).Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `INSERT INTO "tenant_rows"("tenant_id", "name")
+VALUES ($1, $2)
+ON CONFLICT ("name") DO UPDATE SET
+"name" = EXCLUDED."name"
+WHERE ("tenant_rows"."tenant_id" = EXCLUDED."tenant_id")
+RETURNING "tenant_rows"."tenant_id" AS "tenant_id", "tenant_rows"."name" AS "name"`
+	if diff := cmp.Diff(expected, strings.TrimSpace(query)); diff != "" {
+		t.Fatal(diff)
+	}
+	if diff := cmp.Diff([]any{int64(7), "a"}, args); diff != "" {
+		t.Fatal(diff)
+	}
+}
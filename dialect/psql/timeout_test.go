@@ -0,0 +1,130 @@
+package psql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/bobtest"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+	"github.com/stephenafamo/bob/dialect/psql/um"
+	"github.com/stephenafamo/scan"
+)
+
+// mockDB is an executor that begins transactions on the mock,
+// but is not a transaction itself
+type mockDB struct {
+	bob.Executor
+	mock *bobtest.Mock
+}
+
+func (m mockDB) Begin(ctx context.Context) (*bobtest.Mock, error) {
+	return m.mock.Begin(ctx)
+}
+
+func TestStatementTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("begins a transaction", func(t *testing.T) {
+		mock := bobtest.New(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("SET LOCAL statement_timeout = 1500")
+		mock.ExpectQuery("SELECT id FROM users").
+			WillReturnRows(bobtest.NewRows("id").AddRow(1).AddRow(2))
+		mock.ExpectCommit()
+
+		q := psql.Select(sm.Columns("id"), sm.From("users"), sm.StatementTimeout(1500*time.Millisecond))
+		ids, err := bob.All(ctx, mockDB{Executor: mock, mock: mock}, q, scan.SingleColumnMapper[int64])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 2 {
+			t.Fatalf("expected 2 ids, got %v", ids)
+		}
+	})
+
+	t.Run("restores the setting in a transaction", func(t *testing.T) {
+		mock := bobtest.New(t)
+		mock.ExpectQuery("SELECT current_setting('statement_timeout'), set_config('statement_timeout', $1, true)").
+			WithArgs("1").
+			WillReturnRows(bobtest.NewRows("current_setting", "set_config").AddRow("5s", "1"))
+		mock.ExpectExec(`UPDATE users SET "active" = $1`)
+		mock.ExpectExec("SELECT set_config('statement_timeout', $1, true)").WithArgs("5s")
+
+		q := psql.Update(um.Table("users"), um.SetCol("active").ToArg(false), um.StatementTimeout(time.Microsecond))
+		if _, err := q.Exec(ctx, mock); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("returns a typed error", func(t *testing.T) {
+		timeoutErr := &pgconn.PgError{
+			Severity: "ERROR",
+			Code:     "57014",
+			Message:  "canceling statement due to statement timeout",
+		}
+
+		mock := bobtest.New(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("SET LOCAL statement_timeout = 10")
+		mock.ExpectQuery("SELECT * FROM users").WillReturnError(timeoutErr)
+		mock.ExpectRollback()
+
+		q := psql.Select(sm.From("users"), sm.StatementTimeout(10*time.Millisecond))
+		_, err := bob.All(ctx, mockDB{Executor: mock, mock: mock}, q, scan.SingleColumnMapper[int64])
+		if !errors.Is(err, bob.ErrStatementTimeout) {
+			t.Fatalf("expected a statement timeout, got %v", err)
+		}
+
+		var typed *bob.StatementTimeoutError
+		if !errors.As(err, &typed) || typed.Timeout != 10*time.Millisecond || !errors.Is(err, timeoutErr) {
+			t.Fatalf("unexpected error %#v", err)
+		}
+	})
+
+	t.Run("cannot begin", func(t *testing.T) {
+		mock := bobtest.New(t)
+		mock.ExpectQuery("SELECT id FROM users").
+			WillReturnRows(bobtest.NewRows("id").AddRow(1))
+
+		q := psql.Select(sm.Columns("id"), sm.From("users"), sm.StatementTimeout(time.Second))
+		ids, err := bob.All(ctx, struct{ bob.Executor }{mock}, q, scan.SingleColumnMapper[int64])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 1 {
+			t.Fatalf("expected 1 id, got %v", ids)
+		}
+	})
+
+	t.Run("cannot begin and times out", func(t *testing.T) {
+		q := psql.Select(sm.From("users"), sm.StatementTimeout(10*time.Millisecond))
+		_, err := bob.All(ctx, waitingExecutor{}, q, scan.SingleColumnMapper[int64])
+		if !errors.Is(err, bob.ErrStatementTimeout) {
+			t.Fatalf("expected a statement timeout, got %v", err)
+		}
+
+		var typed *bob.StatementTimeoutError
+		if !errors.As(err, &typed) || typed.Timeout != 10*time.Millisecond {
+			t.Fatalf("unexpected error %#v", err)
+		}
+	})
+}
+
+// waitingExecutor runs every query until its context is done
+type waitingExecutor struct{}
+
+func (waitingExecutor) ExecContext(ctx context.Context, _ string, _ ...any) (sql.Result, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (waitingExecutor) QueryContext(ctx context.Context, _ string, _ ...any) (scan.Rows, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
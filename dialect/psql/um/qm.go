@@ -1,6 +1,8 @@
 package um
 
 import (
+	"time"
+
 	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/clause"
 	"github.com/stephenafamo/bob/dialect/psql/dialect"
@@ -104,3 +106,10 @@ func WhereCurrentOf(cursor string) mods.WhereCurrentOf[*dialect.UpdateQuery] {
 func Returning(clauses ...any) mods.Returning[*dialect.UpdateQuery] {
 	return mods.Returning[*dialect.UpdateQuery](clauses)
 }
+
+// StatementTimeout makes the database cancel the query if it runs for longer than d.
+// The query is run in a transaction with a local statement_timeout, so the executor must be a transaction or able to begin one.
+// A query that is cancelled returns a [*bob.StatementTimeoutError]
+func StatementTimeout(d time.Duration) bob.Mod[*dialect.UpdateQuery] {
+	return mods.StatementTimeout[*dialect.UpdateQuery](d)
+}
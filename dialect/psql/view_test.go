@@ -7,6 +7,7 @@ import (
 
 	_ "github.com/lib/pq"
 	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/bobtest"
 	"github.com/stephenafamo/bob/dialect/psql/dialect"
 	"github.com/stephenafamo/bob/dialect/psql/sm"
 	"github.com/stephenafamo/bob/expr"
@@ -97,3 +98,46 @@ func viewToString(t *testing.T, query *ViewQuery[*someStruct, []*someStruct]) st
 	t.Helper()
 	return selectToString(t, query.BaseQuery, 3)
 }
+
+func TestViewQueryPaginate(t *testing.T) {
+	ctx := context.Background()
+	cols := []string{"id", "name", "email", "__bob_cursor_0", "__bob_cursor_1"}
+
+	mock := bobtest.New(t)
+	mock.ExpectQuery(`SELECT "some_struct"."id" AS "id", "some_struct"."name" AS "name", "some_struct"."email" AS "email",
+		"name" AS "__bob_cursor_0", "id" AS "__bob_cursor_1"
+		FROM "some_struct"
+		ORDER BY "name" COLLATE "C", "id"
+		LIMIT 3`).
+		WillReturnRows(bobtest.NewRows(cols...).
+			AddRow(int64(1), "a", "x", "a", int64(1)).
+			AddRow(int64(3), "b", "y", "b", int64(3)).
+			AddRow(int64(2), "c", "x", "c", int64(2)))
+	mock.ExpectQuery(`SELECT "some_struct"."id" AS "id", "some_struct"."name" AS "name", "some_struct"."email" AS "email",
+		"name" AS "__bob_cursor_0", "id" AS "__bob_cursor_1"
+		FROM "some_struct"
+		WHERE ("name" COLLATE "C", "id") > ($1, $2)
+		ORDER BY "name" COLLATE "C", "id"
+		LIMIT 3`).
+		WithArgs("b", int64(3)).
+		WillReturnRows(bobtest.NewRows(cols...).
+			AddRow(int64(2), "c", "x", "c", int64(2)))
+
+	q := someStructViewNoSchema.Query(sm.OrderBy(Quote("name")).Collate("C"), sm.OrderBy(Quote("id")))
+
+	page, err := q.Paginate(ctx, mock, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 2 || page.Next == "" {
+		t.Fatalf("unexpected first page: %d items, next %q", len(page.Items), page.Next)
+	}
+
+	page, err = q.Paginate(ctx, mock, page.Next, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 1 || page.Items[0].ID != 2 {
+		t.Fatalf("unexpected second page: %v", page.Items)
+	}
+}
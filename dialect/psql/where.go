@@ -109,3 +109,82 @@ func (w WhereNullMod[Q, C]) IsNull() mods.Where[Q] {
 func (w WhereNullMod[Q, C]) IsNotNull() mods.Where[Q] {
 	return mods.Where[Q]{E: w.name.IsNotNull()}
 }
+
+func WhereArray[Q Filterable, C any](name Expression) WhereArrayMod[Q, C] {
+	return WhereArrayMod[Q, C]{
+		WhereMod: Where[Q, C](name),
+	}
+}
+
+// WhereArrayMod adds the array operators to [WhereMod] for array columns
+type WhereArrayMod[Q Filterable, C any] struct {
+	WhereMod[Q, C]
+}
+
+// Contains checks if the column contains all the elements of val
+func (w WhereArrayMod[Q, C]) Contains(val C) mods.Where[Q] {
+	return mods.Where[Q]{E: w.name.Contains(Arg(val))}
+}
+
+// ContainedBy checks if all the elements of the column are in val
+func (w WhereArrayMod[Q, C]) ContainedBy(val C) mods.Where[Q] {
+	return mods.Where[Q]{E: w.name.ContainedBy(Arg(val))}
+}
+
+// Overlaps checks if the column has any element in common with val
+func (w WhereArrayMod[Q, C]) Overlaps(val C) mods.Where[Q] {
+	return mods.Where[Q]{E: w.name.Overlaps(Arg(val))}
+}
+
+func WhereArrayNull[Q Filterable, C any](name Expression) WhereArrayNullMod[Q, C] {
+	return WhereArrayNullMod[Q, C]{
+		WhereArrayMod: WhereArray[Q, C](name),
+	}
+}
+
+type WhereArrayNullMod[Q Filterable, C any] struct {
+	WhereArrayMod[Q, C]
+}
+
+func (w WhereArrayNullMod[Q, C]) IsNull() mods.Where[Q] {
+	return mods.Where[Q]{E: w.name.IsNull()}
+}
+
+func (w WhereArrayNullMod[Q, C]) IsNotNull() mods.Where[Q] {
+	return mods.Where[Q]{E: w.name.IsNotNull()}
+}
+
+func WhereSearch[Q Filterable, C any](name Expression) WhereSearchMod[Q, C] {
+	return WhereSearchMod[Q, C]{
+		WhereMod: Where[Q, C](name),
+	}
+}
+
+// WhereSearchMod adds full text search to [WhereMod] for tsvector columns
+type WhereSearchMod[Q Filterable, C any] struct {
+	WhereMod[Q, C]
+}
+
+// Search matches the column with the query, written like a web search.
+// It uses websearch_to_tsquery with the default text search configuration
+func (w WhereSearchMod[Q, C]) Search(query string) mods.Where[Q] {
+	return mods.Where[Q]{E: w.name.Match(WebSearchToTSQuery("", Arg(query)))}
+}
+
+func WhereSearchNull[Q Filterable, C any](name Expression) WhereSearchNullMod[Q, C] {
+	return WhereSearchNullMod[Q, C]{
+		WhereSearchMod: WhereSearch[Q, C](name),
+	}
+}
+
+type WhereSearchNullMod[Q Filterable, C any] struct {
+	WhereSearchMod[Q, C]
+}
+
+func (w WhereSearchNullMod[Q, C]) IsNull() mods.Where[Q] {
+	return mods.Where[Q]{E: w.name.IsNull()}
+}
+
+func (w WhereSearchNullMod[Q, C]) IsNotNull() mods.Where[Q] {
+	return mods.Where[Q]{E: w.name.IsNotNull()}
+}
@@ -0,0 +1,99 @@
+package sqlite_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/sqlite"
+	"github.com/stephenafamo/bob/dialect/sqlite/dialect"
+	"github.com/stephenafamo/bob/dialect/sqlite/fm"
+	"github.com/stephenafamo/bob/dialect/sqlite/im"
+	"github.com/stephenafamo/bob/dialect/sqlite/sm"
+	"github.com/stephenafamo/bob/dialect/sqlite/wm"
+)
+
+func TestWriteLiteral(t *testing.T) {
+	tests := map[string]struct {
+		value    any
+		expected string
+	}{
+		"null":   {value: nil, expected: "NULL"},
+		"bool":   {value: true, expected: "1"},
+		"float":  {value: float32(0.5), expected: "0.5"},
+		"string": {value: `it's a \ test`, expected: `'it''s a \ test'`},
+		"bytes":  {value: []byte{0, 255}, expected: "X'00ff'"},
+		"time":   {value: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), expected: "'2024-01-02 03:04:05+00:00'"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var b strings.Builder
+			if err := dialect.Dialect.WriteLiteral(&b, tc.value); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expected, b.String()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestBuildDebug(t *testing.T) {
+	tests := map[string]struct {
+		query    bob.Query
+		expected string
+	}{
+		"select with window": {
+			query: sqlite.Select(
+				sm.Columns("id", sqlite.F("rank")(fm.Over(wm.BasedOn("w"))).As("r")),
+				sm.From("scores"),
+				sm.Where(sqlite.Quote("game").EQ(sqlite.Arg("chess"))),
+				sm.Window("w", wm.OrderBy("points")),
+				sm.OrderBy("r"),
+			),
+			expected: `SELECT
+  id,
+  rank() OVER ("w") AS "r"
+FROM
+  scores
+WHERE
+  ("game" = 'chess')
+WINDOW
+  "w" AS (ORDER BY points)
+ORDER BY
+  r`,
+		},
+		"upsert": {
+			query: sqlite.Insert(
+				im.IntoAs("distributors", "d", "did", "dname"),
+				im.Values(sqlite.Arg(8, "Anvil")),
+				im.OnConflict("did").DoUpdate(
+					im.SetExcluded("dname"),
+					im.Where(sqlite.Quote("d", "zipcode").NE(sqlite.Arg([]byte("x")))),
+				),
+			),
+			expected: `INSERT INTO distributors AS "d"("did", "dname")
+VALUES
+  (8, 'Anvil')
+ON CONFLICT (did) DO UPDATE SET "dname" = EXCLUDED."dname"
+WHERE
+  ("d"."zipcode" <> X'78')`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := bob.BuildDebug(context.Background(), tc.query, bob.DebugOptions{InlineArgs: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expected, got.String()); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -24,3 +24,23 @@ func (x Expression) String() string {
 	x.WriteSQL(context.Background(), &w, Dialect, 1) //nolint:errcheck
 	return w.String()
 }
+
+// -> path
+// Gets the JSON representation of the subcomponent at the path.
+// The path can be a JSON path such as '$.a', an object label or an array index
+func (x Expression) JSONGet(path bob.Expression) Expression {
+	return x.OP("->", path)
+}
+
+// ->> path
+// Gets the SQL value of the subcomponent at the path.
+// The path can be a JSON path such as '$.a', an object label or an array index
+func (x Expression) JSONGetText(path bob.Expression) Expression {
+	return x.OP("->>", path)
+}
+
+// MATCH val
+// Matches an FTS5 table, or one of its columns, with a full text query
+func (x Expression) Match(val bob.Expression) Expression {
+	return x.OP("MATCH", val)
+}
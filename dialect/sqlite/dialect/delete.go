@@ -18,8 +18,14 @@ type DeleteQuery struct {
 	clause.Limit
 	clause.Offset
 
+	// SoftDelete holds the assignments used to soft delete rows.
+	// If it is not empty, the query is written as an UPDATE of the
+	// rows that would otherwise have been deleted
+	SoftDelete clause.Set
+
 	bob.Load
 	bob.EmbeddedHook
+	bob.StatementTimeout
 	bob.ContextualModdable[*DeleteQuery]
 }
 
@@ -31,6 +37,10 @@ func (d DeleteQuery) WriteSQL(ctx context.Context, w io.StringWriter, dl bob.Dia
 		return nil, err
 	}
 
+	if len(d.SoftDelete.Set) > 0 {
+		return d.softDeleteQuery().WriteSQL(ctx, w, dl, start)
+	}
+
 	withArgs, err := bob.ExpressIf(ctx, w, dl, start+len(args), d.With,
 		len(d.With.CTEs) > 0, "\n", "")
 	if err != nil {
@@ -76,3 +86,17 @@ func (d DeleteQuery) WriteSQL(ctx context.Context, w io.StringWriter, dl bob.Dia
 
 	return args, nil
 }
+
+// softDeleteQuery returns the UPDATE query used to soft delete the rows
+// matched by the DELETE query
+func (d DeleteQuery) softDeleteQuery() UpdateQuery {
+	return UpdateQuery{
+		With:      d.With,
+		Table:     d.TableRef,
+		Set:       d.SoftDelete,
+		Where:     d.Where,
+		Returning: d.Returning,
+		Limit:     d.Limit,
+		Offset:    d.Offset,
+	}
+}
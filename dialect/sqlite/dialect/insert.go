@@ -20,6 +20,7 @@ type InsertQuery struct {
 
 	bob.Load
 	bob.EmbeddedHook
+	bob.StatementTimeout
 	bob.ContextualModdable[*InsertQuery]
 }
 
@@ -0,0 +1,62 @@
+package dialect
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteLiteral writes the value as a SQLite literal.
+// Booleans are written as 1 and 0, and byte slices as X'...' blobs.
+// It is used to print queries for debugging and must not be used to build queries to execute
+func (d dialect) WriteLiteral(w io.StringWriter, value any) error {
+	v, err := driver.DefaultParameterConverter.ConvertValue(value)
+	if err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case nil:
+		w.WriteString("NULL")
+	case bool:
+		if v {
+			w.WriteString("1")
+		} else {
+			w.WriteString("0")
+		}
+	case int64:
+		w.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		switch {
+		case math.IsNaN(v):
+			w.WriteString("NULL")
+		case math.IsInf(v, 1):
+			w.WriteString("9e999")
+		case math.IsInf(v, -1):
+			w.WriteString("-9e999")
+		default:
+			w.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	case string:
+		w.WriteString("'")
+		w.WriteString(strings.ReplaceAll(v, "'", "''"))
+		w.WriteString("'")
+	case []byte:
+		w.WriteString("X'")
+		w.WriteString(hex.EncodeToString(v))
+		w.WriteString("'")
+	case time.Time:
+		w.WriteString("'")
+		w.WriteString(v.Format("2006-01-02 15:04:05.999999999-07:00"))
+		w.WriteString("'")
+	default:
+		return fmt.Errorf("cannot write %T as a literal", v)
+	}
+
+	return nil
+}
@@ -26,6 +26,7 @@ type SelectQuery struct {
 
 	bob.Load
 	bob.EmbeddedHook
+	bob.StatementTimeout
 	bob.ContextualModdable[*SelectQuery]
 }
 
@@ -0,0 +1,158 @@
+package dialect
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/internal/txexec"
+	"github.com/stephenafamo/scan"
+)
+
+// WithStatementTimeout returns an executor that limits each query to the timeout.
+// SQLite has no statement timeout, so two things are done instead:
+//
+//   - The busy_timeout of the connection is set to the timeout, so the query
+//     does not wait longer than that for locks held by other connections.
+//   - The context of the query is cancelled after the timeout, which makes
+//     the driver interrupt the running query.
+//
+// The busy_timeout is a setting of the connection, so it is only changed when the
+// query is known to run on a single connection. If exec is a [bob.Transaction],
+// it is set before the query and the previous value is restored after it.
+// Otherwise, a transaction is started with [bob.Begin] and committed once the query
+// is done. If exec cannot begin a transaction, only the context is cancelled,
+// and a "database is locked" error is returned as is, since the query did not wait
+// for the lock for the timeout.
+//
+// For queries that return rows, the timeout lasts until the rows are closed
+func (d dialect) WithStatementTimeout(exec bob.Executor, timeout time.Duration) bob.Executor {
+	return timeoutExecutor{exec: exec, timeout: timeout}
+}
+
+type timeoutExecutor struct {
+	exec    bob.Executor
+	timeout time.Duration
+}
+
+// busyTimeoutKey marks the context of a query that runs with the busy_timeout set
+type busyTimeoutKey struct{}
+
+// setBusyTimeout sets the busy_timeout of the connection of the transaction
+// and returns the previous value
+func setBusyTimeout(ctx context.Context, tx bob.Executor, ms int64) (int64, error) {
+	rows, err := tx.QueryContext(ctx, "PRAGMA busy_timeout")
+	if err != nil {
+		return 0, err
+	}
+
+	var previous int64
+	if rows.Next() {
+		err = rows.Scan(&previous)
+	}
+	if closeErr := rows.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = rows.Err()
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "PRAGMA busy_timeout = "+strconv.FormatInt(ms, 10)); err != nil {
+		return 0, err
+	}
+
+	return previous, nil
+}
+
+// begin sets the busy_timeout, starting a transaction if needed.
+// The returned context is cancelled after the timeout and is used to run the query
+func (t timeoutExecutor) begin(ctx context.Context) (context.Context, bob.Executor, txexec.EndFunc, error) {
+	ms := max(t.timeout.Milliseconds(), 1)
+	queryCtx, cancel := context.WithTimeoutCause(ctx, t.timeout, bob.ErrStatementTimeout)
+	busyCtx := context.WithValue(queryCtx, busyTimeoutKey{}, true)
+
+	if tx, ok := t.exec.(bob.Transaction); ok {
+		previous, err := setBusyTimeout(ctx, tx, ms)
+		if err != nil {
+			cancel()
+			return nil, nil, nil, err
+		}
+
+		return busyCtx, tx, func(ctx context.Context, err error) error {
+			cancel()
+			_, restoreErr := tx.ExecContext(ctx, "PRAGMA busy_timeout = "+strconv.FormatInt(previous, 10))
+			if err != nil {
+				return err
+			}
+
+			return restoreErr
+		}, nil
+	}
+
+	tx, err := bob.Begin(ctx, t.exec)
+	if errors.Is(err, bob.ErrCannotBegin) {
+		return queryCtx, t.exec, func(_ context.Context, err error) error {
+			cancel()
+			return err
+		}, nil
+	}
+	if err != nil {
+		cancel()
+		return nil, nil, nil, err
+	}
+
+	previous, err := setBusyTimeout(ctx, tx, ms)
+	if err != nil {
+		cancel()
+		_ = tx.Rollback(ctx)
+		return nil, nil, nil, err
+	}
+
+	return busyCtx, tx, func(ctx context.Context, err error) error {
+		cancel()
+		// the connection goes back to the pool, so the setting is always restored
+		_, restoreErr := tx.ExecContext(ctx, "PRAGMA busy_timeout = "+strconv.FormatInt(previous, 10))
+		if err == nil {
+			err = restoreErr
+		}
+
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return err
+		}
+
+		return tx.Commit(ctx)
+	}, nil
+}
+
+// convert returns a [*bob.StatementTimeoutError] if the query failed
+// after its context was cancelled by the timeout, or waited for a lock
+// for longer than the busy_timeout, if it was set for the query
+func (t timeoutExecutor) convert(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	busy, _ := ctx.Value(busyTimeoutKey{}).(bool)
+	if errors.Is(context.Cause(ctx), bob.ErrStatementTimeout) ||
+		(busy && strings.Contains(err.Error(), "database is locked")) {
+		return &bob.StatementTimeoutError{Timeout: t.timeout, Err: err}
+	}
+
+	return err
+}
+
+func (t timeoutExecutor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return txexec.Exec(ctx, t.begin, t.convert, query, args...)
+}
+
+func (t timeoutExecutor) QueryContext(ctx context.Context, query string, args ...any) (scan.Rows, error) {
+	return txexec.Query(ctx, t.begin, t.convert, query, args...)
+}
@@ -1,6 +1,8 @@
 package dm
 
 import (
+	"time"
+
 	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/dialect/sqlite/dialect"
 	"github.com/stephenafamo/bob/mods"
@@ -50,3 +52,12 @@ func Offset(count any) bob.Mod[*dialect.DeleteQuery] {
 		Count: count,
 	}
 }
+
+// StatementTimeout makes the database cancel the query if it runs for longer than d.
+// SQLite has no statement timeout, so the context of the query is cancelled after d, which interrupts the query,
+// and the busy_timeout of the connection is set to d while the query runs, so it does not wait longer for locks.
+// The busy_timeout is only set if the executor is a transaction or can begin one.
+// A query that is interrupted or waited for a lock for d returns a [*bob.StatementTimeoutError]
+func StatementTimeout(d time.Duration) bob.Mod[*dialect.DeleteQuery] {
+	return mods.StatementTimeout[*dialect.DeleteQuery](d)
+}
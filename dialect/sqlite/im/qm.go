@@ -1,6 +1,8 @@
 package im
 
 import (
+	"time"
+
 	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/clause"
 	"github.com/stephenafamo/bob/dialect/sqlite/dialect"
@@ -64,6 +66,15 @@ func Rows(rows ...[]bob.Expression) bob.Mod[*dialect.InsertQuery] {
 	return mods.Rows[*dialect.InsertQuery](rows)
 }
 
+// ChunkSize sets the maximum number of rows inserted by a single statement.
+// When the query is executed through a generated table, larger inserts are split
+// into multiple statements run in a single transaction. Inserts through a generated table
+// are already split to stay below the parameter limit of the database, so this is only needed
+// for a smaller limit. Executing more rows without a generated table returns [clause.ErrChunkNotSplit].
+func ChunkSize(rows int) bob.Mod[*dialect.InsertQuery] {
+	return mods.ChunkSize[*dialect.InsertQuery](rows)
+}
+
 // Insert from a query
 func Query(q bob.Query) bob.Mod[*dialect.InsertQuery] {
 	return bob.ModFunc[*dialect.InsertQuery](func(i *dialect.InsertQuery) {
@@ -132,3 +143,12 @@ func Where(e bob.Expression) bob.Mod[*clause.ConflictClause] {
 		c.Where.Conditions = append(c.Where.Conditions, e)
 	})
 }
+
+// StatementTimeout makes the database cancel the query if it runs for longer than d.
+// SQLite has no statement timeout, so the context of the query is cancelled after d, which interrupts the query,
+// and the busy_timeout of the connection is set to d while the query runs, so it does not wait longer for locks.
+// The busy_timeout is only set if the executor is a transaction or can begin one.
+// A query that is interrupted or waited for a lock for d returns a [*bob.StatementTimeoutError]
+func StatementTimeout(d time.Duration) bob.Mod[*dialect.InsertQuery] {
+	return mods.StatementTimeout[*dialect.InsertQuery](d)
+}
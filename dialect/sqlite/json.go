@@ -0,0 +1,20 @@
+package sqlite
+
+import (
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/sqlite/dialect"
+)
+
+// Extracts the values at the paths from the JSON.
+// With more than one path, the values are returned as a JSON array
+// SQL: json_extract("data", '$.a', '$.b')
+// Go: sqlite.JSONExtract(sqlite.Quote("data"), sqlite.S("$.a"), sqlite.S("$.b"))
+func JSONExtract(json bob.Expression, paths ...bob.Expression) *dialect.Function {
+	args := make([]any, 0, len(paths)+1)
+	args = append(args, json)
+	for _, p := range paths {
+		args = append(args, p)
+	}
+
+	return dialect.NewFunction("json_extract", args...)
+}
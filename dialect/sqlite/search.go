@@ -0,0 +1,42 @@
+package sqlite
+
+import (
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/sqlite/dialect"
+)
+
+// Returns the bm25 rank of the current row of the FTS5 query.
+// Better matches have lower values. weights are the optional weights of the columns.
+// table is the hidden column with the name of the table, qualified with the alias
+// if the table is aliased, such as sqlite.Quote("d", "docs") for "docs" AS "d"
+// SQL: bm25("docs", ?1, ?2)
+// Go: sqlite.BM25(sqlite.Quote("docs"), 10, 1)
+func BM25(table bob.Expression, weights ...float64) *dialect.Function {
+	args := make([]any, 0, len(weights)+1)
+	args = append(args, table)
+	for _, w := range weights {
+		args = append(args, Arg(w))
+	}
+
+	return dialect.NewFunction("bm25", args...)
+}
+
+// Returns the text of the column in the current row of the FTS5 query,
+// with the matching phrases between open and close.
+// column is the index of the column, starting at 0. table is the same as in [BM25]
+// SQL: highlight("docs", ?1, ?2, ?3)
+// Go: sqlite.Highlight(sqlite.Quote("docs"), 1, "<b>", "</b>")
+func Highlight(table bob.Expression, column int, open, close string) *dialect.Function {
+	return dialect.NewFunction("highlight", table, Arg(column), Arg(open), Arg(close))
+}
+
+// Returns a fragment of the text of the column in the current row of the FTS5 query,
+// with the matching phrases between open and close. ellipsis is added where the text
+// was cut, and tokens is the maximum number of tokens in the fragment.
+// column is the index of the column, starting at 0, or -1 to choose it automatically.
+// table is the same as in [BM25]
+// SQL: snippet("docs", ?1, ?2, ?3, ?4, ?5)
+// Go: sqlite.Snippet(sqlite.Quote("docs"), -1, "<b>", "</b>", "...", 10)
+func Snippet(table bob.Expression, column int, open, close, ellipsis string, tokens int) *dialect.Function {
+	return dialect.NewFunction("snippet", table, Arg(column), Arg(open), Arg(close), Arg(ellipsis), Arg(tokens))
+}
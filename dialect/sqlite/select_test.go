@@ -156,6 +156,36 @@ func TestSelect(t *testing.T) {
                 WHERE ("id" = ?2)`,
 			ExpectedArgs: []any{"123", 100},
 		},
+		"json operators": {
+			Query: sqlite.Select(
+				sm.Columns(
+					sqlite.Quote("data").JSONGet(sqlite.S("$.address")).JSONGetText(sqlite.S("city")),
+					sqlite.JSONExtract(sqlite.Quote("data"), sqlite.S("$.a"), sqlite.S("$.b")),
+				),
+				sm.From("users"),
+				sm.Where(sqlite.Quote("data").JSONGetText(sqlite.Arg("$.active")).EQ(sqlite.Arg(1))),
+			),
+			ExpectedSQL: `SELECT (("data" -> '$.address') ->> 'city'), json_extract("data", '$.a', '$.b')
+				FROM users
+				WHERE (("data" ->> ?1) = ?2)`,
+			ExpectedArgs: []any{"$.active", 1},
+		},
+		"full text search": {
+			Query: sqlite.Select(
+				sm.Columns(
+					sqlite.Highlight(sqlite.Quote("d", "docs"), 0, "<b>", "</b>"),
+					sqlite.Snippet(sqlite.Quote("d", "docs"), -1, "<b>", "</b>", "...", 10),
+				),
+				sm.From("docs").As("d"),
+				sm.Where(sqlite.Quote("d", "docs").Match(sqlite.Arg("fat rat"))),
+				sm.OrderBy(sqlite.BM25(sqlite.Quote("d", "docs"), 10, 1)),
+			),
+			ExpectedSQL: `SELECT highlight("d"."docs", ?1, ?2, ?3), snippet("d"."docs", ?4, ?5, ?6, ?7, ?8)
+				FROM docs AS "d"
+				WHERE ("d"."docs" MATCH ?9)
+				ORDER BY bm25("d"."docs", ?10, ?11)`,
+			ExpectedArgs: []any{0, "<b>", "</b>", -1, "<b>", "</b>", "...", 10, "fat rat", 10.0, 1.0},
+		},
 	}
 
 	testutils.RunTests(t, examples, formatter)
@@ -3,8 +3,10 @@ package sqlite
 import (
 	"context"
 	"reflect"
+	"slices"
 
 	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/clause"
 	"github.com/stephenafamo/bob/dialect/sqlite/dialect"
 	"github.com/stephenafamo/bob/dialect/sqlite/dm"
 	"github.com/stephenafamo/bob/dialect/sqlite/im"
@@ -22,6 +24,11 @@ type (
 	ormDeleteQuery[T any, Tslice ~[]T] = orm.Query[*dialect.DeleteQuery, T, Tslice, bob.SliceTransformer[T, Tslice]]
 )
 
+// maxParams is the maximum number of parameters in a single statement in SQLite,
+// the default SQLITE_MAX_VARIABLE_NUMBER since 3.32.0.
+// Inserts with more are split into chunks, see [clause.Values.ParamLimit]
+const maxParams = 32766
+
 func NewTable[T any, Tset setter[T], C bob.Expression](schema, tableName string, columns C) *Table[T, []T, Tset, C] {
 	return NewTablex[T, []T, Tset](schema, tableName, columns, nil)
 }
@@ -72,6 +79,7 @@ func (t *Table[T, Tslice, Tset, C]) Insert(queryMods ...bob.Mod[*dialect.InsertQ
 		ExecQuery: orm.ExecQuery[*dialect.InsertQuery]{
 			BaseQuery: Insert(im.Into(t.NameAsExpr())),
 			Hooks:     &t.InsertQueryHooks,
+			Table:     t.alias,
 		},
 		Scanner: t.scanner,
 	}
@@ -85,17 +93,58 @@ func (t *Table[T, Tslice, Tset, C]) Insert(queryMods ...bob.Mod[*dialect.InsertQ
 		},
 	)
 
+	q.Expression.SetParamLimit(maxParams)
 	q.Apply(queryMods...)
 
 	return q
 }
 
+// Upsert starts an insert query for the setters that updates the existing row
+// instead when an inserted row conflicts on the given columns.
+// Only the columns set in the setters are updated, so all setters must set
+// the same columns, otherwise the query fails with [orm.ErrMixedUpsertSetters].
+// If the table has a tenant column, it is never updated, and conflicting rows
+// of another tenant are left unchanged and not returned
+func (t *Table[T, Tslice, Tset, C]) Upsert(conflictCols []string, setters ...Tset) *ormInsertQuery[T, Tslice] {
+	target := make([]any, len(conflictCols))
+	for i, col := range conflictCols {
+		target[i] = Quote(col)
+	}
+
+	updateCols, err := orm.UpsertColumns(slices.Concat(conflictCols, []string{t.tenantCol}), setters...)
+	if len(updateCols) == 0 && len(conflictCols) > 0 {
+		// Nothing to update, but the conflicting row is still returned
+		updateCols = conflictCols[:1]
+	}
+
+	doUpdate := []bob.Mod[*clause.ConflictClause]{im.SetExcluded(updateCols...)}
+	if t.tenantCol != "" {
+		doUpdate = append(doUpdate, im.Where(Quote(t.alias, t.tenantCol).EQ(im.Excluded(t.tenantCol))))
+	}
+
+	q := t.Insert(
+		bob.ToMods(setters...),
+		im.OnConflict(target...).DoUpdate(doUpdate...),
+	)
+
+	if err != nil {
+		q.Expression.AppendContextualModFunc(
+			func(ctx context.Context, _ *dialect.InsertQuery) (context.Context, error) {
+				return ctx, err
+			},
+		)
+	}
+
+	return q
+}
+
 // Starts an Update query for this table
 func (t *Table[T, Tslice, Tset, C]) Update(queryMods ...bob.Mod[*dialect.UpdateQuery]) *ormUpdateQuery[T, Tslice] {
 	q := &ormUpdateQuery[T, Tslice]{
 		ExecQuery: orm.ExecQuery[*dialect.UpdateQuery]{
 			BaseQuery: Update(um.Table(t.NameAsExpr())),
 			Hooks:     &t.UpdateQueryHooks,
+			Table:     t.alias,
 		},
 		Scanner: t.scanner,
 	}
@@ -109,17 +158,62 @@ func (t *Table[T, Tslice, Tset, C]) Update(queryMods ...bob.Mod[*dialect.UpdateQ
 		},
 	)
 
+	if t.tenantCol != "" {
+		q.Expression.AppendContextualMod(orm.TenantMod[*dialect.UpdateQuery](t.alias, t.tenantCol))
+	}
+
 	q.Apply(queryMods...)
 
 	return q
 }
 
+// WithSoftDelete sets the column used to soft delete rows.
+// Delete sets the column to the current time instead of removing the rows,
+// and queries exclude rows where it is not NULL.
+// Use HardDelete to remove rows permanently
+func (t *Table[T, Tslice, Tset, C]) WithSoftDelete(column string) *Table[T, Tslice, Tset, C] {
+	t.softDeleteCol = column
+	return t
+}
+
+// WithTenant sets the column that holds the tenant of each row.
+// Queries, updates and deletes only affect the rows of the tenant set with [orm.WithTenant],
+// and generated setters insert the rows with it.
+// They fail with [orm.ErrMissingTenant] if there is none
+func (t *Table[T, Tslice, Tset, C]) WithTenant(column string) *Table[T, Tslice, Tset, C] {
+	t.tenantCol = column
+	return t
+}
+
 // Starts a Delete query for this table
+// If the table has a soft delete column, the matching rows that are not
+// deleted yet are updated to set the column to the current time instead, as given by orm.Now
 func (t *Table[T, Tslice, Tset, C]) Delete(queryMods ...bob.Mod[*dialect.DeleteQuery]) *ormDeleteQuery[T, Tslice] {
+	q := t.HardDelete()
+
+	if t.softDeleteCol != "" {
+		q.Expression.AppendContextualModFunc(
+			func(ctx context.Context, q *dialect.DeleteQuery) (context.Context, error) {
+				q.SoftDelete.AppendSet(Quote(t.softDeleteCol).EQ(Arg(orm.Now(ctx))))
+				q.AppendWhere(Quote(t.alias, t.softDeleteCol).IsNull())
+				return ctx, nil
+			},
+		)
+	}
+
+	q.Apply(queryMods...)
+
+	return q
+}
+
+// Starts a Delete query for this table that removes the rows
+// even if the table has a soft delete column
+func (t *Table[T, Tslice, Tset, C]) HardDelete(queryMods ...bob.Mod[*dialect.DeleteQuery]) *ormDeleteQuery[T, Tslice] {
 	q := &ormDeleteQuery[T, Tslice]{
 		ExecQuery: orm.ExecQuery[*dialect.DeleteQuery]{
 			BaseQuery: Delete(dm.From(t.NameAsExpr())),
 			Hooks:     &t.DeleteQueryHooks,
+			Table:     t.alias,
 		},
 		Scanner: t.scanner,
 	}
@@ -133,6 +227,10 @@ func (t *Table[T, Tslice, Tset, C]) Delete(queryMods ...bob.Mod[*dialect.DeleteQ
 		},
 	)
 
+	if t.tenantCol != "" {
+		q.Expression.AppendContextualMod(orm.TenantMod[*dialect.DeleteQuery](t.alias, t.tenantCol))
+	}
+
 	q.Apply(queryMods...)
 
 	return q
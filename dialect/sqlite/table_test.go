@@ -0,0 +1,554 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/clause"
+	"github.com/stephenafamo/bob/dialect/sqlite/dialect"
+	"github.com/stephenafamo/bob/dialect/sqlite/dm"
+	"github.com/stephenafamo/bob/dialect/sqlite/im"
+	"github.com/stephenafamo/bob/dialect/sqlite/um"
+	"github.com/stephenafamo/bob/expr"
+	"github.com/stephenafamo/bob/orm"
+	"github.com/stephenafamo/scan"
+	_ "modernc.org/sqlite"
+)
+
+type someStructSetter struct {
+	Name  *string `db:"name"`
+	Email *string `db:"email"`
+}
+
+func (s someStructSetter) SetColumns() []string {
+	return []string{"name", "email"}
+}
+
+func (s someStructSetter) Apply(q *dialect.InsertQuery) {
+	if len(q.TableRef.Columns) == 0 {
+		q.TableRef.Columns = s.SetColumns()
+	}
+	q.AppendValues(Arg(s.Name), Arg(s.Email))
+}
+
+func (s someStructSetter) UpdateMod() bob.Mod[*dialect.UpdateQuery] {
+	return um.SetCol("name").ToArg(s.Name)
+}
+
+// someStructSlice counts how often its AfterQueryHook is called
+// with the counter in the context, see withHookCounter
+type someStructSlice []*someStruct
+
+type hookCounterKey struct{}
+
+func withHookCounter(ctx context.Context, calls *int) context.Context {
+	return context.WithValue(ctx, hookCounterKey{}, calls)
+}
+
+func (someStructSlice) AfterQueryHook(ctx context.Context, _ bob.Executor, _ bob.QueryType) error {
+	if calls, ok := ctx.Value(hookCounterKey{}).(*int); ok {
+		*calls++
+	}
+	return nil
+}
+
+var someStructTable = NewTablex[*someStruct, someStructSlice, *someStructSetter](
+	"", "some_struct", expr.ColsForStruct[someStruct]("some_struct"), nil,
+)
+
+// countingExecutor counts the statements executed in a transaction
+type countingExecutor struct {
+	bob.Transaction
+	statements int
+}
+
+func (c *countingExecutor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	c.statements++
+	return c.Transaction.ExecContext(ctx, query, args...)
+}
+
+func (c *countingExecutor) QueryContext(ctx context.Context, query string, args ...any) (scan.Rows, error) {
+	c.statements++
+	return c.Transaction.QueryContext(ctx, query, args...)
+}
+
+func TestInsertChunked(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := bob.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(ctx, `CREATE TABLE some_struct (id INTEGER PRIMARY KEY, name TEXT NOT NULL, email TEXT NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+
+	setters := make([]bob.Mod[*dialect.InsertQuery], 0, 8)
+	for i := range 7 {
+		name, email := fmt.Sprintf("name %d", i), fmt.Sprintf("email %d", i)
+		setters = append(setters, &someStructSetter{Name: &name, Email: &email})
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(ctx)
+
+	exec := &countingExecutor{Transaction: tx}
+
+	var afterQueryHookCalls int
+	hookCtx := withHookCounter(ctx, &afterQueryHookCalls)
+
+	var beforeHookCalls int
+	query := someStructTable.Insert(append(setters, im.ChunkSize(3))...)
+	query.Expression.AppendHooks(func(ctx context.Context, _ bob.Executor) (context.Context, error) {
+		beforeHookCalls++
+		return ctx, nil
+	})
+
+	inserted, err := query.All(hookCtx, exec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exec.statements != 3 {
+		t.Fatalf("expected 3 statements, got %d", exec.statements)
+	}
+
+	if beforeHookCalls != 1 {
+		t.Fatalf("expected the query hooks to run once, ran %d times", beforeHookCalls)
+	}
+
+	if afterQueryHookCalls != 1 {
+		t.Fatalf("expected AfterQueryHook to run once, ran %d times", afterQueryHookCalls)
+	}
+
+	if len(inserted) != 7 {
+		t.Fatalf("expected 7 inserted rows, got %d", len(inserted))
+	}
+
+	for i, row := range inserted {
+		if row.ID != int64(i+1) || row.Name != fmt.Sprintf("name %d", i) {
+			t.Fatalf("unexpected row %d: %#v", i, row)
+		}
+	}
+
+	exec.statements = 0
+	affected, err := someStructTable.Insert(append(setters, im.ChunkSize(5))...).Exec(ctx, exec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exec.statements != 2 {
+		t.Fatalf("expected 2 statements, got %d", exec.statements)
+	}
+
+	if affected != 7 {
+		t.Fatalf("expected 7 affected rows, got %d", affected)
+	}
+
+	exec.statements = 0
+	first, err := someStructTable.Insert(append(setters, im.ChunkSize(4))...).One(ctx, exec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exec.statements != 2 {
+		t.Fatalf("expected 2 statements, got %d", exec.statements)
+	}
+
+	if first.ID != 15 {
+		t.Fatalf("expected the first inserted row, got %#v", first)
+	}
+}
+
+func mustBegin(t *testing.T, db bob.DB) bob.Tx {
+	t.Helper()
+	tx, err := db.Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestInsertChunkedInTransaction(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := bob.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(ctx, `CREATE TABLE some_struct (id INTEGER PRIMARY KEY, name TEXT NOT NULL UNIQUE, email TEXT NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+
+	setters := func(names ...string) []bob.Mod[*dialect.InsertQuery] {
+		mods := make([]bob.Mod[*dialect.InsertQuery], len(names))
+		for i, name := range names {
+			mods[i] = &someStructSetter{Name: &name, Email: &name}
+		}
+		return mods
+	}
+
+	count := func(t *testing.T) int64 {
+		t.Helper()
+		n, err := someStructTable.Query().Count(ctx, db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	t.Run("param limit", func(t *testing.T) {
+		// 2 values per row, so a limit of 5 fits 2 rows per statement
+		query := someStructTable.Insert(setters("a", "b", "c", "d", "e")...)
+		query.Expression.SetParamLimit(5)
+
+		if chunks, err := query.Expression.Chunks(ctx, dialect.Dialect, "", nil); err != nil || len(chunks) != 3 {
+			t.Fatalf("expected 3 chunks, got %d (%v)", len(chunks), err)
+		}
+
+		if _, err := query.Exec(ctx, db); err != nil {
+			t.Fatal(err)
+		}
+
+		if n := count(t); n != 5 {
+			t.Fatalf("expected 5 rows, got %d", n)
+		}
+	})
+
+	t.Run("param limit with args in the rest of the statement", func(t *testing.T) {
+		// 2 values per row and 1 in RETURNING, so a limit of 6 fits 2 rows per statement
+		query := someStructTable.Insert(append(setters("n", "o", "p", "q", "r"), im.Returning(Arg(1)))...)
+		query.Expression.SetParamLimit(6)
+
+		exec := &countingExecutor{Transaction: mustBegin(t, db)}
+		defer exec.Rollback(ctx)
+
+		if _, err := query.Exec(ctx, exec); err != nil {
+			t.Fatal(err)
+		}
+
+		if exec.statements != 3 {
+			t.Fatalf("expected 3 statements, got %d", exec.statements)
+		}
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		long := strings.Repeat("x", 100)
+		query := someStructTable.Insert(setters(long+"1", long+"2", long+"3")...)
+		query.Expression.SetSizeLimit(500)
+
+		exec := &countingExecutor{Transaction: mustBegin(t, db)}
+		defer exec.Rollback(ctx)
+
+		if _, err := query.Exec(ctx, exec); err != nil {
+			t.Fatal(err)
+		}
+
+		if exec.statements != 3 {
+			t.Fatalf("expected a statement for each row, got %d", exec.statements)
+		}
+	})
+
+	t.Run("not atomic without a transaction", func(t *testing.T) {
+		exec := struct{ bob.Executor }{db}
+		_, err := someStructTable.Insert(append(setters("s", "t", "u"), im.ChunkSize(2))...).Exec(ctx, exec)
+		if !errors.Is(err, orm.ErrChunksNotAtomic) {
+			t.Fatalf("expected orm.ErrChunksNotAtomic, got %v", err)
+		}
+
+		if n := count(t); n != 5 {
+			t.Fatalf("expected nothing to be inserted, got %d rows", n)
+		}
+	})
+
+	t.Run("rolls back every chunk", func(t *testing.T) {
+		// the second chunk conflicts with an existing row
+		_, err := someStructTable.Insert(append(setters("f", "g", "a"), im.ChunkSize(2))...).All(ctx, db)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		if n := count(t); n != 5 {
+			t.Fatalf("expected the first chunk to be rolled back, got %d rows", n)
+		}
+	})
+
+	t.Run("savepoint in a transaction", func(t *testing.T) {
+		err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bob.Transaction) error {
+			_, err := someStructTable.Insert(append(setters("h", "i", "a"), im.ChunkSize(2))...).Exec(ctx, tx)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			_, err = someStructTable.Insert(setters("j")...).Exec(ctx, tx)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n := count(t); n != 6 {
+			t.Fatalf("expected only the last insert to be committed, got %d rows", n)
+		}
+	})
+
+	t.Run("not split without the ORM", func(t *testing.T) {
+		query := Insert(append(setters("k", "l", "m"), im.Into("some_struct", "name", "email"), im.ChunkSize(2))...)
+		if _, err := query.Exec(ctx, db); !errors.Is(err, clause.ErrChunkNotSplit) {
+			t.Fatalf("expected clause.ErrChunkNotSplit, got %v", err)
+		}
+	})
+}
+
+type softStruct struct {
+	ID        int64        `db:"id,pk"`
+	Name      string       `db:"name"`
+	DeletedAt sql.NullTime `db:"deleted_at"`
+}
+
+type softStructSetter struct {
+	Name *string `db:"name"`
+}
+
+func (s softStructSetter) SetColumns() []string {
+	return []string{"name"}
+}
+
+func (s softStructSetter) Apply(q *dialect.InsertQuery) {
+	q.AppendValues(Arg(s.Name))
+}
+
+func (s softStructSetter) UpdateMod() bob.Mod[*dialect.UpdateQuery] {
+	return um.Set(Quote("name").EQ(Arg(s.Name)))
+}
+
+var softStructTable = NewTablex[*softStruct, []*softStruct, *softStructSetter](
+	"", "soft_struct", expr.ColsForStruct[softStruct]("soft_struct"), nil,
+).WithSoftDelete("deleted_at")
+
+func TestTableSoftDelete(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := bob.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE soft_struct (id INTEGER PRIMARY KEY, name TEXT NOT NULL, deleted_at DATETIME);
+		INSERT INTO soft_struct (id, name) VALUES (1, 'a'), (2, 'b'), (3, 'c');
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	count := func(ctx context.Context) int64 {
+		t.Helper()
+		n, err := softStructTable.Query().Count(ctx, db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	deletedAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := orm.WithClock(ctx, func() time.Time { return deletedAt })
+
+	deleted, err := softStructTable.Delete(dm.Where(Quote("id").EQ(Arg(1)))).All(clock, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(deleted) != 1 || !deleted[0].DeletedAt.Time.Equal(deletedAt) {
+		t.Fatalf("expected the soft deleted row to be returned, got %#v", deleted)
+	}
+
+	if n := count(ctx); n != 2 {
+		t.Fatalf("expected 2 rows, got %d", n)
+	}
+
+	if n := count(orm.WithDeleted(ctx)); n != 3 {
+		t.Fatalf("expected 3 rows with deleted, got %d", n)
+	}
+
+	// already deleted rows are not deleted again
+	affected, err := softStructTable.Delete().Exec(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if affected != 2 {
+		t.Fatalf("expected 2 affected rows, got %d", affected)
+	}
+
+	affected, err = softStructTable.HardDelete(dm.Where(Quote("id").EQ(Arg(1)))).Exec(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if affected != 1 {
+		t.Fatalf("expected 1 affected row, got %d", affected)
+	}
+
+	if n := count(orm.WithDeleted(ctx)); n != 2 {
+		t.Fatalf("expected 2 rows with deleted, got %d", n)
+	}
+}
+
+func TestTableUpsert(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := bob.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(ctx, `CREATE TABLE some_struct (id INTEGER PRIMARY KEY, name TEXT NOT NULL, email TEXT NOT NULL UNIQUE)`); err != nil {
+		t.Fatal(err)
+	}
+
+	setter := func(name, email string) *someStructSetter {
+		return &someStructSetter{Name: &name, Email: &email}
+	}
+
+	existing, err := someStructTable.Insert(setter("old", "a@example.com")).One(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	upserted, err := someStructTable.Upsert(
+		[]string{"email"},
+		setter("new", "a@example.com"),
+		setter("other", "b@example.com"),
+	).All(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(upserted) != 2 {
+		t.Fatalf("expected 2 upserted rows, got %d", len(upserted))
+	}
+
+	if upserted[0].ID != existing.ID || upserted[0].Name != "new" {
+		t.Fatalf("expected the existing row to be updated, got %#v", upserted[0])
+	}
+
+	if upserted[1].ID == existing.ID || upserted[1].Name != "other" {
+		t.Fatalf("expected a new row to be inserted, got %#v", upserted[1])
+	}
+
+	sql, _, err := someStructTable.Upsert([]string{"email"}, setter("new", "a@example.com")).Build(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(sql, `ON CONFLICT ("email") DO UPDATE SET`) ||
+		!strings.Contains(sql, `"name" = EXCLUDED."name"`) {
+		t.Fatalf("unexpected upsert query: %s", sql)
+	}
+}
+
+type tenantStruct struct {
+	ID       int64  `db:"id,pk"`
+	TenantID int64  `db:"tenant_id"`
+	Name     string `db:"name"`
+	Value    string `db:"value"`
+}
+
+type tenantStructSetter struct {
+	Name  string
+	Value string
+}
+
+func (s tenantStructSetter) SetColumns() []string {
+	return []string{"tenant_id", "name", "value"}
+}
+
+// Apply inserts the tenant in the context like the generated setters
+func (s tenantStructSetter) Apply(q *dialect.InsertQuery) {
+	q.TableRef.Columns = s.SetColumns()
+	q.AppendValues(
+		bob.ExpressionFunc(func(ctx context.Context, w io.StringWriter, d bob.Dialect, start int) ([]any, error) {
+			tenant, _, err := orm.Tenant(ctx, "tenant_struct")
+			if err != nil {
+				return nil, err
+			}
+			return Arg(tenant).WriteSQL(ctx, w, d, start)
+		}),
+		Arg(s.Name),
+		Arg(s.Value),
+	)
+}
+
+func (s tenantStructSetter) UpdateMod() bob.Mod[*dialect.UpdateQuery] {
+	return um.SetCol("value").ToArg(s.Value)
+}
+
+func TestTableUpsertTenant(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := bob.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(ctx, `CREATE TABLE tenant_struct (
+		id INTEGER PRIMARY KEY, tenant_id INTEGER NOT NULL, name TEXT NOT NULL UNIQUE, value TEXT NOT NULL
+	)`); err != nil {
+		t.Fatal(err)
+	}
+
+	table := NewTable[tenantStruct, tenantStructSetter]("", "tenant_struct", expr.ColsForStruct[tenantStruct]("tenant_struct")).
+		WithTenant("tenant_id")
+
+	tenant1, tenant2 := orm.WithTenant(ctx, int64(1)), orm.WithTenant(ctx, int64(2))
+	if _, err := table.Insert(tenantStructSetter{Name: "a", Value: "old"}).One(tenant1, db); err != nil {
+		t.Fatal(err)
+	}
+
+	// the row of tenant 1 is neither updated nor returned
+	upserted, err := table.Upsert([]string{"name"}, tenantStructSetter{Name: "a", Value: "new"}).All(tenant2, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(upserted) != 0 {
+		t.Fatalf("expected no upserted rows, got %#v", upserted)
+	}
+
+	upserted, err = table.Upsert([]string{"name"}, tenantStructSetter{Name: "a", Value: "new"}).All(tenant1, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(upserted) != 1 || upserted[0].TenantID != 1 || upserted[0].Value != "new" {
+		t.Fatalf("expected the row of tenant 1 to be updated, got %#v", upserted)
+	}
+
+	sql, _, err := table.Upsert([]string{"name"}, tenantStructSetter{Name: "a", Value: "new"}).Build(tenant1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sql, "DO UPDATE SET\n\"value\" = EXCLUDED.\"value\"\nWHERE (\"tenant_struct\".\"tenant_id\" = EXCLUDED.\"tenant_id\")") {
+		t.Fatalf("unexpected upsert query: %s", sql)
+	}
+}
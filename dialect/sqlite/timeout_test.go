@@ -0,0 +1,124 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/sqlite"
+	"github.com/stephenafamo/bob/dialect/sqlite/im"
+	"github.com/stephenafamo/bob/dialect/sqlite/sm"
+	"github.com/stephenafamo/scan"
+	_ "modernc.org/sqlite"
+)
+
+func TestStatementTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	exec := bob.NewDB(db)
+
+	// counts to a very large number, so the query is always interrupted
+	slow := func(timeout time.Duration) bob.Query {
+		return sqlite.Select(
+			sm.With("c", "n").As(sqlite.RawQuery("SELECT 1 UNION ALL SELECT n + 1 FROM c")),
+			sm.Columns("count(*)"),
+			sm.From("c"),
+			sm.StatementTimeout(timeout),
+		)
+	}
+
+	start := time.Now()
+	_, err = bob.One(ctx, exec, slow(50*time.Millisecond), scan.SingleColumnMapper[int64])
+	if !errors.Is(err, bob.ErrStatementTimeout) {
+		t.Fatalf("expected a statement timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("the query was not interrupted in time, took %s", elapsed)
+	}
+
+	var typed *bob.StatementTimeoutError
+	if !errors.As(err, &typed) || typed.Timeout != 50*time.Millisecond {
+		t.Fatalf("unexpected error %#v", err)
+	}
+
+	// the connection can still be used after the timeout
+	n, err := bob.One(ctx, exec, sqlite.Select(sm.Columns("1"), sm.StatementTimeout(time.Second)), scan.SingleColumnMapper[int64])
+	if err != nil || n != 1 {
+		t.Fatalf("unexpected result %d, %v", n, err)
+	}
+}
+
+func TestStatementTimeoutBusy(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "busy.db")
+
+	locker, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer locker.Close()
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	exec := bob.NewDB(db)
+
+	if _, err := locker.ExecContext(ctx, "CREATE TABLE users (id INTEGER PRIMARY KEY)"); err != nil {
+		t.Fatal(err)
+	}
+
+	// holds the write lock until it is rolled back
+	tx, err := locker.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, "INSERT INTO users (id) VALUES (1)"); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	_, err = bob.Exec(ctx, exec, sqlite.Insert(
+		im.Into("users", "id"),
+		im.Values(sqlite.Arg(2)),
+		im.StatementTimeout(100*time.Millisecond),
+	))
+	if !errors.Is(err, bob.ErrStatementTimeout) {
+		t.Fatalf("expected a statement timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond || elapsed > 5*time.Second {
+		t.Fatalf("expected the query to wait for the lock for the timeout, took %s", elapsed)
+	}
+
+	// the busy_timeout of the connection is restored
+	busy, err := bob.One(ctx, exec, sqlite.RawQuery("PRAGMA busy_timeout"), scan.SingleColumnMapper[int64])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if busy != 0 {
+		t.Fatalf("expected the busy_timeout to be restored, got %d", busy)
+	}
+
+	// without a transaction the busy_timeout is not set,
+	// so the lock error is returned as is
+	_, err = bob.Exec(ctx, struct{ bob.Executor }{exec}, sqlite.Insert(
+		im.Into("users", "id"),
+		im.Values(sqlite.Arg(3)),
+		im.StatementTimeout(100*time.Millisecond),
+	))
+	if err == nil || errors.Is(err, bob.ErrStatementTimeout) {
+		t.Fatalf("expected the lock error not to be a statement timeout, got %v", err)
+	}
+}
@@ -3,12 +3,16 @@ package sqlite
 import (
 	"bytes"
 	"context"
+	"errors"
+	"slices"
 	"testing"
 
 	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/dialect/sqlite/dialect"
 	"github.com/stephenafamo/bob/dialect/sqlite/sm"
 	"github.com/stephenafamo/bob/expr"
+	"github.com/stephenafamo/bob/orm"
+	_ "modernc.org/sqlite"
 )
 
 type someStruct struct {
@@ -58,3 +62,110 @@ func viewToString(t *testing.T, query *ViewQuery[*someStruct, []*someStruct]) st
 	t.Helper()
 	return selectToString(t, query.BaseQuery, 3)
 }
+
+func TestViewQueryPaginate(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := bob.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE some_struct (id INTEGER PRIMARY KEY, name TEXT NOT NULL, email TEXT NOT NULL);
+		INSERT INTO some_struct (id, name, email) VALUES
+			(1, 'a', 'x'), (2, 'b', 'x'), (3, 'a', 'y'), (4, 'c', 'y'), (5, 'b', 'z');
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		mods  []bob.Mod[*dialect.SelectQuery]
+		pages [][]int64
+	}{
+		"single column": {
+			mods:  []bob.Mod[*dialect.SelectQuery]{sm.OrderBy("id")},
+			pages: [][]int64{{1, 2}, {3, 4}, {5}},
+		},
+		"same direction": {
+			mods:  []bob.Mod[*dialect.SelectQuery]{sm.OrderBy("name").Desc(), sm.OrderBy("id").Desc()},
+			pages: [][]int64{{4, 5}, {2, 3}, {1}},
+		},
+		"mixed direction": {
+			mods:  []bob.Mod[*dialect.SelectQuery]{sm.OrderBy("name"), sm.OrderBy("id").Desc()},
+			pages: [][]int64{{3, 1}, {5, 2}, {4}},
+		},
+		"with where": {
+			mods:  []bob.Mod[*dialect.SelectQuery]{sm.Where(Quote("email").NE(Arg("z"))), sm.OrderBy("email"), sm.OrderBy("id")},
+			pages: [][]int64{{1, 2}, {3, 4}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			q := someStructViewNoSchema.Query(tc.mods...)
+
+			var cursor orm.Cursor
+			var prevs []orm.Cursor
+			for i, expected := range tc.pages {
+				page, err := q.Paginate(ctx, db, cursor, 2)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if got := pageIDs(page.Items); !slices.Equal(got, expected) {
+					t.Fatalf("page %d: expected %v, got %v", i, expected, got)
+				}
+
+				if (i == 0) != (page.Prev == "") {
+					t.Fatalf("page %d: unexpected prev cursor %q", i, page.Prev)
+				}
+
+				if (i == len(tc.pages)-1) != (page.Next == "") {
+					t.Fatalf("page %d: unexpected next cursor %q", i, page.Next)
+				}
+
+				prevs = append(prevs, page.Prev)
+				cursor = page.Next
+			}
+
+			// walk back from the last page
+			for i := len(tc.pages) - 1; i > 0; i-- {
+				page, err := q.Paginate(ctx, db, prevs[i], 2)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if got := pageIDs(page.Items); !slices.Equal(got, tc.pages[i-1]) {
+					t.Fatalf("prev of page %d: expected %v, got %v", i, tc.pages[i-1], got)
+				}
+
+				if (i == 1) != (page.Prev == "") {
+					t.Fatalf("prev of page %d: unexpected prev cursor %q", i, page.Prev)
+				}
+
+				if page.Next == "" {
+					t.Fatalf("prev of page %d: missing next cursor", i)
+				}
+			}
+		})
+	}
+
+	if _, err := someStructViewNoSchema.Query().Paginate(ctx, db, "", 2); !errors.Is(err, orm.ErrNoKeysetOrder) {
+		t.Fatalf("expected %v, got %v", orm.ErrNoKeysetOrder, err)
+	}
+
+	if _, err := someStructViewNoSchema.Query(sm.OrderBy("id")).Paginate(ctx, db, "not a cursor", 2); !errors.Is(err, orm.ErrInvalidCursor) {
+		t.Fatalf("expected %v, got %v", orm.ErrInvalidCursor, err)
+	}
+}
+
+func pageIDs(items []*someStruct) []int64 {
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
@@ -0,0 +1,319 @@
+package pgx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/scan"
+)
+
+var (
+	// ErrBatchNotSent is returned by [Future.Get] if the batch was not sent
+	ErrBatchNotSent = errors.New("pgx: the batch has not been sent")
+	// ErrBatchAlreadySent is returned when a batch is sent or queued to after it was sent
+	ErrBatchAlreadySent = errors.New("pgx: the batch has already been sent")
+)
+
+// Batcher can send a [pgx.Batch].
+// It is implemented by [Pool], [PoolConn], [Conn] and [Tx]
+type Batcher interface {
+	bob.Executor
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+var (
+	_ Batcher = Pool{}
+	_ Batcher = PoolConn{}
+	_ Batcher = Conn{}
+	_ Batcher = Tx{}
+)
+
+// Future holds the result of a query in a [Batch].
+// The result is available once the batch is sent
+type Future[T any] struct {
+	value T
+	err   error
+	done  bool
+}
+
+// Get returns the result of the query, or [ErrBatchNotSent] if the batch
+// has not been sent yet
+func (f *Future[T]) Get() (T, error) {
+	if !f.done {
+		var zero T
+		return zero, ErrBatchNotSent
+	}
+
+	return f.value, f.err
+}
+
+func (f *Future[T]) resolve(value T, err error) {
+	f.value, f.err, f.done = value, err, true
+}
+
+type batchItem struct {
+	query bob.Query
+	// read reads the result of the query from the batch results
+	read func(context.Context, pgx.BatchResults) error
+	// finish runs the loaders and hooks of the query after the batch is closed
+	finish func(context.Context, bob.Executor) error
+	// fail resolves the future with the error
+	fail func(error)
+}
+
+// Batch queues bob queries to send them to the database in one round trip.
+// Queries are added with [QueueOne], [QueueAll], [QueueAllx] and [QueueExec],
+// which return a [Future] for the result.
+//
+// When the batch is sent, the hooks of each query are run and the queries are built,
+// then the results are scanned, and the loaders and AfterQueryHooks are run
+// in the order the queries were queued
+type Batch struct {
+	items []batchItem
+	sent  bool
+}
+
+// NewBatch returns an empty batch
+func NewBatch() *Batch {
+	return &Batch{}
+}
+
+// Len returns the number of queued queries
+func (b *Batch) Len() int {
+	return len(b.items)
+}
+
+func (b *Batch) queue(item batchItem) {
+	if b.sent {
+		item.fail(ErrBatchAlreadySent)
+		return
+	}
+
+	b.items = append(b.items, item)
+}
+
+// QueueOne queues a query that returns a single row, like [bob.One]
+func QueueOne[T any](b *Batch, q bob.Query, m scan.Mapper[T]) *Future[T] {
+	f := &Future[T]{}
+	m = withMapperMods(q, m)
+
+	var t T
+	b.queue(batchItem{
+		query: q,
+		fail:  func(err error) { f.resolve(t, err) },
+		read: func(ctx context.Context, br pgx.BatchResults) error {
+			pgxRows, err := br.Query()
+			if err != nil {
+				return err
+			}
+
+			t, err = scan.OneFromRows(ctx, m, rows{pgxRows})
+			return err
+		},
+		finish: func(ctx context.Context, exec bob.Executor) error {
+			if err := load(ctx, exec, q, t); err != nil {
+				return err
+			}
+
+			if h, ok := any(t).(bob.HookableType); ok {
+				if err := h.AfterQueryHook(ctx, exec, q.Type()); err != nil {
+					return err
+				}
+			}
+
+			f.resolve(t, nil)
+			return nil
+		},
+	})
+
+	return f
+}
+
+// QueueAll queues a query that returns multiple rows, like [bob.All]
+func QueueAll[T any](b *Batch, q bob.Query, m scan.Mapper[T]) *Future[[]T] {
+	return QueueAllx[bob.SliceTransformer[T, []T]](b, q, m)
+}
+
+// QueueAllx queues a query that returns multiple rows and transforms them, like [bob.Allx]
+func QueueAllx[Tr bob.Transformer[T, V], T, V any](b *Batch, q bob.Query, m scan.Mapper[T]) *Future[V] {
+	f := &Future[V]{}
+
+	var typedSlice V
+	_, isTransformedHookable := any(typedSlice).(bob.HookableType)
+	_, isSingleHookable := any(*new(T)).(bob.HookableType)
+	// If the transformed type is not hookable, but the single type is,
+	// return an error
+	if !isTransformedHookable && isSingleHookable {
+		f.resolve(typedSlice, bob.ErrHookableTypeMismatch)
+		return f
+	}
+
+	m = withMapperMods(q, m)
+
+	b.queue(batchItem{
+		query: q,
+		fail:  func(err error) { f.resolve(typedSlice, err) },
+		read: func(ctx context.Context, br pgx.BatchResults) error {
+			pgxRows, err := br.Query()
+			if err != nil {
+				return err
+			}
+
+			rawSlice, err := scan.AllFromRows(ctx, m, rows{pgxRows})
+			if err != nil {
+				return err
+			}
+
+			var transformer Tr
+			typedSlice, err = transformer.TransformScanned(rawSlice)
+			return err
+		},
+		finish: func(ctx context.Context, exec bob.Executor) error {
+			if err := load(ctx, exec, q, typedSlice); err != nil {
+				return err
+			}
+
+			if h, ok := any(typedSlice).(bob.HookableType); ok {
+				if err := h.AfterQueryHook(ctx, exec, q.Type()); err != nil {
+					return err
+				}
+			}
+
+			f.resolve(typedSlice, nil)
+			return nil
+		},
+	})
+
+	return f
+}
+
+// QueueExec queues a query that does not return rows, like [bob.Exec]
+func QueueExec(b *Batch, q bob.Query) *Future[sql.Result] {
+	f := &Future[sql.Result]{}
+
+	var res sql.Result
+	b.queue(batchItem{
+		query: q,
+		fail:  func(err error) { f.resolve(nil, err) },
+		read: func(ctx context.Context, br pgx.BatchResults) error {
+			tag, err := br.Exec()
+			if err != nil {
+				return err
+			}
+
+			res = result{tag}
+			return nil
+		},
+		finish: func(ctx context.Context, exec bob.Executor) error {
+			if err := load(ctx, exec, q, nil); err != nil {
+				return err
+			}
+
+			f.resolve(res, nil)
+			return nil
+		},
+	})
+
+	return f
+}
+
+func withMapperMods[T any](q bob.Query, m scan.Mapper[T]) scan.Mapper[T] {
+	if l, ok := q.(bob.MapperModder); ok {
+		if mods := l.GetMapperMods(); len(mods) > 0 {
+			return scan.Mod(m, mods...)
+		}
+	}
+
+	return m
+}
+
+func load(ctx context.Context, exec bob.Executor, q bob.Query, retrieved any) error {
+	if l, ok := q.(bob.Loadable); ok {
+		for _, loader := range l.GetLoaders() {
+			if err := loader.Load(ctx, exec, retrieved); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Send runs the hooks of the queued queries, builds them and sends them
+// in a single [pgx.Batch]. The results are then available from the futures.
+//
+// If a query cannot be built, nothing is sent and every future returns the error.
+// Otherwise, each future returns the error of its own query.
+// Send returns the first error of any query
+func (b *Batch) Send(ctx context.Context, exec Batcher) error {
+	if b.sent {
+		return ErrBatchAlreadySent
+	}
+	b.sent = true
+
+	pgxBatch := &pgx.Batch{}
+	contexts := make([]context.Context, len(b.items))
+
+	for i, item := range b.items {
+		qctx := ctx
+		var err error
+
+		if h, ok := item.query.(bob.HookableQuery); ok {
+			qctx, err = h.RunHooks(qctx, exec)
+		}
+
+		var query string
+		var args []any
+		if err == nil {
+			query, args, err = bob.Build(qctx, item.query)
+		}
+
+		if err != nil {
+			err = fmt.Errorf("batch query %d: %w", i, err)
+			for _, item := range b.items {
+				item.fail(err)
+			}
+			return err
+		}
+
+		contexts[i] = qctx
+		pgxBatch.Queue(query, args...)
+	}
+
+	if len(b.items) == 0 {
+		return nil
+	}
+
+	errs := make([]error, len(b.items))
+	br := exec.SendBatch(ctx, pgxBatch)
+	for i, item := range b.items {
+		errs[i] = item.read(contexts[i], br)
+	}
+
+	closeErr := br.Close()
+
+	var firstErr error
+	for i, item := range b.items {
+		if errs[i] == nil {
+			// loaders run queries, so they can only run once the batch is closed
+			errs[i] = item.finish(bob.LoaderContext(contexts[i], item.query), exec)
+		}
+
+		if errs[i] != nil {
+			item.fail(errs[i])
+			if firstErr == nil {
+				firstErr = errs[i]
+			}
+		}
+	}
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	return closeErr
+}
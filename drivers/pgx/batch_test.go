@@ -0,0 +1,261 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/bobtest"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/dialect"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+	"github.com/stephenafamo/bob/dialect/psql/um"
+	"github.com/stephenafamo/scan"
+)
+
+type batchUser struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+type batchUsers []batchUser
+
+func (u batchUsers) AfterQueryHook(ctx context.Context, exec bob.Executor, typ bob.QueryType) error {
+	for i := range u {
+		u[i].Name += "!"
+	}
+	return nil
+}
+
+// hookedQuery is a query with a hook and a loader, like the queries of generated models
+type hookedQuery struct {
+	bob.BaseQuery[*dialect.SelectQuery]
+	events *[]string
+}
+
+type hookKey struct{}
+
+func (q hookedQuery) RunHooks(ctx context.Context, exec bob.Executor) (context.Context, error) {
+	*q.events = append(*q.events, "hook")
+	q.Apply(sm.Where(psql.Quote("active").EQ(psql.Arg(true))))
+	return context.WithValue(ctx, hookKey{}, "from hook"), nil
+}
+
+func (q hookedQuery) GetLoaders() []bob.Loader {
+	return []bob.Loader{bob.LoaderFunc(func(ctx context.Context, exec bob.Executor, retrieved any) error {
+		*q.events = append(*q.events, fmt.Sprintf("load %v %v", ctx.Value(hookKey{}), retrieved))
+		return nil
+	})}
+}
+
+// fakeBatcher returns canned results for each query in the batch
+type fakeBatcher struct {
+	bob.Executor
+	results []fakeResult
+	sent    []*pgx.QueuedQuery
+}
+
+type fakeResult struct {
+	columns []string
+	rows    [][]any
+	tag     string
+	err     error
+}
+
+func (f *fakeBatcher) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	f.sent = b.QueuedQueries
+	return &fakeBatchResults{results: f.results}
+}
+
+type fakeBatchResults struct {
+	results []fakeResult
+	next    int
+}
+
+func (f *fakeBatchResults) pop() (fakeResult, error) {
+	if f.next >= len(f.results) {
+		return fakeResult{}, errors.New("no more results")
+	}
+	f.next++
+	r := f.results[f.next-1]
+	return r, r.err
+}
+
+func (f *fakeBatchResults) Exec() (pgconn.CommandTag, error) {
+	r, err := f.pop()
+	return pgconn.NewCommandTag(r.tag), err
+}
+
+func (f *fakeBatchResults) Query() (pgx.Rows, error) {
+	r, err := f.pop()
+	return &fakeRows{fakeResult: r}, err
+}
+
+func (f *fakeBatchResults) QueryRow() pgx.Row {
+	r, _ := f.pop()
+	return &fakeRows{fakeResult: r}
+}
+
+func (f *fakeBatchResults) Close() error { return nil }
+
+type fakeRows struct {
+	fakeResult
+	next int
+}
+
+func (r *fakeRows) Close()                        {}
+func (r *fakeRows) Err() error                    { return r.err }
+func (r *fakeRows) CommandTag() pgconn.CommandTag { return pgconn.NewCommandTag(r.tag) }
+func (r *fakeRows) Values() ([]any, error)        { return r.rows[r.next-1], nil }
+func (r *fakeRows) RawValues() [][]byte           { return nil }
+func (r *fakeRows) Conn() *pgx.Conn               { return nil }
+
+func (r *fakeRows) FieldDescriptions() []pgconn.FieldDescription {
+	fields := make([]pgconn.FieldDescription, len(r.columns))
+	for i, col := range r.columns {
+		fields[i].Name = col
+	}
+	return fields
+}
+
+func (r *fakeRows) Next() bool {
+	r.next++
+	return r.next <= len(r.rows)
+}
+
+func (r *fakeRows) Scan(dest ...any) error {
+	if r.next > len(r.rows) {
+		return io.EOF
+	}
+	for i, d := range dest {
+		reflect.ValueOf(d).Elem().Set(reflect.ValueOf(r.rows[r.next-1][i]))
+	}
+	return nil
+}
+
+func TestBatch(t *testing.T) {
+	ctx := context.Background()
+	var events []string
+
+	exec := &fakeBatcher{results: []fakeResult{
+		{columns: []string{"id", "name"}, rows: [][]any{{int64(1), "alice"}}},
+		{columns: []string{"id", "name"}, rows: [][]any{{int64(1), "alice"}, {int64(2), "bob"}}},
+		{tag: "UPDATE 3"},
+		{columns: []string{"count"}, rows: [][]any{{int64(2)}}},
+	}}
+
+	b := NewBatch()
+	one := QueueOne(b, hookedQuery{
+		BaseQuery: psql.Select(sm.From("users"), sm.Where(psql.Quote("id").EQ(psql.Arg(1)))),
+		events:    &events,
+	}, scan.StructMapper[batchUser]())
+	all := QueueAllx[bob.SliceTransformer[batchUser, batchUsers]](b, psql.Select(sm.From("users")), scan.StructMapper[batchUser]())
+	exec1 := QueueExec(b, psql.Update(um.Table("users"), um.SetCol("active").ToArg(false)))
+	count := QueueOne(b, psql.Select(sm.Columns("count(*)"), sm.From("users")), scan.SingleColumnMapper[int64])
+
+	if _, err := one.Get(); !errors.Is(err, ErrBatchNotSent) {
+		t.Fatalf("expected ErrBatchNotSent, got %v", err)
+	}
+
+	if b.Len() != 4 {
+		t.Fatalf("expected 4 queued queries, got %d", b.Len())
+	}
+
+	if err := b.Send(ctx, exec); err != nil {
+		t.Fatal(err)
+	}
+
+	sent := make([]string, len(exec.sent))
+	for i, q := range exec.sent {
+		sent[i] = fmt.Sprintf("%s %v", bobtest.NormalizeSQL(q.SQL), q.Arguments)
+	}
+	if diff := cmp.Diff([]string{
+		`SELECT * FROM users WHERE("id" = $1)AND("active" = $2) [1 true]`,
+		`SELECT * FROM users []`,
+		`UPDATE users SET "active" = $1 [false]`,
+		`SELECT count(*)FROM users []`,
+	}, sent); diff != "" {
+		t.Fatal(diff)
+	}
+
+	u, err := one.Get()
+	if err != nil || u.Name != "alice" {
+		t.Fatalf("unexpected result %v, %v", u, err)
+	}
+
+	users, err := all.Get()
+	if err != nil || len(users) != 2 || users[1].Name != "bob!" {
+		t.Fatalf("unexpected result %v, %v", users, err)
+	}
+
+	res, err := exec1.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if affected, _ := res.RowsAffected(); affected != 3 {
+		t.Fatalf("expected 3 rows affected, got %d", affected)
+	}
+
+	if c, err := count.Get(); err != nil || c != 2 {
+		t.Fatalf("unexpected count %v, %v", c, err)
+	}
+
+	if diff := cmp.Diff([]string{"hook", "load from hook {1 alice}"}, events); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if err := b.Send(ctx, exec); !errors.Is(err, ErrBatchAlreadySent) {
+		t.Fatalf("expected ErrBatchAlreadySent, got %v", err)
+	}
+}
+
+func TestBatchErrors(t *testing.T) {
+	ctx := context.Background()
+	failed := errors.New("failed")
+
+	exec := &fakeBatcher{results: []fakeResult{
+		{columns: []string{"count"}, rows: [][]any{{int64(2)}}},
+		{err: failed},
+	}}
+
+	b := NewBatch()
+	first := QueueOne(b, psql.Select(sm.Columns("count(*)"), sm.From("users")), scan.SingleColumnMapper[int64])
+	second := QueueExec(b, psql.Update(um.Table("users"), um.SetCol("active").ToArg(false)))
+
+	if err := b.Send(ctx, exec); !errors.Is(err, failed) {
+		t.Fatalf("expected the query error, got %v", err)
+	}
+
+	if c, err := first.Get(); err != nil || c != 2 {
+		t.Fatalf("unexpected count %v, %v", c, err)
+	}
+
+	if _, err := second.Get(); !errors.Is(err, failed) {
+		t.Fatalf("expected the query error, got %v", err)
+	}
+
+	t.Run("build error", func(t *testing.T) {
+		exec := &fakeBatcher{}
+		b := NewBatch()
+		first := QueueOne(b, psql.Select(sm.From("users")), scan.SingleColumnMapper[int64])
+		QueueExec(b, psql.RawQuery("UPDATE users SET a = ?"))
+
+		err := b.Send(ctx, exec)
+		if err == nil {
+			t.Fatal("expected a build error")
+		}
+		if exec.sent != nil {
+			t.Fatal("expected nothing to be sent")
+		}
+		if _, getErr := first.Get(); !errors.Is(getErr, err) {
+			t.Fatalf("expected the build error, got %v", getErr)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package pgx
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stephenafamo/bob"
+)
+
+// Log wraps a [Pool], [PoolConn] or [Conn] so that every query, including
+// those run in transactions started with Begin or BeginTx, is logged with [log/slog].
+// See [bob.Log] for the contents of each record.
+func Log(t LogTransactorBeginner, opts bob.LogOptions) LoggedTransactor {
+	return LoggedTransactor{
+		LoggedTransactor: bob.LogTransactor[Tx](t, opts),
+		t:                t,
+		opts:             opts,
+	}
+}
+
+// LogTx wraps a [Tx] so that every query, the commit and the rollback
+// are logged with [log/slog]
+func LogTx(tx Tx, opts bob.LogOptions) bob.LoggedTransaction {
+	return bob.LogTx(tx, opts)
+}
+
+// LogTransactorBeginner is implemented by [Pool], [PoolConn] and [Conn]
+type LogTransactorBeginner interface {
+	bob.Transactor[Tx]
+	BeginTx(context.Context, pgx.TxOptions) (Tx, error)
+}
+
+// LoggedTransactor is the [bob.Transactor] returned by [Log]
+type LoggedTransactor struct {
+	bob.LoggedTransactor[Tx]
+	t    LogTransactorBeginner
+	opts bob.LogOptions
+}
+
+// BeginTx starts a transaction with the given options that is logged with the same options
+func (l LoggedTransactor) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (bob.LoggedTransaction, error) {
+	return bob.LogTransactor[Tx](txOptionsBeginner{
+		LogTransactorBeginner: l.t,
+		txOptions:             txOptions,
+	}, l.opts).Begin(ctx)
+}
+
+// RunInTx runs the provided function in a logged transaction started with BeginTx.
+// If the function returns an error, the transaction is rolled back.
+// Otherwise, the transaction is committed.
+func (l LoggedTransactor) RunInTx(ctx context.Context, txOptions pgx.TxOptions, fn func(context.Context, bob.Transaction) error) error {
+	tx, err := l.BeginTx(ctx, txOptions)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	return bob.RunTx(ctx, tx, fn)
+}
+
+// txOptionsBeginner starts every transaction with the same options
+type txOptionsBeginner struct {
+	LogTransactorBeginner
+	txOptions pgx.TxOptions
+}
+
+func (t txOptionsBeginner) Begin(ctx context.Context) (Tx, error) {
+	return t.BeginTx(ctx, t.txOptions)
+}
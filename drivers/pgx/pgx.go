@@ -8,6 +8,7 @@ import (
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/scan"
 )
 
@@ -59,6 +60,23 @@ func (p Pool) BeginTx(ctx context.Context, opts pgx.TxOptions) (Tx, error) {
 	return beginTx(ctx, opts, p.Pool)
 }
 
+// RunInTx runs the provided function in a transaction.
+// If the function returns an error, the transaction is rolled back.
+// Otherwise, the transaction is committed.
+func (p Pool) RunInTx(ctx context.Context, opts pgx.TxOptions, fn func(context.Context, bob.Transaction) error) error {
+	return runInTx(ctx, opts, p.Pool, fn)
+}
+
+// RunInTxWithRetry works like RunInTx, but runs the function again in a new
+// transaction if it fails with an error that the options say should be retried.
+// The function may be called more than once, so it should not have side effects
+// outside of the transaction
+func (p Pool) RunInTxWithRetry(ctx context.Context, opts pgx.TxOptions, retry bob.RetryOptions, fn func(context.Context, bob.Transaction) error) error {
+	return bob.RunWithRetry(ctx, retry, func(ctx context.Context) error {
+		return runInTx(ctx, opts, p.Pool, fn)
+	})
+}
+
 // Acquire is similar to [*pgxpool.Pool.Acquire] but returns a connection that implement [Queryer]
 func (p Pool) Acquire(ctx context.Context) (PoolConn, error) {
 	pgxConn, err := p.Pool.Acquire(ctx)
@@ -107,6 +125,21 @@ func (c PoolConn) BeginTx(ctx context.Context, opts pgx.TxOptions) (Tx, error) {
 	return beginTx(ctx, opts, c.Conn)
 }
 
+// RunInTx runs the provided function in a transaction.
+// If the function returns an error, the transaction is rolled back.
+// Otherwise, the transaction is committed.
+func (c PoolConn) RunInTx(ctx context.Context, opts pgx.TxOptions, fn func(context.Context, bob.Transaction) error) error {
+	return runInTx(ctx, opts, c.Conn, fn)
+}
+
+// RunInTxWithRetry works like RunInTx, but runs the function again in a new
+// transaction if it fails with an error that the options say should be retried
+func (c PoolConn) RunInTxWithRetry(ctx context.Context, opts pgx.TxOptions, retry bob.RetryOptions, fn func(context.Context, bob.Transaction) error) error {
+	return bob.RunWithRetry(ctx, retry, func(ctx context.Context) error {
+		return runInTx(ctx, opts, c.Conn, fn)
+	})
+}
+
 // NewConn wraps an [*pgx.Conn] and returns a type that implements [Queryer]
 // This is useful when an existing *pgx.Conn is used in other places in the codebase
 func NewConn(conn *pgx.Conn) Conn {
@@ -142,6 +175,21 @@ func (c Conn) BeginTx(ctx context.Context, opts pgx.TxOptions) (Tx, error) {
 	return beginTx(ctx, opts, c.Conn)
 }
 
+// RunInTx runs the provided function in a transaction.
+// If the function returns an error, the transaction is rolled back.
+// Otherwise, the transaction is committed.
+func (c Conn) RunInTx(ctx context.Context, opts pgx.TxOptions, fn func(context.Context, bob.Transaction) error) error {
+	return runInTx(ctx, opts, c.Conn, fn)
+}
+
+// RunInTxWithRetry works like RunInTx, but runs the function again in a new
+// transaction if it fails with an error that the options say should be retried
+func (c Conn) RunInTxWithRetry(ctx context.Context, opts pgx.TxOptions, retry bob.RetryOptions, fn func(context.Context, bob.Transaction) error) error {
+	return bob.RunWithRetry(ctx, retry, func(ctx context.Context) error {
+		return runInTx(ctx, opts, c.Conn, fn)
+	})
+}
+
 type result struct {
 	pgconn.CommandTag
 }
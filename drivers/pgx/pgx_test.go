@@ -32,3 +32,10 @@ var (
 // we wrap the given pgx.Tx, to add auto rollback based on context cancellation
 // so we do not simply embed it
 var _ pgx.Tx = Tx{}
+
+var (
+	_ bob.Executor                          = Log(Pool{}, bob.LogOptions{})
+	_ bob.Transactor[bob.LoggedTransaction] = Log(Conn{}, bob.LogOptions{})
+	_ bob.Transactor[bob.LoggedTransaction] = Log(PoolConn{}, bob.LogOptions{})
+	_ bob.Transaction                       = LogTx(Tx{}, bob.LogOptions{})
+)
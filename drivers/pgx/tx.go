@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/scan"
 )
 
@@ -32,6 +34,17 @@ func beginTx(ctx context.Context, opts pgx.TxOptions, exec transactionBeginner)
 	return NewTx(tx, cancel), nil
 }
 
+// runInTx calls fn in a new transaction, rolling back if it returns an
+// error and committing otherwise
+func runInTx(ctx context.Context, opts pgx.TxOptions, exec transactionBeginner, fn func(context.Context, bob.Transaction) error) error {
+	tx, err := beginTx(ctx, opts, exec)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	return bob.RunTx(ctx, tx, fn)
+}
+
 // NewTx wraps an [pgx.Tx] and returns a type that implements [Queryer] but still
 // retains the expected methods used by pgx.Tx
 // This is useful when an existing pgx.Tx is used in other places in the codebase
@@ -47,7 +60,24 @@ type Tx struct {
 }
 
 // Begin implements pgx.Tx.
+// It starts a pseudo nested transaction using a savepoint.
 func (t Tx) Begin(ctx context.Context) (pgx.Tx, error) {
+	return t.begin(ctx)
+}
+
+// RunInTx runs the provided function in a pseudo nested transaction.
+// If the function returns an error, it is rolled back to the savepoint.
+// Otherwise, the savepoint is released.
+func (t Tx) RunInTx(ctx context.Context, fn func(context.Context, bob.Transaction) error) error {
+	tx, err := t.begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	return bob.RunTx(ctx, tx, fn)
+}
+
+func (t Tx) begin(ctx context.Context) (Tx, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	tx, err := t.tx.Begin(ctx)
 	if err != nil {
@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"reflect"
+	"sync/atomic"
 
 	"github.com/stephenafamo/scan"
 )
@@ -24,8 +27,64 @@ type (
 	HookableType interface {
 		AfterQueryHook(context.Context, Executor, QueryType) error
 	}
+
+	// If a query implements this interface, the context that its loaders and
+	// the AfterQueryHook are run with is modified with AfterQueryContext.
+	// This is used to remove values that only describe the query itself
+	AfterQueryContexter interface {
+		AfterQueryContext(context.Context) context.Context
+	}
 )
 
+type queryTypeCtxKey struct{}
+
+// QueryTypeFromContext returns the [QueryType] of the query being executed.
+// It is set by [Exec], [One], [Allx], [Cursor] and [Each] before the query is
+// sent to the [Executor], so that wrapping executors can inspect it.
+func QueryTypeFromContext(ctx context.Context) (QueryType, bool) {
+	t, ok := ctx.Value(queryTypeCtxKey{}).(QueryType)
+	return t, ok
+}
+
+func withQueryType(ctx context.Context, t QueryType) context.Context {
+	ctx = context.WithValue(ctx, queryTypeCtxKey{}, t)
+	return context.WithValue(ctx, lockingCtxKey{}, new(atomic.Bool))
+}
+
+type lockingCtxKey struct{}
+
+// MarkLocking records that the query being built with the context locks the rows
+// it reads, such as SELECT ... FOR UPDATE. It is called by the locking clauses
+// when they are written, and does nothing if the query is not being executed
+// with [Exec], [One], [Allx], [Cursor] or [Each]
+func MarkLocking(ctx context.Context) {
+	if locking, ok := ctx.Value(lockingCtxKey{}).(*atomic.Bool); ok {
+		locking.Store(true)
+	}
+}
+
+// IsLocking reports whether the query being executed locks the rows it reads.
+// [SplitReads] sends such queries to the primary even if they are SELECT queries
+func IsLocking(ctx context.Context) bool {
+	locking, ok := ctx.Value(lockingCtxKey{}).(*atomic.Bool)
+	return ok && locking.Load()
+}
+
+// LoaderContext returns the context to run the loaders and the AfterQueryHook
+// of a query with, once the query has been executed.
+// The [QueryType] of the query and whether it locks rows are removed, so that the queries they run are not
+// mistaken for it, e.g. a write is not sent to a replica by [SplitReads]
+// because the query was a SELECT
+func LoaderContext(ctx context.Context, q Query) context.Context {
+	ctx = context.WithValue(ctx, queryTypeCtxKey{}, nil)
+	ctx = context.WithValue(ctx, lockingCtxKey{}, nil)
+	if c, ok := q.(AfterQueryContexter); ok {
+		ctx = c.AfterQueryContext(ctx)
+	}
+
+	return ctx
+}
+
 type Executor interface {
 	scan.Queryer
 	ExecContext(context.Context, string, ...any) (sql.Result, error)
@@ -42,6 +101,71 @@ type Transaction interface {
 	Rollback(context.Context) error
 }
 
+// ErrCannotBegin is returned by [Begin] if the executor cannot begin a transaction
+var ErrCannotBegin = errors.New("the executor cannot begin a transaction")
+
+//nolint:gochecknoglobals
+var (
+	errorType       = reflect.TypeFor[error]()
+	transactionType = reflect.TypeFor[Transaction]()
+)
+
+// Begin starts a transaction on an executor that is a [Transactor] of any
+// transaction type, such as [DB] or the types in drivers/pgx.
+// It returns [ErrCannotBegin] if the executor has no such Begin method
+func Begin(ctx context.Context, exec Executor) (Transaction, error) {
+	if t, ok := exec.(Transactor[Transaction]); ok {
+		return t.Begin(ctx)
+	}
+
+	// The type of the transaction is not known,
+	// so the Begin method is found with reflection
+	return callBegin(exec, "Begin", reflect.ValueOf(&ctx).Elem())
+}
+
+// BeginTx is like [Begin], but starts the transaction with the given options.
+// The executor must have a BeginTx method that takes [*sql.TxOptions], such as [DB].
+// If it does not, only nil options are accepted and the transaction is started with [Begin]
+func BeginTx(ctx context.Context, exec Executor, opts *sql.TxOptions) (Transaction, error) {
+	tx, err := callBegin(exec, "BeginTx", reflect.ValueOf(&ctx).Elem(), reflect.ValueOf(opts))
+	if !errors.Is(err, ErrCannotBegin) {
+		return tx, err
+	}
+
+	if opts != nil {
+		return nil, fmt.Errorf("%T does not support transaction options", exec)
+	}
+
+	return Begin(ctx, exec)
+}
+
+// callBegin calls the named method of exec with the given arguments
+// if it returns a [Transaction] and an error
+func callBegin(exec Executor, name string, args ...reflect.Value) (Transaction, error) {
+	begin := reflect.ValueOf(exec).MethodByName(name)
+	if !begin.IsValid() {
+		return nil, ErrCannotBegin
+	}
+
+	typ := begin.Type()
+	if typ.NumIn() != len(args) || typ.NumOut() != 2 ||
+		!typ.Out(0).Implements(transactionType) || typ.Out(1) != errorType {
+		return nil, ErrCannotBegin
+	}
+	for i, arg := range args {
+		if typ.In(i) != arg.Type() {
+			return nil, ErrCannotBegin
+		}
+	}
+
+	out := begin.Call(args)
+	if err, _ := out[1].Interface().(error); err != nil {
+		return nil, err
+	}
+
+	return out[0].Interface().(Transaction), nil
+}
+
 func Exec(ctx context.Context, exec Executor, q Query) (sql.Result, error) {
 	var err error
 
@@ -52,16 +176,19 @@ func Exec(ctx context.Context, exec Executor, q Query) (sql.Result, error) {
 		}
 	}
 
+	ctx = withQueryType(ctx, q.Type())
+
 	sql, args, err := Build(ctx, q)
 	if err != nil {
 		return nil, err
 	}
 
-	result, err := exec.ExecContext(ctx, sql, args...)
+	result, err := timeoutExecutor(q, exec).ExecContext(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
 
+	ctx = LoaderContext(ctx, q)
 	if l, ok := q.(Loadable); ok {
 		for _, loader := range l.GetLoaders() {
 			if err := loader.Load(ctx, exec, nil); err != nil {
@@ -84,6 +211,8 @@ func One[T any](ctx context.Context, exec Executor, q Query, m scan.Mapper[T]) (
 		}
 	}
 
+	ctx = withQueryType(ctx, q.Type())
+
 	sql, args, err := Build(ctx, q)
 	if err != nil {
 		return t, err
@@ -95,11 +224,12 @@ func One[T any](ctx context.Context, exec Executor, q Query, m scan.Mapper[T]) (
 		}
 	}
 
-	t, err = scan.One(ctx, exec, m, sql, args...)
+	t, err = scan.One(ctx, timeoutExecutor(q, exec), m, sql, args...)
 	if err != nil {
 		return t, err
 	}
 
+	ctx = LoaderContext(ctx, q)
 	if l, ok := q.(Loadable); ok {
 		for _, loader := range l.GetLoaders() {
 			if err := loader.Load(ctx, exec, t); err != nil {
@@ -153,6 +283,8 @@ func Allx[Tr Transformer[T, V], T, V any](ctx context.Context, exec Executor, q
 		}
 	}
 
+	ctx = withQueryType(ctx, q.Type())
+
 	sql, args, err := Build(ctx, q)
 	if err != nil {
 		return typedSlice, err
@@ -164,7 +296,7 @@ func Allx[Tr Transformer[T, V], T, V any](ctx context.Context, exec Executor, q
 		}
 	}
 
-	rawSlice, err := scan.All(ctx, exec, m, sql, args...)
+	rawSlice, err := scan.All(ctx, timeoutExecutor(q, exec), m, sql, args...)
 	if err != nil {
 		return typedSlice, err
 	}
@@ -175,6 +307,7 @@ func Allx[Tr Transformer[T, V], T, V any](ctx context.Context, exec Executor, q
 		return typedSlice, err
 	}
 
+	ctx = LoaderContext(ctx, q)
 	if l, ok := q.(Loadable); ok {
 		for _, loader := range l.GetLoaders() {
 			if err := loader.Load(ctx, exec, typedSlice); err != nil {
@@ -203,6 +336,8 @@ func Cursor[T any](ctx context.Context, exec Executor, q Query, m scan.Mapper[T]
 		}
 	}
 
+	ctx = withQueryType(ctx, q.Type())
+
 	sql, args, err := Build(ctx, q)
 	if err != nil {
 		return nil, err
@@ -216,6 +351,7 @@ func Cursor[T any](ctx context.Context, exec Executor, q Query, m scan.Mapper[T]
 
 	l, isLoadable := q.(Loadable)
 	_, isHookable := any(*new(T)).(HookableType)
+	loaderCtx := LoaderContext(ctx, q)
 
 	m2 := scan.Mapper[T](func(ctx context.Context, c []string) (scan.BeforeFunc, func(any) (T, error)) {
 		before, after := m(ctx, c)
@@ -227,7 +363,7 @@ func Cursor[T any](ctx context.Context, exec Executor, q Query, m scan.Mapper[T]
 
 			if isLoadable {
 				for _, loader := range l.GetLoaders() {
-					err = loader.Load(ctx, exec, t)
+					err = loader.Load(loaderCtx, exec, t)
 					if err != nil {
 						return t, err
 					}
@@ -235,7 +371,7 @@ func Cursor[T any](ctx context.Context, exec Executor, q Query, m scan.Mapper[T]
 			}
 
 			if isHookable {
-				if err = any(t).(HookableType).AfterQueryHook(ctx, exec, q.Type()); err != nil {
+				if err = any(t).(HookableType).AfterQueryHook(loaderCtx, exec, q.Type()); err != nil {
 					return t, err
 				}
 			}
@@ -244,7 +380,7 @@ func Cursor[T any](ctx context.Context, exec Executor, q Query, m scan.Mapper[T]
 		}
 	})
 
-	return scan.Cursor(ctx, exec, m2, sql, args...)
+	return scan.Cursor(ctx, timeoutExecutor(q, exec), m2, sql, args...)
 }
 
 // Each returns a range-over-func that can be used to iterate over the rows of a query
@@ -258,6 +394,8 @@ func Each[T any](ctx context.Context, exec Executor, q Query, m scan.Mapper[T])
 		}
 	}
 
+	ctx = withQueryType(ctx, q.Type())
+
 	sql, args, err := Build(ctx, q)
 	if err != nil {
 		return nil, err
@@ -271,6 +409,7 @@ func Each[T any](ctx context.Context, exec Executor, q Query, m scan.Mapper[T])
 
 	l, isLoadable := q.(Loadable)
 	_, isHookable := any(*new(T)).(HookableType)
+	loaderCtx := LoaderContext(ctx, q)
 
 	m2 := scan.Mapper[T](func(ctx context.Context, c []string) (scan.BeforeFunc, func(any) (T, error)) {
 		before, after := m(ctx, c)
@@ -282,7 +421,7 @@ func Each[T any](ctx context.Context, exec Executor, q Query, m scan.Mapper[T])
 
 			if isLoadable {
 				for _, loader := range l.GetLoaders() {
-					err = loader.Load(ctx, exec, t)
+					err = loader.Load(loaderCtx, exec, t)
 					if err != nil {
 						return t, err
 					}
@@ -290,7 +429,7 @@ func Each[T any](ctx context.Context, exec Executor, q Query, m scan.Mapper[T])
 			}
 
 			if isHookable {
-				if err = any(t).(HookableType).AfterQueryHook(ctx, exec, q.Type()); err != nil {
+				if err = any(t).(HookableType).AfterQueryHook(loaderCtx, exec, q.Type()); err != nil {
 					return t, err
 				}
 			}
@@ -299,5 +438,5 @@ func Each[T any](ctx context.Context, exec Executor, q Query, m scan.Mapper[T])
 		}
 	})
 
-	return scan.Each(ctx, exec, m2, sql, args...), nil
+	return scan.Each(ctx, timeoutExecutor(q, exec), m2, sql, args...), nil
 }
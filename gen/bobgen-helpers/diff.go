@@ -0,0 +1,260 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/stephenafamo/bob/gen/drivers"
+	"github.com/urfave/cli/v2"
+)
+
+// MigrationDialect writes the statements of a migration for a database dialect.
+// Each method returns the statements without the trailing semicolon
+type MigrationDialect[C, I any] interface {
+	CreateEnum(drivers.Enum) []string
+	AlterEnum(drivers.EnumDiff) []string
+	DropEnum(drivers.Enum) []string
+
+	// CreateTable creates a new table with its constraints and indexes.
+	// Foreign keys are added later with AddForeignKeys if the dialect supports it
+	CreateTable(drivers.Table[C, I]) []string
+	DropTable(drivers.Table[C, I]) []string
+
+	// DropConstraints drops the constraints and indexes of a changed table
+	// before any column is changed
+	DropConstraints(drivers.TableDiff[C, I]) []string
+	// AlterTable adds and changes the columns of a changed table
+	// and adds its new constraints and indexes, except foreign keys
+	AlterTable(drivers.TableDiff[C, I]) []string
+	// AddForeignKeys adds foreign keys to a table once all tables are created
+	AddForeignKeys(drivers.Table[C, I], []drivers.ForeignKey[C]) []string
+	// DropColumns drops the columns of a changed table
+	DropColumns(drivers.TableDiff[C, I]) []string
+}
+
+// MigrationSQL writes the statements to apply the changes in the diff.
+// Statements are ordered so that nothing is referenced before it is created
+// or after it is dropped
+func MigrationSQL[C, I any](d MigrationDialect[C, I], diff drivers.DBInfoDiff[C, I]) string {
+	var stmts []string
+
+	for _, enum := range diff.AddedEnums {
+		stmts = append(stmts, d.CreateEnum(enum)...)
+	}
+	for _, enum := range diff.ChangedEnums {
+		stmts = append(stmts, d.AlterEnum(enum)...)
+	}
+
+	for _, table := range diff.ChangedTables {
+		stmts = append(stmts, d.DropConstraints(table)...)
+	}
+	for _, table := range diff.AddedTables {
+		stmts = append(stmts, d.CreateTable(table)...)
+	}
+	for _, table := range diff.ChangedTables {
+		stmts = append(stmts, d.AlterTable(table)...)
+	}
+
+	for _, table := range diff.AddedTables {
+		if len(table.Constraints.Foreign) > 0 {
+			stmts = append(stmts, d.AddForeignKeys(table, table.Constraints.Foreign)...)
+		}
+	}
+	for _, table := range diff.ChangedTables {
+		if len(table.AddedForeignKeys) > 0 {
+			stmts = append(stmts, d.AddForeignKeys(table.New, table.AddedForeignKeys)...)
+		}
+	}
+
+	for _, table := range diff.ChangedTables {
+		stmts = append(stmts, d.DropColumns(table)...)
+	}
+	for _, table := range dropOrder(diff.DroppedTables) {
+		stmts = append(stmts, d.DropTable(table)...)
+	}
+	for _, enum := range diff.DroppedEnums {
+		stmts = append(stmts, d.DropEnum(enum)...)
+	}
+
+	if len(stmts) == 0 {
+		return ""
+	}
+
+	return strings.Join(stmts, ";\n\n") + ";\n"
+}
+
+// dropOrder sorts the tables so that a table is dropped
+// before the other dropped tables it references
+func dropOrder[C, I any](tables []drivers.Table[C, I]) []drivers.Table[C, I] {
+	remaining := slices.Clone(tables)
+	ordered := make([]drivers.Table[C, I], 0, len(tables))
+
+	for len(remaining) > 0 {
+		next := slices.IndexFunc(remaining, func(t drivers.Table[C, I]) bool {
+			// not referenced by any other remaining table
+			return !slices.ContainsFunc(remaining, func(other drivers.Table[C, I]) bool {
+				return other.Key != t.Key && slices.ContainsFunc(other.Constraints.Foreign, func(fk drivers.ForeignKey[C]) bool {
+					return fk.ForeignTable == t.Key
+				})
+			})
+		})
+
+		// circular references, drop them in the given order
+		if next == -1 {
+			next = 0
+		}
+
+		ordered = append(ordered, remaining[next])
+		remaining = slices.Delete(remaining, next, next+1)
+	}
+
+	return ordered
+}
+
+// DiffOptions configures the files used by Diff
+type DiffOptions struct {
+	// The JSON file with the previous DBInfo.
+	// If the file does not exist, every table in the database is new
+	Snapshot string
+	// The file to write the migration SQL to
+	Output string
+	// The file to write the JSON encoded drivers.DBInfoDiff to, skipped if empty
+	Report string
+	// Update the snapshot with the current DBInfo after comparing
+	Update bool
+}
+
+// Diff compares the DBInfo snapshot with the current database and writes
+// the migration needed to go from the snapshot to the current schema.
+// The migration is not written if there are no changes.
+// The dialect is created with both DBInfos so it can look up
+// tables and types that are referenced but not changed
+func Diff[T, C, I any](ctx context.Context, d drivers.Interface[T, C, I], dialect func(from, to *drivers.DBInfo[T, C, I]) MigrationDialect[C, I], opts DiffOptions) (drivers.DBInfoDiff[C, I], error) {
+	var diff drivers.DBInfoDiff[C, I]
+
+	if opts.Snapshot == "" {
+		return diff, fmt.Errorf("no snapshot file given")
+	}
+
+	snapshot := &drivers.DBInfo[T, C, I]{}
+	content, err := os.ReadFile(opts.Snapshot)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return diff, fmt.Errorf("reading snapshot: %w", err)
+	default:
+		if err := json.Unmarshal(content, snapshot); err != nil {
+			return diff, fmt.Errorf("decoding snapshot %s: %w", opts.Snapshot, err)
+		}
+	}
+
+	current, err := d.Assemble(ctx)
+	if err != nil {
+		return diff, fmt.Errorf("unable to fetch table data: %w", err)
+	}
+
+	diff = drivers.DiffDBInfo(snapshot, current)
+
+	if opts.Report != "" {
+		if err := writeJSON(opts.Report, diff); err != nil {
+			return diff, fmt.Errorf("writing report: %w", err)
+		}
+	}
+
+	if opts.Output != "" && !diff.IsEmpty() {
+		if err := os.WriteFile(opts.Output, []byte(MigrationSQL(dialect(snapshot, current), diff)), 0o644); err != nil {
+			return diff, fmt.Errorf("writing migration: %w", err)
+		}
+	}
+
+	if opts.Update {
+		if err := writeJSON(opts.Snapshot, current); err != nil {
+			return diff, fmt.Errorf("updating snapshot: %w", err)
+		}
+	}
+
+	return diff, nil
+}
+
+// DiffCommand returns the diff command of a bobgen cli.
+// newDriver creates the driver from the configuration file given with -c,
+// and newDialect creates the dialect used to write the migration
+func DiffCommand[T, C, I any](newDriver func(configPath string) (drivers.Interface[T, C, I], error), newDialect func(from, to *drivers.DBInfo[T, C, I]) MigrationDialect[C, I]) *cli.Command {
+	return &cli.Command{
+		Name:      "diff",
+		Usage:     "Compare the database with a DBInfo snapshot and write the migration",
+		UsageText: "diff --snapshot FILE [--out FILE] [--report FILE] [--update | --check]",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "snapshot",
+				Usage:    "Compare with the DBInfo saved in `FILE`",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "out",
+				Usage: "Write the migration SQL to `FILE`",
+			},
+			&cli.StringFlag{
+				Name:  "report",
+				Usage: "Write the changes as JSON to `FILE`",
+			},
+			&cli.BoolFlag{
+				Name:  "update",
+				Usage: "Save the current DBInfo to the snapshot file",
+			},
+			&cli.BoolFlag{
+				Name:  "check",
+				Usage: "Exit with an error if the database does not match the snapshot. Cannot be used with --update",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			// updating the snapshot would make the next check pass
+			// even though this one failed
+			if c.Bool("check") && c.Bool("update") {
+				return errors.New("--check cannot be used with --update")
+			}
+
+			d, err := newDriver(c.String("config"))
+			if err != nil {
+				return err
+			}
+
+			changes, err := Diff(c.Context, d, newDialect, DiffOptions{
+				Snapshot: c.String("snapshot"),
+				Output:   c.String("out"),
+				Report:   c.String("report"),
+				Update:   c.Bool("update"),
+			})
+			if err != nil {
+				return err
+			}
+
+			if changes.IsEmpty() {
+				fmt.Fprintln(c.App.Writer, "No changes")
+				return nil
+			}
+
+			fmt.Fprint(c.App.Writer, changes.String())
+			if c.Bool("check") {
+				return fmt.Errorf("the database does not match the snapshot %s", c.String("snapshot"))
+			}
+
+			return nil
+		},
+	}
+}
+
+func writeJSON(path string, v any) error {
+	content, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
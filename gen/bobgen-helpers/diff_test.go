@@ -0,0 +1,35 @@
+package helpers
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stephenafamo/bob/gen/drivers"
+	"github.com/urfave/cli/v2"
+)
+
+func TestDiffCommandCheckWithUpdate(t *testing.T) {
+	var loaded bool
+	newDriver := func(string) (drivers.Interface[any, any, any], error) {
+		loaded = true
+		return nil, nil
+	}
+
+	app := &cli.App{
+		Writer:   io.Discard,
+		Flags:    []cli.Flag{&cli.StringFlag{Name: "config"}},
+		Commands: []*cli.Command{DiffCommand[any, any, any](newDriver, nil)},
+	}
+
+	snapshot := filepath.Join(t.TempDir(), "schema.json")
+	err := app.Run([]string{"bobgen", "diff", "--snapshot", snapshot, "--check", "--update"})
+	if err == nil || !strings.Contains(err.Error(), "--check cannot be used with --update") {
+		t.Fatalf("expected --check with --update to be rejected, got %v", err)
+	}
+
+	if loaded {
+		t.Fatal("expected the database not to be read")
+	}
+}
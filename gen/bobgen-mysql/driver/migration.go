@@ -0,0 +1,284 @@
+package driver
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	helpers "github.com/stephenafamo/bob/gen/bobgen-helpers"
+	"github.com/stephenafamo/bob/gen/drivers"
+)
+
+type (
+	table      = drivers.Table[any, any]
+	tableDiff  = drivers.TableDiff[any, any]
+	foreignKey = drivers.ForeignKey[any]
+)
+
+var rgxRawDefault = regexp.MustCompile(`^(-?[0-9]+(\.[0-9]+)?|0x[0-9a-fA-F]*|NULL|CURRENT_TIMESTAMP(\([0-9]*\))?|\(.*\))$`)
+
+// NewMigrationDialect returns the dialect used to write migrations
+// from the diff of two DBInfos.
+// Enums are part of the column type in MySQL, so enum changes are column changes
+func NewMigrationDialect(from, to *DBInfo) helpers.MigrationDialect[any, any] {
+	m := migration{tables: map[string]table{}}
+	for _, info := range []*DBInfo{from, to} {
+		for _, t := range info.Tables {
+			m.tables[t.Key] = t
+		}
+	}
+
+	return m
+}
+
+type migration struct {
+	tables map[string]table
+}
+
+func (migration) CreateEnum(drivers.Enum) []string    { return nil }
+func (migration) AlterEnum(drivers.EnumDiff) []string { return nil }
+func (migration) DropEnum(drivers.Enum) []string      { return nil }
+
+func (m migration) CreateTable(t table) []string {
+	defs := make([]string, 0, len(t.Columns))
+	var generated []string
+	for _, c := range t.Columns {
+		if c.Generated {
+			generated = append(generated, generatedColumn(t, "ADD", c))
+			continue
+		}
+		defs = append(defs, columnDefinition(c))
+	}
+
+	if pk := t.Constraints.Primary; pk != nil {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", quoteIdents(pk.Columns)))
+	}
+	for _, u := range t.Constraints.Uniques {
+		defs = append(defs, fmt.Sprintf("CONSTRAINT %s UNIQUE (%s)", quoteIdent(u.Name), quoteIdents(u.Columns)))
+	}
+	for _, c := range t.Constraints.Checks {
+		defs = append(defs, fmt.Sprintf("CONSTRAINT %s CHECK (%s)", quoteIdent(c.Name), c.Expression))
+	}
+
+	stmts := []string{fmt.Sprintf(
+		"CREATE TABLE %s (\n    %s\n)", tableName(t), strings.Join(defs, ",\n    "),
+	)}
+	stmts = append(stmts, generated...)
+
+	for _, idx := range t.Indexes {
+		if !isConstraintIndex(t, idx.Name) {
+			stmts = append(stmts, createIndex(t, idx))
+		}
+	}
+
+	return stmts
+}
+
+func (m migration) DropTable(t table) []string {
+	return []string{"DROP TABLE " + tableName(t)}
+}
+
+func (m migration) DropConstraints(t tableDiff) []string {
+	var stmts []string
+	name := tableName(t.New)
+
+	for _, fk := range t.DroppedForeignKeys {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s", name, quoteIdent(fk.Name)))
+	}
+	for _, c := range t.DroppedChecks {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP CHECK %s", name, quoteIdent(c.Name)))
+	}
+	for _, u := range t.DroppedUniques {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP INDEX %s", name, quoteIdent(u.Name)))
+	}
+	if t.DroppedPrimaryKey != nil {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY", name))
+	}
+
+	for _, idx := range t.DroppedIndexes {
+		if !isConstraintIndex(t.Old, idx.Name) {
+			stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP INDEX %s", name, quoteIdent(idx.Name)))
+		}
+	}
+
+	return stmts
+}
+
+func (m migration) AlterTable(t tableDiff) []string {
+	var stmts []string
+	name := tableName(t.New)
+
+	for _, c := range t.AddedColumns {
+		if c.Generated {
+			stmts = append(stmts, generatedColumn(t.New, "ADD", c))
+			continue
+		}
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", name, columnDefinition(c)))
+	}
+	for _, c := range t.ChangedColumns {
+		if c.New.Generated {
+			stmts = append(stmts, generatedColumn(t.New, "MODIFY", c.New))
+			continue
+		}
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", name, columnDefinition(c.New)))
+	}
+
+	if pk := t.AddedPrimaryKey; pk != nil {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD PRIMARY KEY (%s)", name, quoteIdents(pk.Columns)))
+	}
+	for _, u := range t.AddedUniques {
+		stmts = append(stmts, fmt.Sprintf(
+			"ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s)", name, quoteIdent(u.Name), quoteIdents(u.Columns),
+		))
+	}
+	for _, c := range t.AddedChecks {
+		stmts = append(stmts, fmt.Sprintf(
+			"ALTER TABLE %s ADD CONSTRAINT %s CHECK (%s)", name, quoteIdent(c.Name), c.Expression,
+		))
+	}
+
+	for _, idx := range t.AddedIndexes {
+		if !isConstraintIndex(t.New, idx.Name) {
+			stmts = append(stmts, createIndex(t.New, idx))
+		}
+	}
+
+	return stmts
+}
+
+func (m migration) AddForeignKeys(t table, fks []foreignKey) []string {
+	stmts := make([]string, len(fks))
+	for i, fk := range fks {
+		foreign, ok := m.tables[fk.ForeignTable]
+		if !ok {
+			foreign = table{Name: fk.ForeignTable}
+		}
+
+		stmts[i] = fmt.Sprintf(
+			"ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+			tableName(t), quoteIdent(fk.Name), quoteIdents(fk.Columns),
+			tableName(foreign), quoteIdents(fk.ForeignColumns),
+		)
+	}
+
+	return stmts
+}
+
+func (m migration) DropColumns(t tableDiff) []string {
+	stmts := make([]string, len(t.DroppedColumns))
+	for i, c := range t.DroppedColumns {
+		stmts[i] = fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", tableName(t.New), quoteIdent(c.Name))
+	}
+
+	return stmts
+}
+
+func columnDefinition(c drivers.Column) string {
+	var def strings.Builder
+	def.WriteString(quoteIdent(c.Name))
+	def.WriteString(" ")
+	def.WriteString(c.DBType)
+
+	if c.Nullable {
+		def.WriteString(" NULL")
+	} else {
+		def.WriteString(" NOT NULL")
+	}
+
+	switch {
+	case c.AutoIncr:
+		def.WriteString(" AUTO_INCREMENT")
+	case c.Default == "":
+	case rgxRawDefault.MatchString(c.Default):
+		def.WriteString(" DEFAULT ")
+		def.WriteString(c.Default)
+	default:
+		def.WriteString(" DEFAULT ")
+		def.WriteString(quoteString(c.Default))
+	}
+
+	if c.Comment != "" {
+		def.WriteString(" COMMENT ")
+		def.WriteString(quoteString(c.Comment))
+	}
+
+	return def.String()
+}
+
+// generatedColumn writes the statement to add or modify a generated column as a comment,
+// since the expression of the column is not known and has to be filled in
+func generatedColumn(t table, action string, c drivers.Column) string {
+	return fmt.Sprintf(
+		"-- The expression of the generated column %s.%s is not known\n-- ALTER TABLE %s %s COLUMN %s %s GENERATED ALWAYS AS (<expression>)",
+		tableName(t), quoteIdent(c.Name), tableName(t), action, quoteIdent(c.Name), c.DBType,
+	)
+}
+
+// isConstraintIndex checks if the index is the one created for
+// the primary key or a unique constraint of the table
+func isConstraintIndex(t table, name string) bool {
+	if name == "PRIMARY" {
+		return true
+	}
+
+	return slices.ContainsFunc(t.Constraints.Uniques, func(u drivers.Constraint[any]) bool {
+		return u.Name == name
+	})
+}
+
+func createIndex(t table, idx drivers.Index[any]) string {
+	var sb strings.Builder
+
+	sb.WriteString("CREATE ")
+	switch {
+	case idx.Type == "FULLTEXT", idx.Type == "SPATIAL":
+		sb.WriteString(idx.Type + " ")
+	case idx.Unique:
+		sb.WriteString("UNIQUE ")
+	}
+
+	cols := make([]string, len(idx.Columns))
+	for i, c := range idx.Columns {
+		cols[i] = quoteIdent(c.Name)
+		if c.IsExpression {
+			cols[i] = "(" + c.Name + ")"
+		}
+
+		if c.Desc.GetOrZero() {
+			cols[i] += " DESC"
+		}
+	}
+
+	fmt.Fprintf(&sb, "INDEX %s ON %s (%s)", quoteIdent(idx.Name), tableName(t), strings.Join(cols, ", "))
+	if idx.Type == "HASH" {
+		sb.WriteString(" USING HASH")
+	}
+
+	return sb.String()
+}
+
+func tableName(t table) string {
+	if t.Schema == "" {
+		return quoteIdent(t.Name)
+	}
+
+	return quoteIdent(t.Schema) + "." + quoteIdent(t.Name)
+}
+
+func quoteIdent(s string) string {
+	return "`" + strings.ReplaceAll(s, "`", "``") + "`"
+}
+
+func quoteIdents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = quoteIdent(n)
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+func quoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(s) + "'"
+}
@@ -0,0 +1,89 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	helpers "github.com/stephenafamo/bob/gen/bobgen-helpers"
+	"github.com/stephenafamo/bob/gen/drivers"
+)
+
+func TestMigrationSQL(t *testing.T) {
+	users := table{
+		Key:  "users",
+		Name: "users",
+		Columns: []drivers.Column{
+			{Name: "id", DBType: "int", Default: "AUTO_INCREMENT", AutoIncr: true},
+			{Name: "email", DBType: "varchar(255)"},
+			{Name: "status", DBType: "enum('active','banned')", Default: "active"},
+		},
+		Constraints: drivers.Constraints[any]{
+			Primary: &drivers.Constraint[any]{Name: "PRIMARY", Columns: []string{"id"}},
+			Uniques: []drivers.Constraint[any]{{Name: "email", Columns: []string{"email"}}},
+		},
+		Indexes: []drivers.Index[any]{
+			{Name: "PRIMARY", Type: "BTREE", Unique: true, Columns: []drivers.IndexColumn{{Name: "id"}}},
+			{Name: "email", Type: "BTREE", Unique: true, Columns: []drivers.IndexColumn{{Name: "email"}}},
+		},
+	}
+
+	posts := table{
+		Key:  "posts",
+		Name: "posts",
+		Columns: []drivers.Column{
+			{Name: "id", DBType: "int", Default: "AUTO_INCREMENT", AutoIncr: true},
+			{Name: "user_id", DBType: "int"},
+			{Name: "body", DBType: "longtext", Nullable: true},
+			{Name: "created_at", DBType: "timestamp", Default: "CURRENT_TIMESTAMP"},
+			{Name: "body_length", DBType: "int", Nullable: true, Generated: true},
+		},
+		Constraints: drivers.Constraints[any]{
+			Primary: &drivers.Constraint[any]{Name: "PRIMARY", Columns: []string{"id"}},
+			Foreign: []drivers.ForeignKey[any]{{
+				Constraint:     drivers.Constraint[any]{Name: "posts_ibfk_1", Columns: []string{"user_id"}},
+				ForeignTable:   "users",
+				ForeignColumns: []string{"id"},
+			}},
+		},
+		Indexes: []drivers.Index[any]{
+			{Name: "PRIMARY", Type: "BTREE", Unique: true, Columns: []drivers.IndexColumn{{Name: "id"}}},
+			{Name: "posts_ibfk_1", Type: "BTREE", Columns: []drivers.IndexColumn{{Name: "user_id"}}},
+			{Name: "body_search", Type: "FULLTEXT", Columns: []drivers.IndexColumn{{Name: "body"}}},
+		},
+	}
+
+	changedUsers := users
+	changedUsers.Columns = []drivers.Column{
+		users.Columns[0],
+		users.Columns[1],
+		{Name: "status", DBType: "enum('active','banned','deleted')", Default: "active"},
+		{Name: "score", DBType: "decimal(5,2)", Default: "0.00", Nullable: true},
+	}
+	changedUsers.Constraints.Uniques = nil
+	changedUsers.Indexes = users.Indexes[:1]
+
+	from := &DBInfo{Tables: drivers.Tables[any, any]{users}}
+	to := &DBInfo{Tables: drivers.Tables[any, any]{changedUsers, posts}}
+
+	got := helpers.MigrationSQL(NewMigrationDialect(from, to), drivers.DiffDBInfo(from, to))
+
+	want := "ALTER TABLE `users` DROP INDEX `email`;\n\n" +
+		"CREATE TABLE `posts` (\n" +
+		"    `id` int NOT NULL AUTO_INCREMENT,\n" +
+		"    `user_id` int NOT NULL,\n" +
+		"    `body` longtext NULL,\n" +
+		"    `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,\n" +
+		"    PRIMARY KEY (`id`)\n" +
+		");\n\n" +
+		"-- The expression of the generated column `posts`.`body_length` is not known\n" +
+		"-- ALTER TABLE `posts` ADD COLUMN `body_length` int GENERATED ALWAYS AS (<expression>);\n\n" +
+		"CREATE INDEX `posts_ibfk_1` ON `posts` (`user_id`);\n\n" +
+		"CREATE FULLTEXT INDEX `body_search` ON `posts` (`body`);\n\n" +
+		"ALTER TABLE `users` ADD COLUMN `score` decimal(5,2) NULL DEFAULT 0.00;\n\n" +
+		"ALTER TABLE `users` MODIFY COLUMN `status` enum('active','banned','deleted') NOT NULL DEFAULT 'active';\n\n" +
+		"ALTER TABLE `posts` ADD CONSTRAINT `posts_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`);\n"
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
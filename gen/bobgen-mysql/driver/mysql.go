@@ -26,6 +26,14 @@ type (
 	DBInfo    = drivers.DBInfo[any, any, any]
 )
 
+// DBExtra is the extra information about the database server
+// that is available to the templates as ExtraInfo
+type DBExtra struct {
+	// The server supports referencing the inserted row with an alias
+	// in ON DUPLICATE KEY UPDATE. Added in MySQL 8.0.19, not in MariaDB
+	RowAlias bool `json:"row_alias"`
+}
+
 type Config struct {
 	helpers.Config `yaml:",squash"`
 	// How many tables to fetch in parallel
@@ -84,6 +92,12 @@ func (d *driver) Assemble(ctx context.Context) (*DBInfo, error) {
 
 	dbinfo = &DBInfo{Driver: "github.com/go-sql-driver/mysql"}
 
+	var version string
+	if err := d.conn.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version); err != nil {
+		return nil, fmt.Errorf("failed to get server version: %w", err)
+	}
+	dbinfo.ExtraInfo = DBExtra{RowAlias: supportsRowAlias(version)}
+
 	dbinfo.Tables, err = drivers.BuildDBInfo[any](ctx, d, d.config.Concurrency, d.config.Config.Only, d.config.Config.Except, d.config.Config.ColumnOrder)
 	if err != nil {
 		return nil, err
@@ -443,3 +457,25 @@ func (d *driver) Comments(ctx context.Context) (map[string]string, error) {
 
 	return ret, nil
 }
+
+// supportsRowAlias checks if a server with the given version supports
+// INSERT ... AS row_alias ON DUPLICATE KEY UPDATE
+func supportsRowAlias(version string) bool {
+	if strings.Contains(strings.ToLower(version), "mariadb") {
+		return false
+	}
+
+	var major, minor, patch int
+	if _, err := fmt.Sscanf(version, "%d.%d.%d", &major, &minor, &patch); err != nil {
+		return false
+	}
+
+	switch {
+	case major != 8:
+		return major > 8
+	case minor != 0:
+		return minor > 0
+	default:
+		return patch >= 19
+	}
+}
@@ -184,3 +184,23 @@ func TestDriver(t *testing.T) {
 		})
 	}
 }
+
+func TestSupportsRowAlias(t *testing.T) {
+	cases := map[string]bool{
+		"8.0.35":                    true,
+		"8.0.19":                    true,
+		"8.0.18":                    false,
+		"8.4.0":                     true,
+		"9.1.0":                     true,
+		"5.7.44-log":                false,
+		"10.11.6-MariaDB-1:10.11.6": false,
+		"11.4.2-MariaDB":            false,
+		"unknown":                   false,
+	}
+
+	for version, expected := range cases {
+		if got := supportsRowAlias(version); got != expected {
+			t.Errorf("%s: expected %t, got %t", version, expected, got)
+		}
+	}
+}
@@ -9,3 +9,18 @@ func (e *UniqueConstraintError) Is(target error) bool {
   return err.Number == 1062 && strings.Contains(err.Message, e.s)
 }
 {{end -}}
+
+{{- define "retryable_error_detection_method" -}}
+{{$.Importer.Import "errors"}}
+{{$.Importer.Import "mysqlDriver" "github.com/go-sql-driver/mysql"}}
+func (e *RetryableError) Is(target error) bool {
+  var err *mysqlDriver.MySQLError
+  if !errors.As(target, &err) {
+    return false
+  }
+
+  // 1213 is ER_LOCK_DEADLOCK
+  // 1205 is ER_LOCK_WAIT_TIMEOUT
+  return err.Number == 1213 || err.Number == 1205
+}
+{{end -}}
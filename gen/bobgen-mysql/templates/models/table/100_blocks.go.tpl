@@ -8,7 +8,7 @@
 	type {{$tAlias.UpPlural}}Query = *{{$.Dialect}}.ViewQuery[*{{$tAlias.UpSingular}}, {{$tAlias.UpSingular}}Slice]
 {{- else -}}
 	// {{$tAlias.UpPlural}} contains methods to work with the {{$table.Name}} table
-	var {{$tAlias.UpPlural}} = {{$.Dialect}}.NewTablex[*{{$tAlias.UpSingular}}, {{$tAlias.UpSingular}}Slice, *{{$tAlias.UpSingular}}Setter]("{{$table.Name}}", build{{$tAlias.UpSingular}}Columns({{quote $table.Key}}), {{$tAlias.DownSingular}}ScanMapper, {{$table.UniqueColPairs}})
+	var {{$tAlias.UpPlural}} = {{$tAlias.DownSingular}}Table{ {{$.Dialect}}.NewTablex[*{{$tAlias.UpSingular}}, {{$tAlias.UpSingular}}Slice, *{{$tAlias.UpSingular}}Setter]("{{$table.Name}}", build{{$tAlias.UpSingular}}Columns({{quote $table.Key}}), {{$tAlias.DownSingular}}ScanMapper, {{$table.UniqueColPairs}}){{with index $.SoftDeleteColumns $table.Key}}.WithSoftDelete({{quote .}}){{end}}{{with index $.TenantColumns $table.Key}}.WithTenant({{quote .}}){{end}} }
 	// {{$tAlias.UpPlural}}Query is a query on the {{$table.Name}} table
 	type {{$tAlias.UpPlural}}Query = *{{$.Dialect}}.ViewQuery[*{{$tAlias.UpSingular}}, {{$tAlias.UpSingular}}Slice]
 {{- end}}
@@ -30,10 +30,11 @@ func (s *{{$tAlias.UpSingular}}Setter) Apply(q *dialect.InsertQuery) {
 	q.AppendValues(
   {{range $index, $column := $table.NonGeneratedColumns -}}
     bob.ExpressionFunc(func(ctx context.Context, w io.StringWriter, d bob.Dialect, start int) ([]any, error){
+        {{- template "setter/insert/tenant" (dict "Data" $ "Table" $table "Column" $column)}}
         {{$colAlias := $tAlias.Column $column.Name -}}
         {{$colGetter := $.Types.FromOptional $.CurrentPackage $.Importer $column.Type (cat "s." $colAlias) $column.Nullable $column.Nullable -}}
         if {{$.Types.IsOptionalInvalid $.CurrentPackage $column.Type $column.Nullable (cat "s." $colAlias)}} {
-          return {{$.Dialect}}.Raw("DEFAULT").WriteSQL(ctx, w, d, start)
+          {{- template "setter/insert/unset" (dict "Data" $ "Table" $table "Column" $column "Default" (printf "%s.Raw(\"DEFAULT\")" $.Dialect))}}
         }
         return {{$.Dialect}}.Arg({{$colGetter}}).WriteSQL(ctx, w, d, start)
     }),
@@ -53,15 +54,36 @@ func (s {{$tAlias.UpSingular}}Setter) UpdateMod() bob.Mod[*dialect.UpdateQuery]
 {{define "one_update" -}}
 {{$table := .Table}}
 {{$tAlias := .Aliases.Table $table.Key -}}
+{{$versionCol := index $.VersionColumns $table.Key -}}
 {{$.Importer.Import (printf "github.com/stephenafamo/bob/dialect/%s/um" $.Dialect)}}
 // Update uses an executor to update the {{$tAlias.UpSingular}}
+{{- if $versionCol}}
+// If the {{$tAlias.UpSingular}} was changed since it was loaded, an *orm.StaleObjectError is returned
+{{- end}}
 func (o *{{$tAlias.UpSingular}}) Update(ctx context.Context, exec bob.Executor, s *{{$tAlias.UpSingular}}Setter) error {
+  {{if $versionCol -}}
+  {{$.Importer.Import "github.com/stephenafamo/bob/orm"}}
+  {{$versionAlias := $tAlias.Column $versionCol -}}
+	rows, err := {{$tAlias.UpPlural}}.Update(s.UpdateMod(), um.Where(o.pkEQ()), um.Where(o.versionEQ())).Exec(ctx, exec)
+  if err != nil {
+    return err
+  }
+
+  if rows == 0 {
+    return &orm.StaleObjectError{Table: {{quote $table.Key}}, Expected: 1}
+  }
+
+  version := o.{{$versionAlias}}
+  s.Overwrite(o)
+  o.{{$versionAlias}} = version + 1
+  {{- else -}}
 	_, err := {{$tAlias.UpPlural}}.Update(s.UpdateMod(), um.Where(o.pkEQ())).Exec(ctx, exec)
   if err != nil {
     return err
   }
 
   s.Overwrite(o)
+  {{- end}}
 
   return nil
 }
@@ -70,7 +92,28 @@ func (o *{{$tAlias.UpSingular}}) Update(ctx context.Context, exec bob.Executor,
 {{define "slice_update" -}}
 {{$table := .Table}}
 {{$tAlias := .Aliases.Table $table.Key -}}
+{{$versionCol := index $.VersionColumns $table.Key -}}
 func (o {{$tAlias.UpSingular}}Slice) UpdateAll(ctx context.Context, exec bob.Executor, vals {{$tAlias.UpSingular}}Setter) error {
+  {{if $versionCol -}}
+  {{$.Importer.Import "github.com/stephenafamo/bob/orm"}}
+  {{$versionAlias := $tAlias.Column $versionCol -}}
+	rows, err := {{$tAlias.UpPlural}}.Update(vals.UpdateMod(), o.UpdateMod()).Exec(ctx, exec)
+  if err != nil {
+    return err
+  }
+
+  if rows != int64(len(o)) {
+    return &orm.StaleObjectError{Table: {{quote $table.Key}}, Expected: int64(len(o)), Affected: rows}
+  }
+
+  for i := range o {
+    version := o[i].{{$versionAlias}}
+    vals.Overwrite(o[i])
+    o[i].{{$versionAlias}} = version + 1
+  }
+
+  return nil
+  {{- else -}}
 	_, err := {{$tAlias.UpPlural}}.Update(vals.UpdateMod(), o.UpdateMod()).Exec(ctx, exec)
 
   for i := range o {
@@ -78,5 +121,16 @@ func (o {{$tAlias.UpSingular}}Slice) UpdateAll(ctx context.Context, exec bob.Exe
   }
 
   return err
+  {{- end}}
 }
-{{- end}}
\ No newline at end of file
+{{- end}}
+{{define "upsert_all" -}}
+{{- if and .Data.ExtraInfo .Data.ExtraInfo.RowAlias -}}
+{{.Data.Importer.Import "github.com/stephenafamo/bob/dialect/mysql/im"}}
+	q := t.Upsert({{printf "%#v" .Columns}}, setters...)
+	q.Apply(im.As("new"))
+	return q.All(ctx, exec)
+{{- else}}
+	return t.Upsert({{printf "%#v" .Columns}}, setters...).All(ctx, exec)
+{{- end}}
+{{- end}}
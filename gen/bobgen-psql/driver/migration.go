@@ -0,0 +1,457 @@
+package driver
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	helpers "github.com/stephenafamo/bob/gen/bobgen-helpers"
+	"github.com/stephenafamo/bob/gen/drivers"
+)
+
+type (
+	table      = drivers.Table[any, IndexExtra]
+	tableDiff  = drivers.TableDiff[any, IndexExtra]
+	foreignKey = drivers.ForeignKey[any]
+)
+
+// NewMigrationDialect returns the dialect used to write migrations
+// from the diff of two DBInfos
+func NewMigrationDialect(from, to *DBInfo) helpers.MigrationDialect[any, IndexExtra] {
+	m := migration{
+		tables:    map[string]table{},
+		enumNames: map[string]string{},
+	}
+
+	for _, info := range []*DBInfo{from, to} {
+		for _, t := range info.Tables {
+			m.tables[t.Key] = t
+			for _, c := range t.Columns {
+				m.addEnumName(c)
+			}
+		}
+	}
+
+	return m
+}
+
+type migration struct {
+	tables map[string]table
+	// the database name of each enum, keyed by its type
+	enumNames map[string]string
+}
+
+// addEnumName saves the database name of the enum used by the column.
+// The DBInfo only has the generated type of an enum
+func (m migration) addEnumName(c drivers.Column) {
+	enum, ok := strings.CutPrefix(c.Type, "enums.")
+	if !ok {
+		return
+	}
+
+	if _, ok := m.enumNames[enum]; !ok {
+		m.enumNames[enum] = c.DBType
+	}
+}
+
+func (m migration) enumName(e drivers.Enum) string {
+	if name, ok := m.enumNames[e.Type]; ok {
+		return quoteName(name)
+	}
+
+	return quoteIdent(e.Type)
+}
+
+func (m migration) CreateEnum(e drivers.Enum) []string {
+	values := make([]string, len(e.Values))
+	for i, v := range e.Values {
+		values[i] = quoteString(v)
+	}
+
+	return []string{fmt.Sprintf(
+		"CREATE TYPE %s AS ENUM (%s)", m.enumName(e), strings.Join(values, ", "),
+	)}
+}
+
+func (m migration) AlterEnum(e drivers.EnumDiff) []string {
+	var stmts []string
+	name := m.enumName(e.New)
+
+	for i, v := range e.New.Values {
+		if slices.Contains(e.Old.Values, v) {
+			continue
+		}
+
+		stmt := fmt.Sprintf("ALTER TYPE %s ADD VALUE %s", name, quoteString(v))
+		if i > 0 {
+			stmt += " AFTER " + quoteString(e.New.Values[i-1])
+		}
+		stmts = append(stmts, stmt)
+	}
+
+	for _, v := range e.Old.Values {
+		if !slices.Contains(e.New.Values, v) {
+			stmts = append(stmts, fmt.Sprintf(
+				"-- %s: the value %s was removed, the type has to be recreated", name, quoteString(v),
+			))
+		}
+	}
+
+	return stmts
+}
+
+func (m migration) DropEnum(e drivers.Enum) []string {
+	return []string{"DROP TYPE " + m.enumName(e)}
+}
+
+func (m migration) CreateTable(t table) []string {
+	defs := make([]string, 0, len(t.Columns))
+	var generated []string
+	for _, c := range t.Columns {
+		if isGeneratedExpr(c) {
+			generated = append(generated, addGeneratedColumn(t, c))
+			continue
+		}
+		defs = append(defs, columnDefinition(c))
+	}
+
+	if pk := t.Constraints.Primary; pk != nil {
+		defs = append(defs, fmt.Sprintf("CONSTRAINT %s PRIMARY KEY (%s)", quoteIdent(pk.Name), quoteIdents(pk.Columns)))
+	}
+	for _, u := range t.Constraints.Uniques {
+		defs = append(defs, fmt.Sprintf("CONSTRAINT %s UNIQUE (%s)", quoteIdent(u.Name), quoteIdents(u.Columns)))
+	}
+	for _, c := range t.Constraints.Checks {
+		defs = append(defs, fmt.Sprintf("CONSTRAINT %s CHECK %s", quoteIdent(c.Name), c.Expression))
+	}
+
+	stmts := []string{fmt.Sprintf(
+		"CREATE TABLE %s (\n    %s\n)", tableName(t), strings.Join(defs, ",\n    "),
+	)}
+	stmts = append(stmts, generated...)
+
+	for _, idx := range t.Indexes {
+		if !isConstraintIndex(t, idx.Name) {
+			stmts = append(stmts, createIndex(t, idx))
+		}
+	}
+
+	return stmts
+}
+
+func (m migration) DropTable(t table) []string {
+	return []string{"DROP TABLE " + tableName(t)}
+}
+
+func (m migration) DropConstraints(t tableDiff) []string {
+	var stmts []string
+	name := tableName(t.New)
+
+	dropConstraint := func(constraint string) {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s", name, quoteIdent(constraint)))
+	}
+
+	for _, fk := range t.DroppedForeignKeys {
+		dropConstraint(fk.Name)
+	}
+	for _, c := range t.DroppedChecks {
+		dropConstraint(c.Name)
+	}
+	for _, u := range t.DroppedUniques {
+		dropConstraint(u.Name)
+	}
+	if t.DroppedPrimaryKey != nil {
+		dropConstraint(t.DroppedPrimaryKey.Name)
+	}
+
+	for _, idx := range t.DroppedIndexes {
+		if !isConstraintIndex(t.Old, idx.Name) {
+			stmts = append(stmts, "DROP INDEX "+qualifiedName(t.Old.Schema, idx.Name))
+		}
+	}
+
+	return stmts
+}
+
+func (m migration) AlterTable(t tableDiff) []string {
+	var stmts []string
+	name := tableName(t.New)
+
+	for _, c := range t.AddedColumns {
+		if isGeneratedExpr(c) {
+			stmts = append(stmts, addGeneratedColumn(t.New, c))
+			continue
+		}
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", name, columnDefinition(c)))
+	}
+
+	for _, c := range t.ChangedColumns {
+		alter := fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s", name, quoteIdent(c.New.Name))
+
+		if c.Old.Generated != c.New.Generated || isIdentity(c.Old) != isIdentity(c.New) {
+			stmts = append(stmts, fmt.Sprintf(
+				"-- %s.%s: the column has to be recreated to change its generation", name, quoteIdent(c.New.Name),
+			))
+			continue
+		}
+
+		if c.Old.DBType != c.New.DBType || !slices.Equal(c.Old.TypeLimits, c.New.TypeLimits) {
+			typ := columnType(c.New)
+			stmts = append(stmts, fmt.Sprintf("%s TYPE %s USING %s::%s", alter, typ, quoteIdent(c.New.Name), typ))
+		}
+
+		if c.Old.Default != c.New.Default && (hasDefault(c.Old) || hasDefault(c.New)) && !c.New.Generated {
+			if hasDefault(c.New) {
+				stmts = append(stmts, fmt.Sprintf("%s SET DEFAULT %s", alter, c.New.Default))
+			} else {
+				stmts = append(stmts, alter+" DROP DEFAULT")
+			}
+		}
+
+		if c.Old.Nullable != c.New.Nullable {
+			if c.New.Nullable {
+				stmts = append(stmts, alter+" DROP NOT NULL")
+			} else {
+				stmts = append(stmts, alter+" SET NOT NULL")
+			}
+		}
+	}
+
+	if pk := t.AddedPrimaryKey; pk != nil {
+		stmts = append(stmts, fmt.Sprintf(
+			"ALTER TABLE %s ADD CONSTRAINT %s PRIMARY KEY (%s)", name, quoteIdent(pk.Name), quoteIdents(pk.Columns),
+		))
+	}
+	for _, u := range t.AddedUniques {
+		stmts = append(stmts, fmt.Sprintf(
+			"ALTER TABLE %s ADD CONSTRAINT %s UNIQUE (%s)", name, quoteIdent(u.Name), quoteIdents(u.Columns),
+		))
+	}
+	for _, c := range t.AddedChecks {
+		stmts = append(stmts, fmt.Sprintf(
+			"ALTER TABLE %s ADD CONSTRAINT %s CHECK %s", name, quoteIdent(c.Name), c.Expression,
+		))
+	}
+
+	for _, idx := range t.AddedIndexes {
+		if !isConstraintIndex(t.New, idx.Name) {
+			stmts = append(stmts, createIndex(t.New, idx))
+		}
+	}
+
+	return stmts
+}
+
+func (m migration) AddForeignKeys(t table, fks []foreignKey) []string {
+	stmts := make([]string, len(fks))
+	for i, fk := range fks {
+		foreign, ok := m.tables[fk.ForeignTable]
+		if !ok {
+			foreign = table{Name: fk.ForeignTable}
+		}
+
+		stmts[i] = fmt.Sprintf(
+			"ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+			tableName(t), quoteIdent(fk.Name), quoteIdents(fk.Columns),
+			tableName(foreign), quoteIdents(fk.ForeignColumns),
+		)
+	}
+
+	return stmts
+}
+
+func (m migration) DropColumns(t tableDiff) []string {
+	stmts := make([]string, len(t.DroppedColumns))
+	for i, c := range t.DroppedColumns {
+		stmts[i] = fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", tableName(t.New), quoteIdent(c.Name))
+	}
+
+	return stmts
+}
+
+func columnDefinition(c drivers.Column) string {
+	var def strings.Builder
+	def.WriteString(quoteIdent(c.Name))
+	def.WriteString(" ")
+
+	switch {
+	case isSerial(c):
+		def.WriteString(strings.NewReplacer("smallint", "smallserial", "bigint", "bigserial", "integer", "serial").Replace(c.DBType))
+	default:
+		def.WriteString(columnType(c))
+	}
+
+	switch {
+	case isIdentity(c) && c.Generated:
+		def.WriteString(" GENERATED ALWAYS AS IDENTITY")
+	case isIdentity(c):
+		def.WriteString(" GENERATED BY DEFAULT AS IDENTITY")
+	case hasDefault(c) && !isSerial(c):
+		def.WriteString(" DEFAULT ")
+		def.WriteString(c.Default)
+	}
+
+	if !c.Nullable {
+		def.WriteString(" NOT NULL")
+	}
+
+	return def.String()
+}
+
+// addGeneratedColumn writes the statement to add a generated column as a comment,
+// since the expression of the column is not known and has to be filled in
+func addGeneratedColumn(t table, c drivers.Column) string {
+	return fmt.Sprintf(
+		"-- The expression of the generated column %s.%s is not known\n-- ALTER TABLE %s ADD COLUMN %s %s GENERATED ALWAYS AS (<expression>) STORED",
+		tableName(t), quoteIdent(c.Name), tableName(t), quoteIdent(c.Name), columnType(c),
+	)
+}
+
+func columnType(c drivers.Column) string {
+	if c.DomainName != "" {
+		return quoteName(c.DomainName)
+	}
+
+	typ := c.DBType
+	isArray := strings.HasSuffix(typ, "[]")
+	if isArray {
+		// arrays of user defined types are named after the array type
+		typ = strings.TrimPrefix(strings.TrimSuffix(typ, "[]"), "_")
+	}
+
+	if len(c.TypeLimits) > 0 {
+		typ = fmt.Sprintf("%s(%s)", typ, strings.Join(c.TypeLimits, ","))
+	}
+
+	if isArray {
+		typ += "[]"
+	}
+
+	return typ
+}
+
+func hasDefault(c drivers.Column) bool {
+	return c.Default != "" && c.Default != "NULL" && c.Default != "IDENTITY" && c.Default != "GENERATED"
+}
+
+func isIdentity(c drivers.Column) bool {
+	return c.Default == "IDENTITY"
+}
+
+// isGeneratedExpr checks if the column is generated from an expression,
+// as opposed to an identity column
+func isGeneratedExpr(c drivers.Column) bool {
+	return c.Generated && !isIdentity(c)
+}
+
+func isSerial(c drivers.Column) bool {
+	if !strings.HasPrefix(c.Default, "nextval(") {
+		return false
+	}
+
+	switch c.DBType {
+	case "smallint", "integer", "bigint":
+		return true
+	default:
+		return false
+	}
+}
+
+// isConstraintIndex checks if the index is the one created for
+// the primary key or a unique constraint of the table
+func isConstraintIndex(t table, name string) bool {
+	if t.Constraints.Primary != nil && t.Constraints.Primary.Name == name {
+		return true
+	}
+
+	return slices.ContainsFunc(t.Constraints.Uniques, func(u drivers.Constraint[any]) bool {
+		return u.Name == name
+	})
+}
+
+func createIndex(t table, idx drivers.Index[IndexExtra]) string {
+	var sb strings.Builder
+
+	sb.WriteString("CREATE ")
+	if idx.Unique {
+		sb.WriteString("UNIQUE ")
+	}
+	fmt.Fprintf(&sb, "INDEX %s ON %s", quoteIdent(idx.Name), tableName(t))
+	if idx.Type != "" && idx.Type != "btree" {
+		fmt.Fprintf(&sb, " USING %s", idx.Type)
+	}
+
+	cols := make([]string, len(idx.Columns))
+	for i, c := range idx.Columns {
+		cols[i] = c.Name
+		if !c.IsExpression {
+			cols[i] = quoteIdent(c.Name)
+		}
+
+		desc := c.Desc.GetOrZero()
+		if desc {
+			cols[i] += " DESC"
+		}
+
+		// NULLS LAST is the default for ascending order, and NULLS FIRST for descending
+		if i < len(idx.Extra.NullsFirst) && idx.Extra.NullsFirst[i] != desc {
+			if desc {
+				cols[i] += " NULLS LAST"
+			} else {
+				cols[i] += " NULLS FIRST"
+			}
+		}
+	}
+	fmt.Fprintf(&sb, " (%s)", strings.Join(cols, ", "))
+
+	if len(idx.Extra.Include) > 0 {
+		fmt.Fprintf(&sb, " INCLUDE (%s)", quoteIdents(idx.Extra.Include))
+	}
+	if idx.Extra.NullsDistinct {
+		sb.WriteString(" NULLS NOT DISTINCT")
+	}
+	if idx.Extra.Where != "" {
+		fmt.Fprintf(&sb, " WHERE %s", idx.Extra.Where)
+	}
+
+	return sb.String()
+}
+
+func tableName(t table) string {
+	return qualifiedName(t.Schema, t.Name)
+}
+
+func qualifiedName(schema, name string) string {
+	if schema == "" {
+		return quoteIdent(name)
+	}
+
+	return quoteIdent(schema) + "." + quoteIdent(name)
+}
+
+// quoteName quotes a name that may be prefixed with its schema
+func quoteName(name string) string {
+	schema, name, ok := strings.Cut(name, ".")
+	if !ok {
+		return quoteIdent(schema)
+	}
+
+	return qualifiedName(schema, name)
+}
+
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+func quoteIdents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = quoteIdent(n)
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+func quoteString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
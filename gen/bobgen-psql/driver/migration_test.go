@@ -0,0 +1,165 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/aarondl/opt/null"
+	"github.com/google/go-cmp/cmp"
+	helpers "github.com/stephenafamo/bob/gen/bobgen-helpers"
+	"github.com/stephenafamo/bob/gen/drivers"
+)
+
+func TestMigrationSQL(t *testing.T) {
+	users := table{
+		Key:  "users",
+		Name: "users",
+		Columns: []drivers.Column{
+			{Name: "id", DBType: "integer", Default: "nextval('users_id_seq'::regclass)"},
+			{Name: "email", DBType: "character varying", TypeLimits: []string{"255"}},
+			{Name: "status", DBType: "public.user_status", Type: "enums.UserStatus", Default: "'active'::user_status"},
+		},
+		Constraints: drivers.Constraints[any]{
+			Primary: &drivers.Constraint[any]{Name: "users_pkey", Columns: []string{"id"}},
+			Uniques: []drivers.Constraint[any]{{Name: "users_email_key", Columns: []string{"email"}}},
+		},
+		Indexes: []drivers.Index[IndexExtra]{
+			{Name: "users_pkey", Type: "btree", Unique: true, Columns: []drivers.IndexColumn{{Name: "id"}}},
+			{Name: "users_email_key", Type: "btree", Unique: true, Columns: []drivers.IndexColumn{{Name: "email"}}},
+		},
+	}
+
+	posts := table{
+		Key:    "blog.posts",
+		Schema: "blog",
+		Name:   "posts",
+		Columns: []drivers.Column{
+			{Name: "id", DBType: "bigint", Default: "IDENTITY"},
+			{Name: "user_id", DBType: "integer"},
+			{Name: "tags", DBType: "text[]", Nullable: true, Default: "NULL"},
+		},
+		Constraints: drivers.Constraints[any]{
+			Primary: &drivers.Constraint[any]{Name: "posts_pkey", Columns: []string{"id"}},
+			Foreign: []drivers.ForeignKey[any]{{
+				Constraint:     drivers.Constraint[any]{Name: "posts_user_id_fkey", Columns: []string{"user_id"}},
+				ForeignTable:   "users",
+				ForeignColumns: []string{"id"},
+			}},
+		},
+		Indexes: []drivers.Index[IndexExtra]{{
+			Name:    "posts_recent_idx",
+			Type:    "btree",
+			Columns: []drivers.IndexColumn{{Name: "user_id"}, {Name: "id", Desc: null.From(true)}},
+			Extra:   IndexExtra{NullsFirst: []bool{false, true}, Where: "(user_id > 0)"},
+		}},
+	}
+
+	enum := drivers.Enum{Type: "UserStatus", Values: []string{"active", "banned"}}
+
+	changedUsers := users
+	changedUsers.Columns = []drivers.Column{
+		users.Columns[0],
+		{Name: "email", DBType: "text", Nullable: true, Default: "NULL"},
+		users.Columns[2],
+		{Name: "created_at", DBType: "timestamp with time zone", Default: "now()"},
+	}
+
+	from := &DBInfo{
+		Tables: drivers.Tables[any, IndexExtra]{users},
+		Enums:  []drivers.Enum{{Type: "UserStatus", Values: []string{"active"}}},
+	}
+	to := &DBInfo{
+		Tables: drivers.Tables[any, IndexExtra]{changedUsers, posts},
+		Enums:  []drivers.Enum{enum},
+	}
+
+	got := helpers.MigrationSQL(NewMigrationDialect(from, to), drivers.DiffDBInfo(from, to))
+
+	want := `ALTER TYPE "public"."user_status" ADD VALUE 'banned' AFTER 'active';
+
+CREATE TABLE "blog"."posts" (
+    "id" bigint GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+    "user_id" integer NOT NULL,
+    "tags" text[],
+    CONSTRAINT "posts_pkey" PRIMARY KEY ("id")
+);
+
+CREATE INDEX "posts_recent_idx" ON "blog"."posts" ("user_id", "id" DESC) WHERE (user_id > 0);
+
+ALTER TABLE "users" ADD COLUMN "created_at" timestamp with time zone DEFAULT now() NOT NULL;
+
+ALTER TABLE "users" ALTER COLUMN "email" TYPE text USING "email"::text;
+
+ALTER TABLE "users" ALTER COLUMN "email" DROP NOT NULL;
+
+ALTER TABLE "blog"."posts" ADD CONSTRAINT "posts_user_id_fkey" FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// Going back drops what was created
+	got = helpers.MigrationSQL(NewMigrationDialect(to, from), drivers.DiffDBInfo(to, from))
+
+	want = `-- "public"."user_status": the value 'banned' was removed, the type has to be recreated;
+
+ALTER TABLE "users" ALTER COLUMN "email" TYPE character varying(255) USING "email"::character varying(255);
+
+ALTER TABLE "users" ALTER COLUMN "email" SET NOT NULL;
+
+ALTER TABLE "users" DROP COLUMN "created_at";
+
+DROP TABLE "blog"."posts";
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestMigrationCreateTable(t *testing.T) {
+	users := table{
+		Key:  "users",
+		Name: "users",
+		Columns: []drivers.Column{
+			{Name: "id", DBType: "integer", Default: "nextval('users_id_seq'::regclass)"},
+			{Name: "email", DBType: "character varying", TypeLimits: []string{"255"}},
+			{Name: "scores", DBType: "numeric[]", TypeLimits: []string{"5", "2"}, Nullable: true, Default: "NULL"},
+			{Name: "domain", DBType: "text", Nullable: true, Default: "GENERATED", Generated: true},
+		},
+		Constraints: drivers.Constraints[any]{
+			Primary: &drivers.Constraint[any]{Name: "users_pkey", Columns: []string{"id"}},
+			Uniques: []drivers.Constraint[any]{{Name: "users_email_key", Columns: []string{"email"}}},
+			Checks: []drivers.Check[any]{{
+				Constraint: drivers.Constraint[any]{Name: "users_email_check"},
+				Expression: "(email <> ''::text)",
+			}},
+		},
+		Indexes: []drivers.Index[IndexExtra]{
+			{Name: "users_pkey", Type: "btree", Unique: true, Columns: []drivers.IndexColumn{{Name: "id"}}},
+			{Name: "users_email_key", Type: "btree", Unique: true, Columns: []drivers.IndexColumn{{Name: "email"}}},
+			{
+				Name: "users_lower_email_idx", Type: "hash",
+				Columns: []drivers.IndexColumn{{Name: "lower((email)::text)", IsExpression: true}},
+				Extra:   IndexExtra{Include: []string{"id"}},
+			},
+		},
+	}
+
+	got := NewMigrationDialect(&DBInfo{}, &DBInfo{}).CreateTable(users)
+	want := []string{
+		`CREATE TABLE "users" (
+    "id" serial NOT NULL,
+    "email" character varying(255) NOT NULL,
+    "scores" numeric(5,2)[],
+    CONSTRAINT "users_pkey" PRIMARY KEY ("id"),
+    CONSTRAINT "users_email_key" UNIQUE ("email"),
+    CONSTRAINT "users_email_check" CHECK (email <> ''::text)
+)`,
+		`-- The expression of the generated column "users"."domain" is not known
+-- ALTER TABLE "users" ADD COLUMN "domain" text GENERATED ALWAYS AS (<expression>) STORED`,
+		`CREATE INDEX "users_lower_email_idx" ON "users" USING hash (lower((email)::text)) INCLUDE ("id")`,
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatal(diff)
+	}
+}
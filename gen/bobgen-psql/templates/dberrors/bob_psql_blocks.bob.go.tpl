@@ -49,3 +49,29 @@ func (e *CheckConstraintError) Is(target error) bool {
 	{{end}}
 }
 {{end -}}
+
+{{- define "retryable_error_detection_method"}}
+func (e *RetryableError) Is(target error) bool {
+	{{if eq $.Driver "github.com/lib/pq" "github.com/jackc/pgx/v5" "github.com/jackc/pgx/v5/stdlib"}}
+		{{$errType := ""}}
+		{{if eq $.Driver "github.com/lib/pq"}}
+      {{$.Importer.Import "github.com/lib/pq"}}
+      {{$errType = "*pq.Error"}}
+		{{else}}
+      {{$.Importer.Import "github.com/jackc/pgx/v5/pgconn"}}
+			{{$errType = "*pgconn.PgError"}}
+		{{end}}
+    {{$.Importer.Import "errors"}}
+    var err {{$errType}}
+    if !errors.As(target, &err) {
+      return false
+    }
+
+    // 40001 is serialization_failure
+    // 40P01 is deadlock_detected
+    return err.Code == "40001" || err.Code == "40P01"
+	{{else}}
+    return false
+	{{end}}
+}
+{{end -}}
@@ -67,3 +67,26 @@ func TestCheckConstraintErrors(t *testing.T) {
 }
 {{end}}
 {{end}}
+
+{{if or (eq $.Driver "github.com/lib/pq") (hasPrefix "github.com/jackc/pgx/v5" $.Driver)}}
+{{$.Importer.Import "fmt"}}
+{{$.Importer.Import "testing"}}
+{{if eq $.Driver "github.com/lib/pq"}}
+{{$.Importer.Import "github.com/lib/pq"}}
+{{else}}
+{{$.Importer.Import "github.com/jackc/pgx/v5/pgconn"}}
+{{end}}
+
+func TestRetryableErrors(t *testing.T) {
+	for code, want := range map[string]bool{"40001": true, "40P01": true, "23505": false} {
+		{{if eq $.Driver "github.com/lib/pq"}}
+		err := fmt.Errorf("call: %w", &pq.Error{Code: pq.ErrorCode(code)})
+		{{else}}
+		err := fmt.Errorf("call: %w", &pgconn.PgError{Code: code})
+		{{end}}
+		if got := IsRetryable(err); got != want {
+			t.Errorf("IsRetryable(%s) = %t, want %t", code, got, want)
+		}
+	}
+}
+{{end}}
@@ -0,0 +1,335 @@
+package driver
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	helpers "github.com/stephenafamo/bob/gen/bobgen-helpers"
+	"github.com/stephenafamo/bob/gen/drivers"
+)
+
+type (
+	table     = drivers.Table[any, IndexExtra]
+	tableDiff = drivers.TableDiff[any, IndexExtra]
+)
+
+// NewMigrationDialect returns the dialect used to write migrations
+// from the diff of two DBInfos.
+//
+// SQLite cannot alter columns or constraints, so a changed table that
+// needs more than new columns and indexes is rebuilt: a new table is created,
+// the rows are copied over and the old table is replaced
+func NewMigrationDialect(from, to *DBInfo) helpers.MigrationDialect[any, IndexExtra] {
+	m := migration{tables: map[string]table{}}
+	for _, info := range []*DBInfo{from, to} {
+		for _, t := range info.Tables {
+			m.tables[t.Key] = t
+		}
+	}
+
+	return m
+}
+
+type migration struct {
+	tables map[string]table
+}
+
+func (migration) CreateEnum(drivers.Enum) []string    { return nil }
+func (migration) AlterEnum(drivers.EnumDiff) []string { return nil }
+func (migration) DropEnum(drivers.Enum) []string      { return nil }
+
+// CreateTable includes the foreign keys since they cannot be added later
+func (m migration) CreateTable(t table) []string {
+	stmts := []string{m.createTable(t, tableName(t))}
+	stmts = append(stmts, addGeneratedColumns(t)...)
+
+	for _, idx := range t.Indexes {
+		if idx.Type == "c" {
+			stmts = append(stmts, createIndex(t, idx))
+		}
+	}
+
+	return stmts
+}
+
+func (m migration) createTable(t table, name string) string {
+	defs := make([]string, 0, len(t.Columns))
+	for _, c := range t.Columns {
+		if c.Generated {
+			// added afterwards by addGeneratedColumns
+			continue
+		}
+		defs = append(defs, columnDefinition(c))
+	}
+
+	if pk := t.Constraints.Primary; pk != nil {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", quoteIdents(pk.Columns)))
+	}
+	for _, u := range t.Constraints.Uniques {
+		defs = append(defs, fmt.Sprintf("UNIQUE (%s)", quoteIdents(u.Columns)))
+	}
+	for _, fk := range t.Constraints.Foreign {
+		foreign := fk.ForeignTable
+		if ft, ok := m.tables[fk.ForeignTable]; ok {
+			foreign = ft.Name
+		}
+
+		defs = append(defs, fmt.Sprintf(
+			"FOREIGN KEY (%s) REFERENCES %s (%s)",
+			quoteIdents(fk.Columns), quoteIdent(foreign), quoteIdents(fk.ForeignColumns),
+		))
+	}
+	for _, c := range t.Constraints.Checks {
+		defs = append(defs, fmt.Sprintf("CONSTRAINT %s CHECK (%s)", quoteIdent(c.Name), c.Expression))
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (\n    %s\n)", name, strings.Join(defs, ",\n    "))
+}
+
+func (m migration) DropTable(t table) []string {
+	return []string{"DROP TABLE " + tableName(t)}
+}
+
+func (m migration) DropConstraints(t tableDiff) []string {
+	if needsRebuild(t) {
+		return nil
+	}
+
+	var stmts []string
+	for _, idx := range t.DroppedIndexes {
+		if idx.Type == "c" {
+			stmts = append(stmts, "DROP INDEX "+qualifiedName(t.Old.Schema, idx.Name))
+		}
+	}
+
+	return stmts
+}
+
+func (m migration) AlterTable(t tableDiff) []string {
+	if needsRebuild(t) {
+		return m.rebuild(t)
+	}
+
+	var stmts []string
+	for _, c := range t.AddedColumns {
+		if c.Generated {
+			stmts = append(stmts, addGeneratedColumn(t.New, c))
+			continue
+		}
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", tableName(t.New), columnDefinition(c)))
+	}
+
+	for _, idx := range t.AddedIndexes {
+		if idx.Type == "c" {
+			stmts = append(stmts, createIndex(t.New, idx))
+		}
+	}
+
+	return stmts
+}
+
+// AddForeignKeys does nothing, foreign keys are added when a table is created or rebuilt
+func (m migration) AddForeignKeys(table, []drivers.ForeignKey[any]) []string {
+	return nil
+}
+
+func (m migration) DropColumns(t tableDiff) []string {
+	if needsRebuild(t) {
+		return nil
+	}
+
+	stmts := make([]string, len(t.DroppedColumns))
+	for i, c := range t.DroppedColumns {
+		stmts[i] = fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", tableName(t.New), quoteIdent(c.Name))
+	}
+
+	return stmts
+}
+
+// rebuild follows the steps recommended by SQLite to make
+// changes that ALTER TABLE does not support
+// https://www.sqlite.org/lang_altertable.html#otheralter
+func (m migration) rebuild(t tableDiff) []string {
+	tmpName := qualifiedName(t.New.Schema, "new_"+t.New.Name)
+
+	var common []string
+	for _, c := range t.New.Columns {
+		if c.Generated {
+			continue
+		}
+		if slices.ContainsFunc(t.Old.Columns, func(old drivers.Column) bool { return old.Name == c.Name }) {
+			common = append(common, quoteIdent(c.Name))
+		}
+	}
+
+	stmts := []string{
+		fmt.Sprintf("-- %s is rebuilt since SQLite cannot alter it in place.\n-- Foreign key checks should be disabled while it is rebuilt\n%s",
+			tableName(t.New), m.createTable(t.New, tmpName)),
+		fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s",
+			tmpName, strings.Join(common, ", "), strings.Join(common, ", "), tableName(t.Old)),
+		"DROP TABLE " + tableName(t.Old),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", tmpName, quoteIdent(t.New.Name)),
+	}
+	stmts = append(stmts, addGeneratedColumns(t.New)...)
+
+	for _, idx := range t.New.Indexes {
+		if idx.Type == "c" {
+			stmts = append(stmts, createIndex(t.New, idx))
+		}
+	}
+
+	return stmts
+}
+
+// needsRebuild checks if the changes to the table can only be made
+// by rebuilding the table
+func needsRebuild(t tableDiff) bool {
+	if len(t.ChangedColumns) > 0 ||
+		t.AddedPrimaryKey != nil || t.DroppedPrimaryKey != nil ||
+		len(t.AddedUniques) > 0 || len(t.DroppedUniques) > 0 ||
+		len(t.AddedForeignKeys) > 0 || len(t.DroppedForeignKeys) > 0 ||
+		len(t.AddedChecks) > 0 || len(t.DroppedChecks) > 0 {
+		return true
+	}
+
+	// ADD COLUMN cannot add a NOT NULL column without a default
+	for _, c := range t.AddedColumns {
+		if !c.Generated && !c.Nullable && !hasDefault(c) {
+			return true
+		}
+	}
+
+	// DROP COLUMN cannot drop a column used by an index or constraint
+	for _, c := range t.DroppedColumns {
+		for _, idx := range t.Old.Indexes {
+			if slices.ContainsFunc(idx.Columns, func(ic drivers.IndexColumn) bool { return ic.Name == c.Name }) {
+				return true
+			}
+		}
+
+		for _, fk := range t.Old.Constraints.Foreign {
+			if slices.Contains(fk.Columns, c.Name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func columnDefinition(c drivers.Column) string {
+	var def strings.Builder
+	def.WriteString(quoteIdent(c.Name))
+	if c.DBType != "" {
+		def.WriteString(" ")
+		def.WriteString(c.DBType)
+	}
+
+	if hasDefault(c) {
+		def.WriteString(" DEFAULT ")
+		def.WriteString(c.Default)
+	}
+
+	if !c.Nullable {
+		def.WriteString(" NOT NULL")
+	}
+
+	return def.String()
+}
+
+// addGeneratedColumns writes the statements to add the generated columns of the table
+func addGeneratedColumns(t table) []string {
+	var stmts []string
+	for _, c := range t.Columns {
+		if c.Generated {
+			stmts = append(stmts, addGeneratedColumn(t, c))
+		}
+	}
+
+	return stmts
+}
+
+// addGeneratedColumn writes the statement to add a generated column as a comment,
+// since the expression of the column is not known and has to be filled in.
+// ADD COLUMN can only add VIRTUAL columns, STORED columns need the table to be rebuilt
+func addGeneratedColumn(t table, c drivers.Column) string {
+	def := quoteIdent(c.Name)
+	if c.DBType != "" {
+		def += " " + c.DBType
+	}
+
+	return fmt.Sprintf(
+		"-- The expression of the generated column %s.%s is not known\n-- ALTER TABLE %s ADD COLUMN %s GENERATED ALWAYS AS (<expression>) VIRTUAL",
+		tableName(t), quoteIdent(c.Name), tableName(t), def,
+	)
+}
+
+func hasDefault(c drivers.Column) bool {
+	switch c.Default {
+	case "", "NULL", "auto_increment", "auto_generated":
+		return false
+	default:
+		return true
+	}
+}
+
+func createIndex(t table, idx drivers.Index[IndexExtra]) string {
+	var sb strings.Builder
+
+	sb.WriteString("CREATE ")
+	if idx.Unique {
+		sb.WriteString("UNIQUE ")
+	}
+
+	cols := make([]string, len(idx.Columns))
+	for i, c := range idx.Columns {
+		// the expression is written as it is in the index definition
+		if c.IsExpression {
+			cols[i] = c.Name
+			continue
+		}
+
+		cols[i] = quoteIdent(c.Name)
+		if c.Desc.GetOrZero() {
+			cols[i] += " DESC"
+		}
+	}
+
+	fmt.Fprintf(&sb, "INDEX %s ON %s (%s)",
+		qualifiedName(t.Schema, idx.Name), quoteIdent(t.Name), strings.Join(cols, ", "))
+
+	// Without its WHERE clause, the index would cover every row.
+	// A unique index would then reject rows that the partial index allows
+	if idx.Extra.Partial {
+		return fmt.Sprintf("-- The WHERE clause of the partial index %s is not known\n-- %s WHERE <condition>",
+			qualifiedName(t.Schema, idx.Name), sb.String())
+	}
+
+	return sb.String()
+}
+
+func tableName(t table) string {
+	return qualifiedName(t.Schema, t.Name)
+}
+
+func qualifiedName(schema, name string) string {
+	if schema == "" {
+		return quoteIdent(name)
+	}
+
+	return quoteIdent(schema) + "." + quoteIdent(name)
+}
+
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+func quoteIdents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = quoteIdent(n)
+	}
+
+	return strings.Join(quoted, ", ")
+}
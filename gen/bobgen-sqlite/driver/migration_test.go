@@ -0,0 +1,134 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	helpers "github.com/stephenafamo/bob/gen/bobgen-helpers"
+	"github.com/stephenafamo/bob/gen/drivers"
+)
+
+const migrationSchema = `
+CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT NOT NULL UNIQUE, name TEXT);
+CREATE TABLE posts (
+    id INTEGER PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id),
+    title TEXT NOT NULL DEFAULT '',
+    body TEXT
+);
+CREATE INDEX posts_title ON posts(title);
+`
+
+func TestMigrationSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes string
+	}{
+		{
+			name:    "add table",
+			changes: `CREATE TABLE tags (id INTEGER PRIMARY KEY, post_id INT REFERENCES posts(id), name TEXT NOT NULL);`,
+		},
+		{
+			name:    "drop table",
+			changes: `DROP TABLE posts;`,
+		},
+		{
+			name: "add and drop columns",
+			changes: `ALTER TABLE users ADD COLUMN age INT;
+				ALTER TABLE posts DROP COLUMN body;`,
+		},
+		{
+			name: "change indexes",
+			changes: `DROP INDEX posts_title;
+				CREATE UNIQUE INDEX posts_user_title ON posts(user_id DESC, title);`,
+		},
+		{
+			name: "rebuild table",
+			changes: `CREATE TABLE new_users (id INTEGER PRIMARY KEY, email TEXT NOT NULL, name TEXT NOT NULL DEFAULT 'x');
+				INSERT INTO new_users SELECT id, email, coalesce(name, 'x') FROM users;
+				DROP TABLE users;
+				ALTER TABLE new_users RENAME TO users;`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			current := filepath.Join(dir, "current.db")
+			migrated := filepath.Join(dir, "migrated.db")
+
+			from := assembleSQLite(t, current, migrationSchema)
+			to := assembleSQLite(t, current, tt.changes)
+
+			diff := drivers.DiffDBInfo(from, to)
+			if diff.IsEmpty() {
+				t.Fatal("expected changes")
+			}
+
+			migration := helpers.MigrationSQL(NewMigrationDialect(from, to), diff)
+			got := assembleSQLite(t, migrated, migrationSchema, migration)
+
+			if remaining := drivers.DiffDBInfo(to, got); !remaining.IsEmpty() {
+				t.Fatalf("migration did not apply all changes:\n%s\nmigration:\n%s", remaining, migration)
+			}
+		})
+	}
+}
+
+func TestMigrationSQLUnknownExpressions(t *testing.T) {
+	dir := t.TempDir()
+	current := filepath.Join(dir, "current.db")
+	migrated := filepath.Join(dir, "migrated.db")
+
+	from := assembleSQLite(t, current, migrationSchema)
+	to := assembleSQLite(t, current, `ALTER TABLE users ADD COLUMN domain TEXT GENERATED ALWAYS AS (substr(email, instr(email, '@') + 1)) VIRTUAL;
+		CREATE UNIQUE INDEX posts_user_title ON posts(user_id, title) WHERE body IS NOT NULL;`)
+
+	migration := helpers.MigrationSQL(NewMigrationDialect(from, to), drivers.DiffDBInfo(from, to))
+	for _, want := range []string{
+		`-- The expression of the generated column "users"."domain" is not known
+-- ALTER TABLE "users" ADD COLUMN "domain" TEXT GENERATED ALWAYS AS (<expression>) VIRTUAL;`,
+		`-- The WHERE clause of the partial index "posts_user_title" is not known
+-- CREATE UNIQUE INDEX "posts_user_title" ON "posts" ("user_id", "title") WHERE <condition>;`,
+	} {
+		if !strings.Contains(migration, want) {
+			t.Fatalf("expected the migration to contain:\n%s\nmigration:\n%s", want, migration)
+		}
+	}
+
+	// the commented out statements are not run
+	got := assembleSQLite(t, migrated, migrationSchema, migration)
+	if remaining := drivers.DiffDBInfo(got, from); !remaining.IsEmpty() {
+		t.Fatalf("expected no changes to be applied:\n%s\nmigration:\n%s", remaining, migration)
+	}
+}
+
+// assembleSQLite runs the statements in the database file
+// and returns the DBInfo of the database
+func assembleSQLite(t *testing.T, path string, stmts ...string) *DBInfo {
+	t.Helper()
+	ctx := context.Background()
+	dsn := "file:" + path
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, stmt := range stmts {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("running %s: %v", stmt, err)
+		}
+	}
+
+	info, err := New(Config{Config: helpers.Config{Dsn: dsn}}).Assemble(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return info
+}
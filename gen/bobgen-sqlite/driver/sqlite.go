@@ -188,7 +188,10 @@ func inferDriver(config Config) string {
 
 func (d *driver) buildQuery(schema string) (string, []any) {
 	var args []any
-	query := fmt.Sprintf(`SELECT name FROM %q.sqlite_schema WHERE name NOT LIKE 'sqlite_%%' AND type IN ('table', 'view')`, schema)
+	// Shadow tables store the data of virtual tables, such as FTS5 tables,
+	// and are not queried directly
+	query := fmt.Sprintf(`SELECT name FROM %q.sqlite_schema WHERE name NOT LIKE 'sqlite_%%' AND type IN ('table', 'view')
+		AND name NOT IN (SELECT name FROM pragma_table_list WHERE schema = '%s' AND type = 'shadow')`, schema, schema)
 
 	tableFilter := drivers.ParseTableFilter(d.config.Only, d.config.Except)
 
@@ -294,6 +297,11 @@ func (d driver) getTable(ctx context.Context, schema, name string, colFilter dri
 		return table, err
 	}
 
+	table.Module, err = d.virtualTableModule(ctx, schema, name)
+	if err != nil {
+		return table, err
+	}
+
 	table.Columns, err = d.columns(ctx, schema, name, tinfo, colFilter)
 	if err != nil {
 		return table, err
@@ -363,6 +371,29 @@ func (d driver) getTable(ctx context.Context, schema, name string, colFilter dri
 	return table, nil
 }
 
+//nolint:gochecknoglobals
+var virtualTableRgx = regexp.MustCompile(`(?is)^\s*CREATE\s+VIRTUAL\s+TABLE\s.*?\sUSING\s+(\w+)`)
+
+// virtualTableModule returns the module of a virtual table, such as "fts5",
+// or an empty string if the table is not a virtual table
+func (d driver) virtualTableModule(ctx context.Context, schema, tableName string) (string, error) {
+	//nolint:gosec
+	query := fmt.Sprintf("SELECT sql FROM '%s'.sqlite_master WHERE type = 'table' AND name = ?", schema)
+
+	var createSQL sql.NullString
+	err := d.conn.QueryRowContext(ctx, query, tableName).Scan(&createSQL)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("virtual table query: %w", err)
+	}
+
+	match := virtualTableRgx.FindStringSubmatch(createSQL.String)
+	if match == nil {
+		return "", nil
+	}
+
+	return strings.ToLower(match[1]), nil
+}
+
 // Columns takes a table name and attempts to retrieve the table information
 // from the database. It retrieves the column names
 // and column types and returns those as a []Column after TranslateColumnType()
@@ -400,6 +431,11 @@ func (d driver) columns(ctx context.Context, schema, tableName string, tinfo []i
 		if _, ok := excludedColumns[colInfo.Name]; ok {
 			continue
 		}
+		// Hidden columns of virtual tables, such as the rank column of FTS5 tables,
+		// are not returned by SELECT *
+		if colInfo.Hidden == 1 {
+			continue
+		}
 		column := drivers.Column{
 			Name:     colInfo.Name,
 			DBType:   strings.ToUpper(colInfo.Type),
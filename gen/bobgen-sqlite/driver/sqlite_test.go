@@ -217,7 +217,8 @@ func testSQLiteDriver(t *testing.T, config Config) {
 				OverwriteGolden: overwriteGolden,
 				Templates:       gen.SQLiteTemplates,
 				Dialect:         "sqlite",
-				GoTestArgs:      []string{"-p=1"},
+				// FTS5 is only included in mattn/go-sqlite3 with the sqlite_fts5 tag
+				GoTestArgs: []string{"-p=1", "-tags=sqlite_fts5"},
 			})
 		})
 	}
@@ -36,6 +36,9 @@ func main() {
 			},
 		},
 		Action: run,
+		Commands: []*cli.Command{
+			helpers.DiffCommand(newDriver, driver.NewMigrationDialect),
+		},
 	}
 
 	if err := app.RunContext(ctx, os.Args); err != nil {
@@ -57,3 +60,12 @@ func run(c *cli.Context) error {
 	state := &gen.State[any]{Config: config}
 	return gen.Run(c.Context, state, driver.New(driverConfig), outputPlugins...)
 }
+
+func newDriver(configPath string) (driver.Interface, error) {
+	_, driverConfig, _, err := helpers.GetConfigFromFile[any, driver.Config](configPath, "sqlite")
+	if err != nil {
+		return nil, err
+	}
+
+	return driver.New(driverConfig), nil
+}
@@ -53,3 +53,44 @@ func (e *UniqueConstraintError) Is(target error) bool {
 	{{end}}
 }
 {{end -}}
+
+{{- define "retryable_error_detection_method" -}}
+func (e *RetryableError) Is(target error) bool {
+	{{if eq $.Driver "github.com/tursodatabase/libsql-client-go/libsql"}}
+    {{$.Importer.Import "strings"}}
+    return strings.Contains(target.Error(), "SQLITE_BUSY") ||
+      strings.Contains(target.Error(), "database is locked")
+	{{else if eq $.Driver "modernc.org/sqlite" "github.com/mattn/go-sqlite3" "github.com/ncruces/go-sqlite3"}}
+    {{$.Importer.Import "errors"}}
+		{{if eq $.Driver "modernc.org/sqlite"}}
+			{{$.Importer.Import "sqliteDriver" $.Driver}}
+    var err *sqliteDriver.Error
+    if !errors.As(target, &err) {
+      return false
+    }
+
+    // 5 is SQLITE_BUSY and 6 is SQLITE_LOCKED, the extended codes share the lower byte
+    code := err.Code() & 0xff
+    return code == 5 || code == 6
+		{{else if eq $.Driver "github.com/mattn/go-sqlite3"}}
+			{{$.Importer.Import $.Driver}}
+    var err sqlite3.Error
+    if !errors.As(target, &err) {
+      return false
+    }
+
+    return err.Code == sqlite3.ErrBusy || err.Code == sqlite3.ErrLocked
+		{{else}}
+			{{$.Importer.Import $.Driver}}
+    var err *sqlite3.Error
+    if !errors.As(target, &err) {
+      return false
+    }
+
+    return err.Code() == sqlite3.BUSY || err.Code() == sqlite3.LOCKED
+		{{end}}
+	{{else}}
+    return false
+	{{end}}
+}
+{{end -}}
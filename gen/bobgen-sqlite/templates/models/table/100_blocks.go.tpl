@@ -12,6 +12,14 @@ func (s *{{$tAlias.UpSingular}}Setter) Apply(q *dialect.InsertQuery) {
 
   if len(q.TableRef.Columns) == 0 {
     q.TableRef.Columns = s.SetColumns()
+    {{- block "setter/insert/columns" (dict "Data" $ "Table" $table)}}{{end}}
+    {{- with index $.TenantColumns $table.Key}}
+    {{$.Importer.Import "slices"}}
+    if !slices.Contains(q.TableRef.Columns, {{quote .}}) {
+      // the tenant is always inserted, see the value below
+      q.TableRef.Columns = append(q.TableRef.Columns, {{quote .}})
+    }
+    {{- end}}
     {{if $table.Constraints.Primary -}}
     if len(q.TableRef.Columns) == 0 {
       q.TableRef.Columns = {{printf "%#v" $table.Constraints.Primary.Columns}}
@@ -25,10 +33,11 @@ func (s *{{$tAlias.UpSingular}}Setter) Apply(q *dialect.InsertQuery) {
     {{range $column := $table.NonGeneratedColumns -}}
     case {{printf "%q" $column.Name}}:
       vals = append(vals, bob.ExpressionFunc(func(ctx context.Context, w io.StringWriter, d bob.Dialect, start int) ([]any, error) {
+        {{- template "setter/insert/tenant" (dict "Data" $ "Table" $table "Column" $column)}}
         {{$colAlias := $tAlias.Column $column.Name -}}
         {{$colGetter := $.Types.FromOptional $.CurrentPackage $.Importer $column.Type (cat "s." $colAlias) $column.Nullable $column.Nullable -}}
         if {{$.Types.IsOptionalInvalid $.CurrentPackage $column.Type $column.Nullable (cat "s." $colAlias)}} {
-          return {{$.Dialect}}.Arg(nil).WriteSQL(ctx, w, d, start)
+          {{- template "setter/insert/unset" (dict "Data" $ "Table" $table "Column" $column "Default" (printf "%s.Arg(nil)" $.Dialect))}}
         }
         return {{$.Dialect}}.Arg({{$colGetter}}).WriteSQL(ctx, w, d, start)
       }))
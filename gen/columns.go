@@ -0,0 +1,95 @@
+package gen
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/stephenafamo/bob/gen/drivers"
+)
+
+// processVersionColumns finds the column used for optimistic locking in each table.
+// It returns a map of table keys to column names.
+// Only tables with a primary key are considered, and the column must be an integer,
+// since it is incremented on every update, and must not be nullable, generated
+// or part of the primary key
+func processVersionColumns[C, I any](sel ColumnSelector, tables []drivers.Table[C, I]) map[string]string {
+	return FindColumns("version", sel, tables, func(t drivers.Table[C, I], c drivers.Column) string {
+		if !isIntegerType(c.Type) {
+			return fmt.Sprintf("it must be an integer, not %s", c.Type)
+		}
+		if c.Nullable || c.Generated || slices.Contains(t.Constraints.Primary.Columns, c.Name) {
+			return "it must not be nullable, generated or part of the primary key"
+		}
+		return ""
+	})
+}
+
+// processSoftDeleteColumns finds the column used for soft deletes in each table.
+// It returns a map of table keys to column names.
+// Only tables with a primary key are considered, and the column must be
+// nullable, since a NULL value marks a row that is not deleted
+func processSoftDeleteColumns[C, I any](sel ColumnSelector, tables []drivers.Table[C, I]) map[string]string {
+	return FindColumns("soft delete", sel, tables, func(t drivers.Table[C, I], c drivers.Column) string {
+		if !c.Nullable || c.Generated || slices.Contains(t.Constraints.Primary.Columns, c.Name) {
+			return "it must be nullable, and must not be generated or part of the primary key"
+		}
+		return ""
+	})
+}
+
+// processTenantColumns finds the column that holds the tenant of each row in each table.
+// It returns a map of table keys to column names.
+// Only tables with a primary key are considered, and the column must not be generated
+func processTenantColumns[C, I any](sel ColumnSelector, tables []drivers.Table[C, I]) map[string]string {
+	return FindColumns("tenant", sel, tables, func(t drivers.Table[C, I], c drivers.Column) string {
+		if c.Generated {
+			return "it must not be generated"
+		}
+		return ""
+	})
+}
+
+// FindColumns returns the first column matched by the selector in each table
+// with a primary key, as a map of table keys to column names.
+// check returns the reason a matched column cannot be used, or an empty string if it can.
+// kind names the column in the warnings printed for skipped columns
+func FindColumns[C, I any](kind string, sel ColumnSelector, tables []drivers.Table[C, I], check func(drivers.Table[C, I], drivers.Column) string) map[string]string {
+	found := make(map[string]string)
+	if sel.IsEmpty() {
+		return found
+	}
+
+	for _, t := range tables {
+		if t.Constraints.Primary == nil {
+			continue
+		}
+
+		if len(sel.Tables) > 0 && !slices.Contains(sel.Tables, t.Key) {
+			continue
+		}
+
+		for _, c := range t.Columns {
+			if !sel.Matches(c) {
+				continue
+			}
+
+			if reason := check(t, c); reason != "" {
+				fmt.Printf("WARNING: Cannot use %s.%s as the %s column, %s\n", t.Key, c.Name, kind, reason)
+				continue
+			}
+
+			if other, ok := found[t.Key]; ok {
+				fmt.Printf("WARNING: Multiple %s columns found in %s, using %q and ignoring %q\n", kind, t.Key, other, c.Name)
+				continue
+			}
+
+			found[t.Key] = c.Name
+		}
+	}
+
+	if len(found) == 0 {
+		fmt.Printf("WARNING: No match found for %s column: %+v\n", kind, sel)
+	}
+
+	return found
+}
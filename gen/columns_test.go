@@ -0,0 +1,129 @@
+package gen
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/stephenafamo/bob/gen/drivers"
+	"github.com/stephenafamo/bob/internal"
+)
+
+func TestProcessVersionColumns(t *testing.T) {
+	pk := &drivers.Constraint[any]{Columns: []string{"id"}}
+	tables := drivers.Tables[any, any]{
+		{
+			Key:         "users",
+			Columns:     []drivers.Column{{Name: "id", Type: "int64"}, {Name: "version", Type: "int64"}},
+			Constraints: drivers.Constraints[any]{Primary: pk},
+		},
+		{
+			Key:         "jets",
+			Columns:     []drivers.Column{{Name: "id", Type: "int64"}, {Name: "lock_version", Type: "int32", Comment: "lock"}},
+			Constraints: drivers.Constraints[any]{Primary: pk},
+		},
+		{
+			Key:         "nullable",
+			Columns:     []drivers.Column{{Name: "id", Type: "int64"}, {Name: "version", Type: "int64", Nullable: true}},
+			Constraints: drivers.Constraints[any]{Primary: pk},
+		},
+		{
+			Key:         "releases",
+			Columns:     []drivers.Column{{Name: "id", Type: "int64"}, {Name: "version", Type: "string"}},
+			Constraints: drivers.Constraints[any]{Primary: pk},
+		},
+		{
+			Key:     "views",
+			Columns: []drivers.Column{{Name: "version", Type: "int64"}},
+		},
+	}
+
+	tests := map[string]struct {
+		sel      ColumnSelector
+		expected map[string]string
+	}{
+		"empty": {
+			expected: map[string]string{},
+		},
+		"name": {
+			sel:      ColumnSelector{Name: "version"},
+			expected: map[string]string{"users": "version"},
+		},
+		"regex": {
+			sel:      ColumnSelector{Name: "/version$/"},
+			expected: map[string]string{"users": "version", "jets": "lock_version"},
+		},
+		"match": {
+			sel:      ColumnSelector{Match: ColumnFilter{Comment: internal.Pointer("lock")}},
+			expected: map[string]string{"jets": "lock_version"},
+		},
+		"tables": {
+			sel:      ColumnSelector{Name: "/version$/", Tables: []string{"jets"}},
+			expected: map[string]string{"jets": "lock_version"},
+		},
+		"primary key": {
+			sel:      ColumnSelector{Name: "id"},
+			expected: map[string]string{},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := processVersionColumns(tc.sel, tables)
+			if !maps.Equal(got, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestProcessSoftDeleteColumns(t *testing.T) {
+	pk := &drivers.Constraint[any]{Columns: []string{"id"}}
+	tables := drivers.Tables[any, any]{
+		{
+			Key:         "users",
+			Columns:     []drivers.Column{{Name: "id"}, {Name: "deleted_at", Nullable: true}},
+			Constraints: drivers.Constraints[any]{Primary: pk},
+		},
+		{
+			Key:         "required",
+			Columns:     []drivers.Column{{Name: "id"}, {Name: "deleted_at"}},
+			Constraints: drivers.Constraints[any]{Primary: pk},
+		},
+		{
+			Key:     "views",
+			Columns: []drivers.Column{{Name: "deleted_at", Nullable: true}},
+		},
+	}
+
+	got := processSoftDeleteColumns(ColumnSelector{Name: "deleted_at"}, tables)
+	expected := map[string]string{"users": "deleted_at"}
+	if !maps.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestProcessTenantColumns(t *testing.T) {
+	pk := &drivers.Constraint[any]{Columns: []string{"id"}}
+	tables := drivers.Tables[any, any]{
+		{
+			Key:         "users",
+			Columns:     []drivers.Column{{Name: "id"}, {Name: "tenant_id"}},
+			Constraints: drivers.Constraints[any]{Primary: pk},
+		},
+		{
+			Key:         "generated",
+			Columns:     []drivers.Column{{Name: "id"}, {Name: "tenant_id", Generated: true}},
+			Constraints: drivers.Constraints[any]{Primary: pk},
+		},
+		{
+			Key:     "views",
+			Columns: []drivers.Column{{Name: "tenant_id"}},
+		},
+	}
+
+	got := processTenantColumns(ColumnSelector{Name: "tenant_id"}, tables)
+	expected := map[string]string{"users": "tenant_id"}
+	if !maps.Equal(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
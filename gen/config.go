@@ -45,6 +45,18 @@ type Config[ConstraintExtra any] struct {
 	Replacements []Replace   `yaml:"replacements"`
 	Inflections  Inflections `yaml:"inflections"`
 
+	// Column used for optimistic locking. The generated Update, UpdateAll and Delete
+	// methods only affect rows with the version of the loaded model, and increment it
+	VersionColumn ColumnSelector `yaml:"version_column"`
+
+	// Column used for soft deletes. Queries exclude rows where it is not NULL,
+	// and the generated Delete methods set it instead of deleting the rows
+	SoftDeleteColumn ColumnSelector `yaml:"soft_delete_column"`
+
+	// Column that holds the tenant of each row. Queries, updates and deletes are
+	// scoped to the tenant in the context, and inserts set it
+	TenantColumn ColumnSelector `yaml:"tenant_column"`
+
 	// Customize the generator name in the top level comment of generated files
 	// >>   Code generated by **GENERATOR NAME**. DO NOT EDIT.
 	// defaults to "BobGen [driver] [version]"
@@ -58,6 +70,31 @@ type Replace struct {
 	Replace string       `yaml:"replace"`
 }
 
+// ColumnSelector selects a column in each table that has a special meaning
+// for the generated code. A column is selected if its name matches Name,
+// or if it matches the Match filter
+type ColumnSelector struct {
+	// Name of the column. Can be a regular expression enclosed with / slashes
+	Name string `yaml:"name"`
+	// Match the column with a filter, for cases where the name is not enough
+	Match ColumnFilter `yaml:"match"`
+	// Tables to look in. Matches all tables if empty
+	Tables []string `yaml:"tables"`
+}
+
+func (s ColumnSelector) IsEmpty() bool {
+	return s.Name == "" && s.Match.IsEmpty()
+}
+
+// Matches determines if a drivers.Column is selected
+func (s ColumnSelector) Matches(column drivers.Column) bool {
+	if s.Name != "" && matchString(s.Name, column.Name) {
+		return true
+	}
+
+	return s.Match.Matches(column)
+}
+
 // ColumnFilter is used to filter columns in the config file.
 // It should mirror the fields of drivers.Column
 type ColumnFilter struct {
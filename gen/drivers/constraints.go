@@ -2,6 +2,7 @@ package drivers
 
 import (
 	"encoding/json"
+	"reflect"
 
 	"github.com/aarondl/opt/null"
 )
@@ -34,6 +35,25 @@ func (i Index[E]) HasExpressionColumn() bool {
 	return false
 }
 
+// IsPartial reports whether the index only covers the rows matching a predicate.
+// The drivers record it in the extra info, as Where in psql and Partial in sqlite
+func (i Index[E]) IsPartial() bool {
+	extra := reflect.Indirect(reflect.ValueOf(i.Extra))
+	if extra.Kind() != reflect.Struct {
+		return false
+	}
+
+	if where := extra.FieldByName("Where"); where.Kind() == reflect.String && where.String() != "" {
+		return true
+	}
+
+	if partial := extra.FieldByName("Partial"); partial.Kind() == reflect.Bool && partial.Bool() {
+		return true
+	}
+
+	return false
+}
+
 func (i Index[E]) NonExpressionColumns() []string {
 	cols := make([]string, 0, len(i.Columns))
 	for _, c := range i.Columns {
@@ -0,0 +1,322 @@
+package drivers
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// DBInfoDiff holds the schema changes needed to go from one DBInfo to another.
+// Tables, columns, constraints and indexes are matched by their key or name.
+// A constraint or index that was changed is both dropped and added
+type DBInfoDiff[C, I any] struct {
+	AddedTables   []Table[C, I]     `json:"added_tables"`
+	DroppedTables []Table[C, I]     `json:"dropped_tables"`
+	ChangedTables []TableDiff[C, I] `json:"changed_tables"`
+
+	AddedEnums   []Enum     `json:"added_enums"`
+	DroppedEnums []Enum     `json:"dropped_enums"`
+	ChangedEnums []EnumDiff `json:"changed_enums"`
+}
+
+// TableDiff holds the changes to a table that exists in both DBInfos
+type TableDiff[C, I any] struct {
+	Key string `json:"key"`
+	// The table before and after the changes
+	Old Table[C, I] `json:"old"`
+	New Table[C, I] `json:"new"`
+
+	AddedColumns   []Column     `json:"added_columns"`
+	DroppedColumns []Column     `json:"dropped_columns"`
+	ChangedColumns []ColumnDiff `json:"changed_columns"`
+
+	AddedPrimaryKey    *Constraint[C]  `json:"added_primary_key"`
+	DroppedPrimaryKey  *Constraint[C]  `json:"dropped_primary_key"`
+	AddedUniques       []Constraint[C] `json:"added_uniques"`
+	DroppedUniques     []Constraint[C] `json:"dropped_uniques"`
+	AddedForeignKeys   []ForeignKey[C] `json:"added_foreign_keys"`
+	DroppedForeignKeys []ForeignKey[C] `json:"dropped_foreign_keys"`
+	AddedChecks        []Check[C]      `json:"added_checks"`
+	DroppedChecks      []Check[C]      `json:"dropped_checks"`
+
+	AddedIndexes   []Index[I] `json:"added_indexes"`
+	DroppedIndexes []Index[I] `json:"dropped_indexes"`
+}
+
+// ColumnDiff holds a column whose definition was changed
+type ColumnDiff struct {
+	Old Column `json:"old"`
+	New Column `json:"new"`
+}
+
+// EnumDiff holds an enum whose values were changed
+type EnumDiff struct {
+	Old Enum `json:"old"`
+	New Enum `json:"new"`
+}
+
+// DiffDBInfo compares two DBInfos and returns the changes needed to go from
+// the first to the second. Query folders and extra information are not compared
+func DiffDBInfo[T, C, I any](from, to *DBInfo[T, C, I]) DBInfoDiff[C, I] {
+	var diff DBInfoDiff[C, I]
+
+	for _, table := range to.Tables {
+		old, ok := findTable(from.Tables, table.Key)
+		if !ok {
+			diff.AddedTables = append(diff.AddedTables, table)
+			continue
+		}
+
+		if tableDiff := diffTable(old, table); !tableDiff.IsEmpty() {
+			diff.ChangedTables = append(diff.ChangedTables, tableDiff)
+		}
+	}
+
+	for _, table := range from.Tables {
+		if _, ok := findTable(to.Tables, table.Key); !ok {
+			diff.DroppedTables = append(diff.DroppedTables, table)
+		}
+	}
+
+	for _, enum := range to.Enums {
+		i := slices.IndexFunc(from.Enums, func(e Enum) bool { return e.Type == enum.Type })
+		switch {
+		case i == -1:
+			diff.AddedEnums = append(diff.AddedEnums, enum)
+		case !slices.Equal(from.Enums[i].Values, enum.Values):
+			diff.ChangedEnums = append(diff.ChangedEnums, EnumDiff{Old: from.Enums[i], New: enum})
+		}
+	}
+
+	for _, enum := range from.Enums {
+		if !slices.ContainsFunc(to.Enums, func(e Enum) bool { return e.Type == enum.Type }) {
+			diff.DroppedEnums = append(diff.DroppedEnums, enum)
+		}
+	}
+
+	return diff
+}
+
+// IsEmpty returns true if there are no changes
+func (d DBInfoDiff[C, I]) IsEmpty() bool {
+	return len(d.AddedTables) == 0 && len(d.DroppedTables) == 0 &&
+		len(d.ChangedTables) == 0 && len(d.AddedEnums) == 0 &&
+		len(d.DroppedEnums) == 0 && len(d.ChangedEnums) == 0
+}
+
+// String returns a summary of the changes, one per line
+func (d DBInfoDiff[C, I]) String() string {
+	var sb strings.Builder
+
+	for _, e := range d.AddedEnums {
+		fmt.Fprintf(&sb, "+ enum %s %q\n", e.Type, e.Values)
+	}
+	for _, e := range d.ChangedEnums {
+		fmt.Fprintf(&sb, "~ enum %s %q -> %q\n", e.New.Type, e.Old.Values, e.New.Values)
+	}
+	for _, t := range d.AddedTables {
+		fmt.Fprintf(&sb, "+ table %s\n", t.Key)
+	}
+	for _, t := range d.ChangedTables {
+		sb.WriteString(t.String())
+	}
+	for _, t := range d.DroppedTables {
+		fmt.Fprintf(&sb, "- table %s\n", t.Key)
+	}
+	for _, e := range d.DroppedEnums {
+		fmt.Fprintf(&sb, "- enum %s\n", e.Type)
+	}
+
+	return sb.String()
+}
+
+// IsEmpty returns true if there are no changes to the table
+func (t TableDiff[C, I]) IsEmpty() bool {
+	return len(t.AddedColumns) == 0 && len(t.DroppedColumns) == 0 &&
+		len(t.ChangedColumns) == 0 && t.AddedPrimaryKey == nil &&
+		t.DroppedPrimaryKey == nil && len(t.AddedUniques) == 0 &&
+		len(t.DroppedUniques) == 0 && len(t.AddedForeignKeys) == 0 &&
+		len(t.DroppedForeignKeys) == 0 && len(t.AddedChecks) == 0 &&
+		len(t.DroppedChecks) == 0 && len(t.AddedIndexes) == 0 &&
+		len(t.DroppedIndexes) == 0
+}
+
+// String returns a summary of the changes to the table, one per line
+func (t TableDiff[C, I]) String() string {
+	var sb strings.Builder
+
+	for _, c := range t.AddedColumns {
+		fmt.Fprintf(&sb, "+ column %s.%s %s\n", t.Key, c.Name, c.DBType)
+	}
+	for _, c := range t.ChangedColumns {
+		fmt.Fprintf(&sb, "~ column %s.%s %s\n", t.Key, c.New.Name, strings.Join(c.Changes(), ", "))
+	}
+	for _, c := range t.DroppedColumns {
+		fmt.Fprintf(&sb, "- column %s.%s\n", t.Key, c.Name)
+	}
+
+	if t.DroppedPrimaryKey != nil {
+		fmt.Fprintf(&sb, "- primary key %s.%s\n", t.Key, t.DroppedPrimaryKey.Name)
+	}
+	if t.AddedPrimaryKey != nil {
+		fmt.Fprintf(&sb, "+ primary key %s.%s %q\n", t.Key, t.AddedPrimaryKey.Name, t.AddedPrimaryKey.Columns)
+	}
+	for _, u := range t.DroppedUniques {
+		fmt.Fprintf(&sb, "- unique %s.%s\n", t.Key, u.Name)
+	}
+	for _, u := range t.AddedUniques {
+		fmt.Fprintf(&sb, "+ unique %s.%s %q\n", t.Key, u.Name, u.Columns)
+	}
+	for _, fk := range t.DroppedForeignKeys {
+		fmt.Fprintf(&sb, "- foreign key %s.%s\n", t.Key, fk.Name)
+	}
+	for _, fk := range t.AddedForeignKeys {
+		fmt.Fprintf(&sb, "+ foreign key %s.%s %q -> %s %q\n", t.Key, fk.Name, fk.Columns, fk.ForeignTable, fk.ForeignColumns)
+	}
+	for _, c := range t.DroppedChecks {
+		fmt.Fprintf(&sb, "- check %s.%s\n", t.Key, c.Name)
+	}
+	for _, c := range t.AddedChecks {
+		fmt.Fprintf(&sb, "+ check %s.%s %s\n", t.Key, c.Name, c.Expression)
+	}
+	for _, i := range t.DroppedIndexes {
+		fmt.Fprintf(&sb, "- index %s.%s\n", t.Key, i.Name)
+	}
+	for _, i := range t.AddedIndexes {
+		fmt.Fprintf(&sb, "+ index %s.%s\n", t.Key, i.Name)
+	}
+
+	return sb.String()
+}
+
+// Changes describes what was changed in the column definition
+func (c ColumnDiff) Changes() []string {
+	var changes []string
+
+	if c.Old.DBType != c.New.DBType || !slices.Equal(c.Old.TypeLimits, c.New.TypeLimits) {
+		changes = append(changes, fmt.Sprintf("type %s -> %s", c.Old.fullDBType(), c.New.fullDBType()))
+	}
+	if c.Old.Nullable != c.New.Nullable {
+		changes = append(changes, fmt.Sprintf("nullable %t -> %t", c.Old.Nullable, c.New.Nullable))
+	}
+	if c.Old.Default != c.New.Default {
+		changes = append(changes, fmt.Sprintf("default %q -> %q", c.Old.Default, c.New.Default))
+	}
+	if c.Old.Generated != c.New.Generated {
+		changes = append(changes, fmt.Sprintf("generated %t -> %t", c.Old.Generated, c.New.Generated))
+	}
+	if c.Old.AutoIncr != c.New.AutoIncr {
+		changes = append(changes, fmt.Sprintf("autoincr %t -> %t", c.Old.AutoIncr, c.New.AutoIncr))
+	}
+
+	return changes
+}
+
+func (c Column) fullDBType() string {
+	if len(c.TypeLimits) == 0 {
+		return c.DBType
+	}
+
+	return fmt.Sprintf("%s(%s)", c.DBType, strings.Join(c.TypeLimits, ","))
+}
+
+func findTable[C, I any](tables []Table[C, I], key string) (Table[C, I], bool) {
+	i := slices.IndexFunc(tables, func(t Table[C, I]) bool { return t.Key == key })
+	if i == -1 {
+		return Table[C, I]{}, false
+	}
+
+	return tables[i], true
+}
+
+func diffTable[C, I any](from, to Table[C, I]) TableDiff[C, I] {
+	diff := TableDiff[C, I]{Key: to.Key, Old: from, New: to}
+
+	for _, col := range to.Columns {
+		i := slices.IndexFunc(from.Columns, func(c Column) bool { return c.Name == col.Name })
+		switch {
+		case i == -1:
+			diff.AddedColumns = append(diff.AddedColumns, col)
+		case len(ColumnDiff{Old: from.Columns[i], New: col}.Changes()) > 0:
+			diff.ChangedColumns = append(diff.ChangedColumns, ColumnDiff{Old: from.Columns[i], New: col})
+		}
+	}
+
+	for _, col := range from.Columns {
+		if !slices.ContainsFunc(to.Columns, func(c Column) bool { return c.Name == col.Name }) {
+			diff.DroppedColumns = append(diff.DroppedColumns, col)
+		}
+	}
+
+	if !sameDefinition(from.Constraints.Primary, to.Constraints.Primary) {
+		diff.DroppedPrimaryKey = from.Constraints.Primary
+		diff.AddedPrimaryKey = to.Constraints.Primary
+	}
+
+	diff.AddedUniques, diff.DroppedUniques = diffByName(
+		from.Constraints.Uniques, to.Constraints.Uniques,
+		func(c Constraint[C]) string { return c.Name },
+	)
+	diff.AddedForeignKeys, diff.DroppedForeignKeys = diffByName(
+		from.Constraints.Foreign, to.Constraints.Foreign,
+		func(c ForeignKey[C]) string { return c.Name },
+	)
+	diff.AddedChecks, diff.DroppedChecks = diffByName(
+		from.Constraints.Checks, to.Constraints.Checks,
+		func(c Check[C]) string { return c.Name },
+	)
+	diff.AddedIndexes, diff.DroppedIndexes = diffByName(
+		from.Indexes, to.Indexes,
+		func(i Index[I]) string { return i.Name },
+	)
+
+	return diff
+}
+
+// diffByName matches the items by name and returns the added and dropped ones.
+// Items with the same name but a different definition are in both
+func diffByName[T any](from, to []T, name func(T) string) (added, dropped []T) {
+	for _, item := range to {
+		i := slices.IndexFunc(from, func(f T) bool { return name(f) == name(item) })
+		if i == -1 || !sameDefinition(&from[i], &item) {
+			added = append(added, item)
+		}
+	}
+
+	for _, item := range from {
+		i := slices.IndexFunc(to, func(t T) bool { return name(t) == name(item) })
+		if i == -1 || !sameDefinition(&to[i], &item) {
+			dropped = append(dropped, item)
+		}
+	}
+
+	return added, dropped
+}
+
+// sameDefinition compares two constraints or indexes ignoring their comments.
+// They are compared by their JSON encoding since the extra information
+// of a DBInfo loaded from a snapshot is not always of the same Go type
+func sameDefinition[T any](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return definitionJSON(*a) == definitionJSON(*b)
+}
+
+func definitionJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return string(b)
+	}
+	delete(m, "comment")
+
+	b, _ = json.Marshal(m)
+	return string(b)
+}
@@ -0,0 +1,141 @@
+package drivers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aarondl/opt/null"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestDiffDBInfo(t *testing.T) {
+	t.Parallel()
+
+	users := Table[any, any]{
+		Key:  "users",
+		Name: "users",
+		Columns: []Column{
+			{Name: "id", DBType: "integer"},
+			{Name: "email", DBType: "text"},
+			{Name: "name", DBType: "text", Nullable: true},
+		},
+		Constraints: Constraints[any]{
+			Primary: &Constraint[any]{Name: "users_pkey", Columns: []string{"id"}},
+			Uniques: []Constraint[any]{{Name: "users_email_key", Columns: []string{"email"}}},
+		},
+		Indexes: []Index[any]{
+			{Name: "users_name_idx", Columns: []IndexColumn{{Name: "name"}}},
+		},
+	}
+
+	posts := Table[any, any]{Key: "posts", Name: "posts", Columns: []Column{{Name: "id", DBType: "integer"}}}
+	tags := Table[any, any]{Key: "tags", Name: "tags", Columns: []Column{{Name: "id", DBType: "integer"}}}
+
+	newUsers := users
+	newUsers.Comment = "comments are ignored"
+	newUsers.Columns = []Column{
+		{Name: "id", DBType: "integer"},
+		{Name: "email", DBType: "character varying", TypeLimits: []string{"255"}},
+		{Name: "age", DBType: "integer", Nullable: true},
+	}
+	newUsers.Constraints.Uniques = []Constraint[any]{
+		{Name: "users_email_key", Columns: []string{"email", "id"}},
+	}
+	newUsers.Indexes = []Index[any]{
+		{Name: "users_name_idx", Columns: []IndexColumn{{Name: "name"}}, Comment: "only the comment changed"},
+		{Name: "users_age_idx", Columns: []IndexColumn{{Name: "age"}}},
+	}
+
+	from := &DBInfo[any, any, any]{
+		Tables: Tables[any, any]{users, posts},
+		Enums: []Enum{
+			{Type: "Status", Values: []string{"active"}},
+			{Type: "Old", Values: []string{"a"}},
+		},
+	}
+	to := &DBInfo[any, any, any]{
+		Tables: Tables[any, any]{newUsers, tags},
+		Enums: []Enum{
+			{Type: "Status", Values: []string{"active", "inactive"}},
+		},
+	}
+
+	got := DiffDBInfo(from, to)
+
+	want := DBInfoDiff[any, any]{
+		AddedTables:   []Table[any, any]{tags},
+		DroppedTables: []Table[any, any]{posts},
+		ChangedTables: []TableDiff[any, any]{{
+			Key:          "users",
+			Old:          users,
+			New:          newUsers,
+			AddedColumns: []Column{{Name: "age", DBType: "integer", Nullable: true}},
+			DroppedColumns: []Column{
+				{Name: "name", DBType: "text", Nullable: true},
+			},
+			ChangedColumns: []ColumnDiff{{Old: users.Columns[1], New: newUsers.Columns[1]}},
+			AddedUniques:   newUsers.Constraints.Uniques,
+			DroppedUniques: users.Constraints.Uniques,
+			AddedIndexes:   []Index[any]{newUsers.Indexes[1]},
+		}},
+		ChangedEnums: []EnumDiff{{
+			Old: Enum{Type: "Status", Values: []string{"active"}},
+			New: Enum{Type: "Status", Values: []string{"active", "inactive"}},
+		}},
+		DroppedEnums: []Enum{{Type: "Old", Values: []string{"a"}}},
+	}
+
+	if diff := cmp.Diff(want, got, cmpopts.EquateComparable(null.Val[bool]{})); diff != "" {
+		t.Fatal(diff)
+	}
+
+	wantChanges := []string{`type text -> character varying(255)`}
+	if diff := cmp.Diff(wantChanges, got.ChangedTables[0].ChangedColumns[0].Changes()); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if !DiffDBInfo(to, to).IsEmpty() {
+		t.Fatal("expected no changes when comparing a DBInfo with itself")
+	}
+}
+
+func TestDiffDBInfoSnapshot(t *testing.T) {
+	t.Parallel()
+
+	type indexExtra struct {
+		Where string `json:"where"`
+	}
+
+	info := &DBInfo[any, any, indexExtra]{
+		Tables: Tables[any, indexExtra]{{
+			Key:  "users",
+			Name: "users",
+			Indexes: []Index[indexExtra]{
+				{Name: "users_idx", Extra: indexExtra{Where: "deleted_at IS NULL"}},
+			},
+			Constraints: Constraints[any]{
+				Foreign: []ForeignKey[any]{{
+					Constraint:     Constraint[any]{Name: "users_team_fkey", Columns: []string{"team_id"}},
+					ForeignTable:   "teams",
+					ForeignColumns: []string{"id"},
+				}},
+			},
+		}},
+	}
+
+	// A DBInfo loaded from a snapshot should match the one it was saved from
+	b, err := json.Marshal(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := &DBInfo[any, any, indexExtra]{}
+	if err := json.Unmarshal(b, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := DiffDBInfo(snapshot, info); !diff.IsEmpty() {
+		t.Fatalf("expected no changes, got:\n%s", diff)
+	}
+}
@@ -19,6 +19,9 @@ type Table[ConstraintExtra, IndexExtra any] struct {
 	Indexes     []Index[IndexExtra]          `yaml:"indexes" json:"indexes"`
 	Constraints Constraints[ConstraintExtra] `yaml:"constraints" json:"constraints"`
 	Comment     string                       `json:"comment" yaml:"comment"`
+	// For virtual tables, the module that implements the table.
+	// Example value: "fts5"
+	Module string `yaml:"module,omitempty" json:"module,omitempty"`
 }
 
 func (t Table[C, I]) DBTag(c Column) string {
@@ -97,6 +100,39 @@ func (t Table[C, I]) HasExactUnique(cols ...string) bool {
 	return false
 }
 
+// UniqueKeys returns the primary key, the unique constraints and the unique
+// indexes of the table that can be used as a conflict target.
+// Partial indexes and indexes on expressions are skipped, and keys on the
+// same columns are only returned once
+func (t Table[C, I]) UniqueKeys() []Constraint[C] {
+	keys := make([]Constraint[C], 0, len(t.Constraints.Uniques)+1)
+	add := func(key Constraint[C]) {
+		for _, k := range keys {
+			if internal.SliceMatch(k.Columns, key.Columns) {
+				return
+			}
+		}
+		keys = append(keys, key)
+	}
+
+	if t.Constraints.Primary != nil {
+		add(*t.Constraints.Primary)
+	}
+
+	for _, u := range t.Constraints.Uniques {
+		add(u)
+	}
+
+	for _, idx := range t.Indexes {
+		if !idx.Unique || idx.IsPartial() || idx.HasExpressionColumn() {
+			continue
+		}
+		add(Constraint[C]{Name: idx.Name, Columns: idx.NonExpressionColumns(), Comment: idx.Comment})
+	}
+
+	return keys
+}
+
 func (t Table[C, I]) RelIsRequired(rel orm.Relationship) bool {
 	// The relationship is not required, if its not using foreign keys
 	if rel.NeverRequired {
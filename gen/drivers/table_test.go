@@ -3,6 +3,8 @@ package drivers
 import (
 	"context"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 // mockConstructor satisfies Constructor[any, any] for testing BuildDBInfo.
@@ -185,3 +187,42 @@ func TestCanSoftDelete(t *testing.T) {
 		}
 	}
 }
+
+func TestUniqueKeys(t *testing.T) {
+	t.Parallel()
+
+	type indexExtra struct{ Where string }
+
+	table := Table[any, indexExtra]{
+		Constraints: Constraints[any]{
+			Primary: &Constraint[any]{Name: "pk", Columns: []string{"id"}},
+			Uniques: []Constraint[any]{
+				{Name: "email", Columns: []string{"email"}},
+				{Name: "id", Columns: []string{"id"}},
+				{Name: "name_org", Columns: []string{"name", "org_id"}},
+				{Name: "org_name", Columns: []string{"org_id", "name"}},
+			},
+		},
+		Indexes: []Index[indexExtra]{
+			{Name: "pk_idx", Unique: true, Columns: []IndexColumn{{Name: "id"}}},
+			{Name: "slug_idx", Unique: true, Columns: []IndexColumn{{Name: "slug"}}},
+			{Name: "org_idx", Columns: []IndexColumn{{Name: "org_id"}}},
+			{Name: "lower_email_idx", Unique: true, Columns: []IndexColumn{{Name: "lower(email)", IsExpression: true}}},
+			{Name: "active_idx", Unique: true, Columns: []IndexColumn{{Name: "code"}}, Extra: indexExtra{Where: "(active)"}},
+		},
+	}
+
+	want := []Constraint[any]{
+		{Name: "pk", Columns: []string{"id"}},
+		{Name: "email", Columns: []string{"email"}},
+		{Name: "name_org", Columns: []string{"name", "org_id"}},
+		{Name: "slug_idx", Columns: []string{"slug"}},
+	}
+	if diff := cmp.Diff(want, table.UniqueKeys()); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if keys := (Table[any, any]{}).UniqueKeys(); len(keys) != 0 {
+		t.Fatalf("expected no keys, got %v", keys)
+	}
+}
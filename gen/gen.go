@@ -145,6 +145,9 @@ func Run[T, C, I any](ctx context.Context, s *State[C], driver drivers.Interface
 		RelationTagCount:   s.Config.RelationTagCount,
 		RelationLoadedName: relationLoadedName,
 		EnumFormat:         s.Config.EnumFormat,
+		VersionColumns:     processVersionColumns(s.Config.VersionColumn, dbInfo.Tables),
+		SoftDeleteColumns:  processSoftDeleteColumns(s.Config.SoftDeleteColumn, dbInfo.Tables),
+		TenantColumns:      processTenantColumns(s.Config.TenantColumn, dbInfo.Tables),
 		OutputPackages:     pkgMap,
 		Driver:             dbInfo.Driver,
 	}
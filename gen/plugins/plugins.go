@@ -19,6 +19,7 @@ func Setup[T, C, I any](config Config, templates gen.Templates) []gen.Plugin {
 		Loaders[C](config.Loaders, templates.Loaders),
 		Joins[C](config.Joins, templates.Joins),
 		Counts[C](config.Counts, templates.Counts),
+		Timestamps[T, C, I](config.Timestamps, templates.Timestamps),
 		Queries[T, C, I](templates.Queries),
 	}
 }
@@ -33,6 +34,8 @@ type Config struct {
 	Loaders  OnOffConfig  `yaml:"loaders"`
 	Joins    OnOffConfig  `yaml:"joins"`
 	Counts   OnOffConfig  `yaml:"counts"`
+
+	Timestamps TimestampsConfig `yaml:"timestamps"`
 }
 
 func (c Config) Merge(c2 Config) Config {
@@ -46,6 +49,8 @@ func (c Config) Merge(c2 Config) Config {
 		Loaders:  mergeOnOffConfig(c.Loaders, c2.Loaders),
 		Joins:    mergeOnOffConfig(c.Joins, c2.Joins),
 		Counts:   mergeOnOffConfig(c.Counts, c2.Counts),
+
+		Timestamps: mergeTimestampsConfig(c.Timestamps, c2.Timestamps),
 	}
 }
 
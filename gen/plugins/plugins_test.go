@@ -14,4 +14,7 @@ var (
 
 	_ gen.StatePlugin[any]                  = &queriesOutputPlugin[any, any, any]{}
 	_ gen.TemplateDataPlugin[any, any, any] = &queriesOutputPlugin[any, any, any]{}
+
+	_ gen.StatePlugin[any]                  = timestampsPlugin[any, any, any]{}
+	_ gen.TemplateDataPlugin[any, any, any] = timestampsPlugin[any, any, any]{}
 )
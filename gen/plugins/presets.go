@@ -39,4 +39,6 @@ var PresetNone = Config{
 	Loaders:  OnOffConfig{Disabled: internal.Pointer(true)},
 	Joins:    OnOffConfig{Disabled: internal.Pointer(true)},
 	Counts:   OnOffConfig{Disabled: internal.Pointer(true)},
+
+	Timestamps: TimestampsConfig{Disabled: internal.Pointer(true)},
 }
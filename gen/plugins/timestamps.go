@@ -0,0 +1,116 @@
+package plugins
+
+import (
+	"cmp"
+	"io/fs"
+
+	"github.com/stephenafamo/bob/gen"
+	"github.com/stephenafamo/bob/gen/drivers"
+	"github.com/stephenafamo/bob/internal"
+)
+
+type TimestampsConfig struct {
+	Disabled *bool `yaml:"disabled"`
+	// Column filled with the current time on insert when it is not set
+	CreatedAt gen.ColumnSelector `yaml:"created_at"`
+	// Column filled with the current time on insert and update when it is not set
+	UpdatedAt gen.ColumnSelector `yaml:"updated_at"`
+}
+
+func mergeTimestampsConfig(c1, c2 TimestampsConfig) TimestampsConfig {
+	merged := TimestampsConfig{
+		Disabled:  cmp.Or(c2.Disabled, c1.Disabled),
+		CreatedAt: c1.CreatedAt,
+		UpdatedAt: c1.UpdatedAt,
+	}
+
+	if !c2.CreatedAt.IsEmpty() {
+		merged.CreatedAt = c2.CreatedAt
+	}
+
+	if !c2.UpdatedAt.IsEmpty() {
+		merged.UpdatedAt = c2.UpdatedAt
+	}
+
+	return merged
+}
+
+// Timestamps fills the configured created_at and updated_at columns of each
+// table with the current time when they are not set in the setter.
+// The time is gotten with orm.Now, so the clock can be replaced through the context
+func Timestamps[T, C, I any](config TimestampsConfig, templates ...fs.FS) gen.Plugin {
+	return timestampsPlugin[T, C, I]{
+		config:    config,
+		templates: templates,
+	}
+}
+
+type timestampsPlugin[T, C, I any] struct {
+	config    TimestampsConfig
+	templates []fs.FS
+}
+
+// Name implements gen.StatePlugin.
+func (timestampsPlugin[T, C, I]) Name() string {
+	return "Timestamps Plugin"
+}
+
+func (t timestampsPlugin[T, C, I]) disabled() bool {
+	return internal.ValOrZero(t.config.Disabled) ||
+		(t.config.CreatedAt.IsEmpty() && t.config.UpdatedAt.IsEmpty())
+}
+
+// PlugState implements gen.StatePlugin.
+func (t timestampsPlugin[T, C, I]) PlugState(state *gen.State[C]) error {
+	if t.disabled() {
+		return nil
+	}
+
+	if err := dependsOn(t.config.Disabled, state, "models"); err != nil {
+		return err
+	}
+
+	for _, output := range state.Outputs {
+		if output.Key == "models" {
+			output.Templates = append(output.Templates, gen.BaseTemplates.Timestamps)
+			output.Templates = append(output.Templates, t.templates...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// PlugTemplateData implements gen.TemplateDataPlugin.
+func (t timestampsPlugin[T, C, I]) PlugTemplateData(data *gen.TemplateData[T, C, I]) error {
+	if t.disabled() {
+		return nil
+	}
+
+	createdAt := gen.FindColumns("created_at", t.config.CreatedAt, data.Tables, checkTimestampColumn[C, I])
+	updatedAt := gen.FindColumns("updated_at", t.config.UpdatedAt, data.Tables, checkTimestampColumn[C, I])
+
+	data.TimestampColumns = make(map[string]gen.TimestampColumns)
+	for _, table := range data.Tables {
+		cols := gen.TimestampColumns{
+			CreatedAt: createdAt[table.Key],
+			UpdatedAt: updatedAt[table.Key],
+		}
+
+		if cols != (gen.TimestampColumns{}) {
+			data.TimestampColumns[table.Key] = cols
+		}
+	}
+
+	return nil
+}
+
+// checkTimestampColumn is the check of [gen.FindColumns] for timestamp columns,
+// which must be a time.Time and must not be generated
+func checkTimestampColumn[C, I any](_ drivers.Table[C, I], c drivers.Column) string {
+	if c.Generated || c.Type != "time.Time" {
+		return "it must be a time.Time and must not be generated"
+	}
+
+	return ""
+}
@@ -0,0 +1,102 @@
+package plugins
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stephenafamo/bob/gen"
+	"github.com/stephenafamo/bob/gen/drivers"
+	"github.com/stephenafamo/bob/internal"
+)
+
+func TestTimestampsPlugTemplateData(t *testing.T) {
+	pk := &drivers.Constraint[any]{Columns: []string{"id"}}
+	tables := drivers.Tables[any, any]{
+		{
+			Key:         "users",
+			Constraints: drivers.Constraints[any]{Primary: pk},
+			Columns: []drivers.Column{
+				{Name: "id", Type: "int64"},
+				{Name: "created_at", Type: "time.Time"},
+				{Name: "updated_at", Type: "time.Time", Nullable: true},
+			},
+		},
+		{
+			Key:         "posts",
+			Constraints: drivers.Constraints[any]{Primary: pk},
+			Columns: []drivers.Column{
+				{Name: "id", Type: "int64"},
+				{Name: "created_at", Type: "int64"},
+				{Name: "modified_at", Type: "time.Time"},
+			},
+		},
+		{
+			Key:         "tags",
+			Constraints: drivers.Constraints[any]{Primary: pk},
+			Columns: []drivers.Column{
+				{Name: "id", Type: "int64"},
+				{Name: "created_at", Type: "time.Time", Generated: true},
+			},
+		},
+		{
+			Key: "users_view",
+			Columns: []drivers.Column{
+				{Name: "created_at", Type: "time.Time"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		config TimestampsConfig
+		want   map[string]gen.TimestampColumns
+	}{
+		{
+			name:   "no columns",
+			config: TimestampsConfig{},
+			want:   nil,
+		},
+		{
+			name: "by name",
+			config: TimestampsConfig{
+				CreatedAt: gen.ColumnSelector{Name: "created_at"},
+				UpdatedAt: gen.ColumnSelector{Name: "/^(updated|modified)_at$/"},
+			},
+			want: map[string]gen.TimestampColumns{
+				"users": {CreatedAt: "created_at", UpdatedAt: "updated_at"},
+				"posts": {UpdatedAt: "modified_at"},
+			},
+		},
+		{
+			name: "limited to tables",
+			config: TimestampsConfig{
+				CreatedAt: gen.ColumnSelector{Name: "created_at", Tables: []string{"users"}},
+			},
+			want: map[string]gen.TimestampColumns{
+				"users": {CreatedAt: "created_at"},
+			},
+		},
+		{
+			name: "disabled",
+			config: TimestampsConfig{
+				Disabled:  internal.Pointer(true),
+				CreatedAt: gen.ColumnSelector{Name: "created_at"},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &gen.TemplateData[any, any, any]{Tables: tables}
+			plugin := Timestamps[any, any, any](tt.config).(gen.TemplateDataPlugin[any, any, any])
+			if err := plugin.PlugTemplateData(data); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tt.want, data.TimestampColumns); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
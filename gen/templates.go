@@ -50,32 +50,35 @@ func buildTemplatesFromKnownDirStructure(templates fs.FS, dir string) Templates
 	LoadersTemplates, _ := fs.Sub(templates, "templates/loaders")
 	JoinsTemplates, _ := fs.Sub(templates, "templates/joins")
 	CountsTemplates, _ := fs.Sub(templates, "templates/counts")
+	TimestampsTemplates, _ := fs.Sub(templates, "templates/timestamps")
 
 	return Templates{
-		DBInfo:   DBInfoTemplates,
-		Enums:    EnumTemplates,
-		Models:   ModelTemplates,
-		Factory:  FactoryTemplates,
-		Queries:  QueriesTemplates,
-		DBErrors: DBErrorTemplates,
-		Where:    WhereTemplates,
-		Loaders:  LoadersTemplates,
-		Joins:    JoinsTemplates,
-		Counts:   CountsTemplates,
+		DBInfo:     DBInfoTemplates,
+		Enums:      EnumTemplates,
+		Models:     ModelTemplates,
+		Factory:    FactoryTemplates,
+		Queries:    QueriesTemplates,
+		DBErrors:   DBErrorTemplates,
+		Where:      WhereTemplates,
+		Loaders:    LoadersTemplates,
+		Joins:      JoinsTemplates,
+		Counts:     CountsTemplates,
+		Timestamps: TimestampsTemplates,
 	}
 }
 
 type Templates struct {
-	Enums    fs.FS
-	Models   fs.FS
-	Factory  fs.FS
-	Queries  fs.FS
-	DBErrors fs.FS
-	Where    fs.FS
-	Loaders  fs.FS
-	Joins    fs.FS
-	Counts   fs.FS
-	DBInfo   fs.FS
+	Enums      fs.FS
+	Models     fs.FS
+	Factory    fs.FS
+	Queries    fs.FS
+	DBErrors   fs.FS
+	Where      fs.FS
+	Loaders    fs.FS
+	Joins      fs.FS
+	Counts     fs.FS
+	Timestamps fs.FS
+	DBInfo     fs.FS
 }
 
 type TemplateData[T, C, I any] struct {
@@ -115,6 +118,14 @@ type TemplateData[T, C, I any] struct {
 	TagIgnore map[string]struct{}
 	// Format for enum value identifiers: "title_case" or "screaming_snake_case"
 	EnumFormat string
+	// Maps table keys to the column used for optimistic locking
+	VersionColumns map[string]string
+	// Maps table keys to the column used for soft deletes
+	SoftDeleteColumns map[string]string
+	// Maps table keys to the column that holds the tenant of each row
+	TenantColumns map[string]string
+	// Maps table keys to the columns filled by the timestamps plugin
+	TimestampColumns map[string]TimestampColumns
 
 	// Supplied by the driver
 	ExtraInfo T
@@ -128,6 +139,13 @@ type TemplateData[T, C, I any] struct {
 	Language language.Language
 }
 
+// TimestampColumns holds the columns of a table that are filled with
+// the current time when a row is inserted or updated
+type TimestampColumns struct {
+	CreatedAt string
+	UpdatedAt string
+}
+
 func loadTemplate(tpl *template.Template, customFuncs template.FuncMap, name, content string) error {
 	_, err := tpl.New(name).
 		Funcs(sprig.GenericFuncMap()).
@@ -172,3 +172,30 @@ func countPreloader[T countPreloadable](name string, countExpr func(from string)
 		return queryMod, mapperMod, nil
 	}
 }
+
+{{- if $.SoftDeleteColumns}}
+
+// notDeleted excludes the soft deleted rows of a table from a count query
+// unless the context was modified with orm.WithDeleted
+func notDeleted(alias, column string) bob.Mod[*dialect.SelectQuery] {
+	return bob.ModFunc[*dialect.SelectQuery](func(q *dialect.SelectQuery) {
+		q.AppendContextualModFunc(func(ctx context.Context, q *dialect.SelectQuery) (context.Context, error) {
+			if !orm.DeletedIncluded(ctx) {
+				q.AppendWhere({{$.Dialect}}.Quote(alias, column).IsNull())
+			}
+			return ctx, nil
+		})
+	})
+}
+{{- end}}
+
+{{- if $.TenantColumns}}
+
+// inTenant scopes the rows of a table in a count query
+// to the tenant in the context
+func inTenant(alias, column string) bob.Mod[*dialect.SelectQuery] {
+	return bob.ModFunc[*dialect.SelectQuery](func(q *dialect.SelectQuery) {
+		q.AppendContextualMod(orm.TenantMod[*dialect.SelectQuery](alias, column))
+	})
+}
+{{- end}}
@@ -94,6 +94,14 @@ func build{{$tAlias.UpSingular}}CountPreloader() {{$tAlias.DownSingular}}CountPr
 					),
 					{{- end}}
 					{{- end}}
+					{{- range $side := $rel.Sides}}
+					{{- with index $.SoftDeleteColumns $side.To}}
+					notDeleted({{($.Aliases.Table $side.To).UpPlural}}.Alias(), {{quote .}}),
+					{{- end}}
+					{{- with index $.TenantColumns $side.To}}
+					inTenant({{($.Aliases.Table $side.To).UpPlural}}.Alias(), {{quote .}}),
+					{{- end}}
+					{{- end}}
 				}
 				subqueryMods = append(subqueryMods, mods...)
 				return {{$.Dialect}}.Group({{$.Dialect}}.Select(subqueryMods...).Expression)
@@ -296,6 +304,14 @@ func (os {{$tAlias.UpSingular}}Slice) LoadCount{{$relAlias}}(ctx context.Context
 		),
 		{{end -}}
 		{{- end}}
+		{{range $side := $rel.Sides -}}
+		{{with index $.SoftDeleteColumns $side.To -}}
+		notDeleted({{($.Aliases.Table $side.To).UpPlural}}.Alias(), {{quote .}}),
+		{{end -}}
+		{{with index $.TenantColumns $side.To -}}
+		inTenant({{($.Aliases.Table $side.To).UpPlural}}.Alias(), {{quote .}}),
+		{{end -}}
+		{{end -}}
 		// WHERE fk IN (parent PKs) — psql single-column FK uses `= ANY(array)` (see PKArgExpr above)
 		{{if eq (len $firstSide.FromColumns) 1 -}}
 		{{$local := index $firstSide.FromColumns 0 -}}
@@ -45,3 +45,26 @@ func (e *CheckConstraintError) Is(target error) bool {
   return false
 }
 {{end}}
+
+{{$.Importer.Import "errors"}}
+// ErrRetryable captures errors that may not happen again if the transaction is retried,
+// such as serialization failures and deadlocks.
+var ErrRetryable = &RetryableError{}
+
+type RetryableError struct{}
+
+func (e *RetryableError) Error() string {
+  return "retryable error"
+}
+
+{{block "retryable_error_detection_method" . -}}
+func (e *RetryableError) Is(target error) bool {
+  return false
+}
+{{end}}
+
+// IsRetryable reports whether running the transaction again may succeed.
+// It can be used as the ShouldRetry function of bob.RetryOptions.
+func IsRetryable(err error) bool {
+  return errors.Is(ErrRetryable, err)
+}
@@ -22,6 +22,11 @@ func TestCreate{{$tAlias.UpSingular}}(t *testing.T) {
 
   ctx, cancel := context.WithCancel(t.Context())
   t.Cleanup(cancel)
+  {{- if index $.TenantColumns $table.Key}}
+  {{$.Importer.Import "github.com/stephenafamo/bob/orm"}}
+  // the factory sets the tenant of the rows
+  ctx = orm.WithAllTenants(ctx)
+  {{- end}}
 
   tx, err := testDB.Begin(ctx)
   if err != nil {
@@ -52,6 +57,11 @@ func TestCreate{{$tAlias.UpSingular}}With{{$relAlias}}DoesNotDuplicateParent(t *
 
   ctx, cancel := context.WithCancel(t.Context())
   t.Cleanup(cancel)
+  {{- if index $.TenantColumns $table.Key}}
+  {{$.Importer.Import "github.com/stephenafamo/bob/orm"}}
+  // the factory sets the tenant of the rows
+  ctx = orm.WithAllTenants(ctx)
+  {{- end}}
 
   tx, err := testDB.Begin(ctx)
   if err != nil {
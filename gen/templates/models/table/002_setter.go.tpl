@@ -35,6 +35,7 @@ func (s {{$tAlias.UpSingular}}Setter) SetColumns() []string {
 func (s {{$tAlias.UpSingular}}Setter) Overwrite(t *{{$tAlias.UpSingular}}) {
 	{{- range $column := $table.Columns -}}
     {{if $column.Generated}}{{continue}}{{end -}}
+    {{if eq $column.Name (index $.TenantColumns $table.Key)}}{{continue}}{{end -}}
     {{$colAlias := $tAlias.Column $column.Name -}}
     {{$colTyp := $.Types.GetOptional $.CurrentPackage $.Importer $column.Type $column.Nullable -}}
 		if {{$.Types.IsOptionalValid $.CurrentPackage $column.Type $column.Nullable (cat "s." $colAlias)}} {
@@ -58,10 +59,23 @@ func (s *{{$tAlias.UpSingular}}Setter) Apply(q *dialect.InsertQuery) {
 	q.AppendValues(
   {{range $index, $column := $table.NonGeneratedColumns -}}
     bob.ExpressionFunc(func(ctx context.Context, w io.StringWriter, d bob.Dialect, start int) ([]any, error){
+        {{- block "setter/insert/tenant" (dict "Data" $ "Table" $table "Column" $column)}}
+        {{- if eq .Column.Name (index .Data.TenantColumns .Table.Key)}}
+        {{.Data.Importer.Import "github.com/stephenafamo/bob/orm"}}
+        // rows are always inserted with the tenant in the context
+        if tenant, ok, err := orm.Tenant(ctx, {{quote .Table.Key}}); err != nil {
+          return nil, err
+        } else if ok {
+          return {{.Data.Dialect}}.Arg(tenant).WriteSQL(ctx, w, d, start)
+        }
+        {{end -}}
+        {{end}}
         {{$colAlias := $tAlias.Column $column.Name -}}
         {{$colGetter := $.Types.FromOptional $.CurrentPackage $.Importer $column.Type (cat "s." $colAlias) $column.Nullable $column.Nullable -}}
         if {{$.Types.IsOptionalInvalid $.CurrentPackage $column.Type $column.Nullable (cat "s." $colAlias)}} {
-          return {{$.Dialect}}.Raw("DEFAULT").WriteSQL(ctx, w, d, start)
+          {{- block "setter/insert/unset" (dict "Data" $ "Table" $table "Column" $column "Default" (printf "%s.Raw(\"DEFAULT\")" $.Dialect))}}
+          return {{.Default}}.WriteSQL(ctx, w, d, start)
+          {{- end}}
         }
         return {{$.Dialect}}.Arg({{$colGetter}}).WriteSQL(ctx, w, d, start)
     }),
@@ -86,15 +100,30 @@ func (s {{$tAlias.UpSingular}}Setter) Expressions(prefix ...string) []bob.Expres
 
   {{$.Importer.Import "github.com/stephenafamo/bob/expr" }}
 	{{$.Importer.Import (printf "github.com/stephenafamo/bob/dialect/%s/um" $.Dialect)}}
+	{{$versionCol := index $.VersionColumns $table.Key -}}
+	{{$tenantCol := index $.TenantColumns $table.Key -}}
 	{{range $column := $table.Columns -}}
 	{{if $column.Generated}}{{continue}}{{end -}}
 	{{$colAlias := $tAlias.Column $column.Name -}}
+	{{if eq $column.Name $tenantCol -}}
+    // {{$column.Name}} is the tenant of the row and is never updated
+    {{continue}}
+	{{end -}}
+	{{if eq $column.Name $versionCol -}}
+    // {{$column.Name}} is used for optimistic locking and is always incremented
+    exprs = append(exprs, expr.Join{Sep: " = ", Exprs: []bob.Expression{
+      {{$.Dialect}}.Quote(append(prefix, "{{$column.Name}}")...),
+      {{$.Dialect}}.Quote(append(prefix, "{{$column.Name}}")...).Plus({{$.Dialect}}.Raw("1")),
+    }})
+
+    {{continue}}
+	{{end -}}
     if {{$.Types.IsOptionalValid $.CurrentPackage $column.Type $column.Nullable (cat "s." $colAlias)}} {
       exprs = append(exprs, expr.Join{Sep: " = ", Exprs: []bob.Expression{
         {{$.Dialect}}.Quote(append(prefix, "{{$column.Name}}")...), 
         {{$.Dialect}}.Arg(s.{{$colAlias}}),
       }})
-		}
+		}{{block "setter/expressions/unset" (dict "Data" $ "Table" $table "Column" $column)}}{{end}}
 
 	{{end -}}
 
@@ -53,14 +53,33 @@ func (o *{{$tAlias.UpSingular}}) pkEQ() dialect.Expression {
     }))
 }
 
+{{with $versionCol := index $.VersionColumns $table.Key -}}
+// versionEQ matches the version of the {{$tAlias.UpSingular}} for optimistic locking
+func (o *{{$tAlias.UpSingular}}) versionEQ() dialect.Expression {
+  return {{$.Dialect}}.Quote("{{tableColumnAlias $table.Schema $table.Name $table.Key}}", "{{$versionCol}}").EQ({{$.Dialect}}.Arg(o.{{$tAlias.Column $versionCol}}))
+}
+{{- end}}
+
 
 {{block "one_update" . -}}
 {{$table := .Table}}
 {{$tAlias := .Aliases.Table $table.Key -}}
+{{$versionCol := index $.VersionColumns $table.Key -}}
 {{$.Importer.Import (printf "github.com/stephenafamo/bob/dialect/%s/um" $.Dialect)}}
 // Update uses an executor to update the {{$tAlias.UpSingular}}
+{{- if $versionCol}}
+// If the {{$tAlias.UpSingular}} was changed since it was loaded, an *orm.StaleObjectError is returned
+{{- end}}
 func (o *{{$tAlias.UpSingular}}) Update(ctx context.Context, exec bob.Executor, s *{{$tAlias.UpSingular}}Setter) error {
-	v, err := {{$tAlias.UpPlural}}.Update(s.UpdateMod(), um.Where(o.pkEQ())).One(ctx, exec)
+	v, err := {{$tAlias.UpPlural}}.Update(s.UpdateMod(), um.Where(o.pkEQ()){{if $versionCol}}, um.Where(o.versionEQ()){{end}}).One(ctx, exec)
+  {{if $versionCol -}}
+  {{$.Importer.Import "errors"}}
+  {{$.Importer.Import "database/sql"}}
+  {{$.Importer.Import "github.com/stephenafamo/bob/orm"}}
+  if errors.Is(err, sql.ErrNoRows) {
+    return &orm.StaleObjectError{Table: {{quote $table.Key}}, Expected: 1}
+  }
+  {{end -}}
   if err != nil {
     return err
   }
@@ -73,11 +92,42 @@ func (o *{{$tAlias.UpSingular}}) Update(ctx context.Context, exec bob.Executor,
 {{- end}}
 
 {{$.Importer.Import (printf "github.com/stephenafamo/bob/dialect/%s/dm" $.Dialect)}}
+{{$versionCol := index $.VersionColumns $table.Key -}}
+{{$softDeleteCol := index $.SoftDeleteColumns $table.Key -}}
 // Delete deletes a single {{$tAlias.UpSingular}} record with an executor
+{{- if $softDeleteCol}}
+// The record is soft deleted by setting {{$softDeleteCol}}, use HardDelete to remove it
+{{- end}}
+{{- if $versionCol}}
+// If the {{$tAlias.UpSingular}} was changed since it was loaded, an *orm.StaleObjectError is returned
+{{- end}}
 func (o *{{$tAlias.UpSingular}}) Delete(ctx context.Context, exec bob.Executor) error {
+  {{if $versionCol -}}
+  {{$.Importer.Import "github.com/stephenafamo/bob/orm"}}
+	rows, err := {{$tAlias.UpPlural}}.Delete(dm.Where(o.pkEQ()), dm.Where(o.versionEQ())).Exec(ctx, exec)
+  if err != nil {
+    return err
+  }
+
+  if rows == 0 {
+    return &orm.StaleObjectError{Table: {{quote $table.Key}}, Expected: 1}
+  }
+
+  return nil
+  {{- else -}}
 	_, err := {{$tAlias.UpPlural}}.Delete(dm.Where(o.pkEQ())).Exec(ctx, exec)
   return err
+  {{- end}}
+}
+
+{{- if $softDeleteCol}}
+
+// HardDelete permanently deletes a single {{$tAlias.UpSingular}} record with an executor
+func (o *{{$tAlias.UpSingular}}) HardDelete(ctx context.Context, exec bob.Executor) error {
+	_, err := {{$tAlias.UpPlural}}.HardDelete(dm.Where(o.pkEQ())).Exec(ctx, exec)
+  return err
 }
+{{- end}}
 
 // Reload refreshes the {{$tAlias.UpSingular}} using the executor
 func (o *{{$tAlias.UpSingular}}) Reload(ctx context.Context, exec bob.Executor) error {
@@ -0,0 +1,29 @@
+{{$table := .Table}}
+{{$tAlias := .Aliases.Table .Table.Key -}}
+
+{{if .Table.Constraints.Primary -}}
+// {{$tAlias.DownSingular}}Table is the type of {{$tAlias.UpPlural}}.
+// It adds an upsert method for each unique key of the table
+type {{$tAlias.DownSingular}}Table struct {
+	*{{$.Dialect}}.Table[*{{$tAlias.UpSingular}}, {{$tAlias.UpSingular}}Slice, *{{$tAlias.UpSingular}}Setter, {{$tAlias.DownSingular}}Columns]
+}
+
+{{$tenantCol := index $.TenantColumns $table.Key -}}
+{{range $key := $table.UniqueKeys -}}
+{{- /* MySQL cannot leave the conflicting rows of other tenants alone */ -}}
+{{- if and $tenantCol (eq $.Dialect "mysql") (not (has $tenantCol $key.Columns))}}{{continue}}{{end -}}
+{{- $fnName := printf "UpsertBy%s" (titleCase $key.Name) -}}
+// {{$fnName}} inserts the rows, updating the existing row instead
+// when an inserted row conflicts on {{$key.Name}} ({{join ", " $key.Columns}}).
+// Only the columns set in the setters are updated, so all setters must set the same columns
+{{- if $tenantCol}}
+// The {{$tenantCol}} of existing rows is never updated, and rows of other tenants are left unchanged
+{{- end}}
+func (t {{$tAlias.DownSingular}}Table) {{$fnName}}(ctx context.Context, exec bob.Executor, setters ...*{{$tAlias.UpSingular}}Setter) ({{$tAlias.UpSingular}}Slice, error) {
+	{{- block "upsert_all" (dict "Data" $ "Table" $table "Columns" $key.Columns)}}
+	return t.Upsert({{printf "%#v" .Columns}}, setters...).All(ctx, exec)
+	{{- end}}
+}
+
+{{end -}}
+{{- end}}
@@ -49,6 +49,24 @@ func (o {{$tAlias.UpSingular}}Slice) pkIN() dialect.Expression {
     }))
 }
 
+{{with $versionCol := index $.VersionColumns $table.Key -}}
+// pkVersionIN matches the primary keys and versions of the slice for optimistic locking
+func (o {{$tAlias.UpSingular}}Slice) pkVersionIN() dialect.Expression {
+  if len(o) == 0 {
+    return {{$.Dialect}}.Raw("NULL")
+  }
+
+  return {{$.Dialect}}.Group({{- range $col := $pkCols -}}{{$.Dialect}}.Quote("{{tableColumnAlias $table.Schema $table.Name $table.Key}}", "{{$col}}"), {{end}}{{$.Dialect}}.Quote("{{tableColumnAlias $table.Schema $table.Name $table.Key}}", "{{$versionCol}}")).
+    In(bob.ExpressionFunc(func(ctx context.Context, w io.StringWriter, d bob.Dialect, start int) ([]any, error){
+      pkPairs := make([]bob.Expression, len(o))
+      for i, row := range o {
+        pkPairs[i] = {{$.Dialect}}.ArgGroup({{range $col := $pkCols}}row.{{$tAlias.Column $col}}, {{end}}row.{{$tAlias.Column $versionCol}})
+      }
+      return bob.ExpressSlice(ctx, w, d, start, pkPairs, "", ", ", "")
+    }))
+}
+{{- end}}
+
 {{/* A single ==-comparable primary key column is matched in O(N+M) via a map;
      composite keys and custom compare_expr types fall back to the nested loop. */ -}}
 {{- $pkCol := index $pkCols 0 -}}
@@ -125,7 +143,7 @@ func (o {{$tAlias.UpSingular}}Slice) UpdateMod() bob.Mod[*dialect.UpdateQuery] {
       return err
     }))
 
-    q.AppendWhere(o.pkIN())
+    q.AppendWhere(o.{{if index $.VersionColumns $table.Key}}pkVersionIN{{else}}pkIN{{end}}())
   })
 }
 
@@ -154,7 +172,7 @@ func (o {{$tAlias.UpSingular}}Slice) DeleteMod() bob.Mod[*dialect.DeleteQuery] {
       return err
     }))
 
-    q.AppendWhere(o.pkIN())
+    q.AppendWhere(o.{{if index $.VersionColumns $table.Key}}pkVersionIN{{else}}pkIN{{end}}())
   })
 }
 
@@ -191,13 +209,28 @@ func (o {{$tAlias.UpSingular}}Slice) MergeMod() bob.Mod[*dialect.MergeQuery] {
 {{block "slice_update" . -}}
 {{$table := .Table}}
 {{$tAlias := .Aliases.Table $table.Key -}}
+{{$versionCol := index $.VersionColumns $table.Key -}}
 func (o {{$tAlias.UpSingular}}Slice) UpdateAll(ctx context.Context, exec bob.Executor, vals {{$tAlias.UpSingular}}Setter) error {
   if len(o) == 0 {
     return nil
   }
 
+  {{if $versionCol -}}
+  {{$.Importer.Import "github.com/stephenafamo/bob/orm"}}
+	v, err := {{$tAlias.UpPlural}}.Update(vals.UpdateMod(), o.UpdateMod()).All(ctx, exec)
+  if err != nil {
+    return err
+  }
+
+  if len(v) != len(o) {
+    return &orm.StaleObjectError{Table: {{quote $table.Key}}, Expected: int64(len(o)), Affected: int64(len(v))}
+  }
+
+  return nil
+  {{- else -}}
 	_, err := {{$tAlias.UpPlural}}.Update(vals.UpdateMod(), o.UpdateMod()).All(ctx, exec)
   return err
+  {{- end}}
 }
 {{- end}}
 
@@ -206,9 +239,37 @@ func (o {{$tAlias.UpSingular}}Slice) DeleteAll(ctx context.Context, exec bob.Exe
     return nil
   }
 
+  {{if index $.VersionColumns $table.Key -}}
+  {{$.Importer.Import "github.com/stephenafamo/bob/orm"}}
+	rows, err := {{$tAlias.UpPlural}}.Delete(o.DeleteMod()).Exec(ctx, exec)
+  if err != nil {
+    return err
+  }
+
+  if rows != int64(len(o)) {
+    return &orm.StaleObjectError{Table: {{quote $table.Key}}, Expected: int64(len(o)), Affected: rows}
+  }
+
+  return nil
+  {{- else -}}
 	_, err := {{$tAlias.UpPlural}}.Delete(o.DeleteMod()).Exec(ctx, exec)
   return err
+  {{- end}}
+}
+
+{{- if index $.SoftDeleteColumns $table.Key}}
+
+// HardDeleteAll permanently deletes the records in the slice,
+// even though the table uses soft deletes
+func (o {{$tAlias.UpSingular}}Slice) HardDeleteAll(ctx context.Context, exec bob.Executor) error {
+  if len(o) == 0 {
+    return nil
+  }
+
+	_, err := {{$tAlias.UpPlural}}.HardDelete(o.DeleteMod()).Exec(ctx, exec)
+  return err
 }
+{{- end}}
 
 
 func (o {{$tAlias.UpSingular}}Slice) ReloadAll(ctx context.Context, exec bob.Executor) error {
@@ -0,0 +1,40 @@
+{{- define "setter/insert/columns" -}}
+{{- $tAlias := .Data.Aliases.Table .Table.Key -}}
+{{- $timestamps := index .Data.TimestampColumns .Table.Key -}}
+{{- range $column := .Table.NonGeneratedColumns -}}
+{{- if or (eq $column.Name $timestamps.CreatedAt) (eq $column.Name $timestamps.UpdatedAt)}}
+    if {{$.Data.Types.IsOptionalInvalid $.Data.CurrentPackage $column.Type $column.Nullable (cat "s." ($tAlias.Column $column.Name))}} {
+      q.TableRef.Columns = append(q.TableRef.Columns, {{quote $column.Name}})
+    }
+{{- end -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "setter/insert/unset" -}}
+{{- $timestamps := index .Data.TimestampColumns .Table.Key -}}
+{{- if or (eq .Column.Name $timestamps.CreatedAt) (eq .Column.Name $timestamps.UpdatedAt) -}}
+{{- .Data.Importer.Import "github.com/stephenafamo/bob/orm"}}
+          // {{.Column.Name}} is set to the current time if it was not given
+          return {{.Data.Dialect}}.Arg(orm.Now(ctx)).WriteSQL(ctx, w, d, start)
+{{- else}}
+          return {{.Default}}.WriteSQL(ctx, w, d, start)
+{{- end -}}
+{{- end -}}
+
+{{- define "setter/expressions/unset" -}}
+{{- $timestamps := index .Data.TimestampColumns .Table.Key -}}
+{{- if eq .Column.Name $timestamps.UpdatedAt -}}
+{{- .Data.Importer.Import "io" -}}
+{{- .Data.Importer.Import "context" -}}
+{{- .Data.Importer.Import "github.com/stephenafamo/bob/orm" -}}
+{{- " "}}else {
+      // {{.Column.Name}} is set to the current time if it was not given
+      exprs = append(exprs, expr.Join{Sep: " = ", Exprs: []bob.Expression{
+        {{.Data.Dialect}}.Quote(append(prefix, "{{.Column.Name}}")...),
+        bob.ExpressionFunc(func(ctx context.Context, w io.StringWriter, d bob.Dialect, start int) ([]any, error) {
+          return {{.Data.Dialect}}.Arg(orm.Now(ctx)).WriteSQL(ctx, w, d, start)
+        }),
+      }})
+    }
+{{- end -}}
+{{- end -}}
@@ -8,7 +8,17 @@ type {{$tAlias.DownSingular}}Where[Q {{$.Dialect}}.Filterable] struct {
 	{{range $column := $table.Columns -}}
     {{- $colAlias := $tAlias.Column $column.Name -}}
     {{- $colTyp := $.Types.Get $.CurrentPackage $.Importer $column.Type -}}
-		{{- if $column.Nullable -}}
+    {{- $isArray := and (eq $.Dialect "psql") (hasSuffix "[]" $column.DBType) -}}
+    {{- $isSearch := and (eq $.Dialect "psql") (eq $column.DBType "tsvector") -}}
+		{{- if and $isSearch $column.Nullable -}}
+			{{$colAlias}} {{$.Dialect}}.WhereSearchNullMod[Q, {{$colTyp}}]
+		{{- else if $isSearch -}}
+			{{$colAlias}} {{$.Dialect}}.WhereSearchMod[Q, {{$colTyp}}]
+		{{- else if and $isArray $column.Nullable -}}
+			{{$colAlias}} {{$.Dialect}}.WhereArrayNullMod[Q, {{$colTyp}}]
+		{{- else if $isArray -}}
+			{{$colAlias}} {{$.Dialect}}.WhereArrayMod[Q, {{$colTyp}}]
+		{{- else if $column.Nullable -}}
 			{{$colAlias}} {{$.Dialect}}.WhereNullMod[Q, {{$colTyp}}]
 		{{- else -}}
 			{{$colAlias}} {{$.Dialect}}.WhereMod[Q, {{$colTyp}}]
@@ -29,7 +39,17 @@ func build{{$tAlias.UpSingular}}Where[Q {{$.Dialect}}.Filterable](cols {{$tAlias
 			{{range $column := $table.Columns -}}
       {{- $colAlias := $tAlias.Column $column.Name -}}
       {{- $colTyp := $.Types.Get $.CurrentPackage $.Importer $column.Type -}}
-				{{- if $column.Nullable -}}
+      {{- $isArray := and (eq $.Dialect "psql") (hasSuffix "[]" $column.DBType) -}}
+      {{- $isSearch := and (eq $.Dialect "psql") (eq $column.DBType "tsvector") -}}
+				{{- if and $isSearch $column.Nullable -}}
+					{{$colAlias}}: {{$.Dialect}}.WhereSearchNull[Q, {{$colTyp}}](cols.{{$colAlias}}.Expression),
+				{{- else if $isSearch -}}
+					{{$colAlias}}: {{$.Dialect}}.WhereSearch[Q, {{$colTyp}}](cols.{{$colAlias}}.Expression),
+				{{- else if and $isArray $column.Nullable -}}
+					{{$colAlias}}: {{$.Dialect}}.WhereArrayNull[Q, {{$colTyp}}](cols.{{$colAlias}}.Expression),
+				{{- else if $isArray -}}
+					{{$colAlias}}: {{$.Dialect}}.WhereArray[Q, {{$colTyp}}](cols.{{$colAlias}}.Expression),
+				{{- else if $column.Nullable -}}
 					{{$colAlias}}: {{$.Dialect}}.WhereNull[Q, {{$colTyp}}](cols.{{$colAlias}}.Expression),
 				{{- else -}}
 					{{$colAlias}}: {{$.Dialect}}.Where[Q, {{$colTyp}}](cols.{{$colAlias}}.Expression),
@@ -41,6 +61,19 @@ func build{{$tAlias.UpSingular}}Where[Q {{$.Dialect}}.Filterable](cols {{$tAlias
 	}
 }
 
+{{/* FTS5 virtual tables are searched with the hidden column
+     that has the same name as the table. The method is skipped if a column
+     would have the same name. */}}
+{{- $searchTaken := false -}}
+{{- range $colAlias := $tAlias.Columns}}{{if eq $colAlias "Search"}}{{$searchTaken = true}}{{end}}{{end -}}
+{{if and (eq $.Dialect "sqlite") (eq $table.Module "fts5") (not $searchTaken) -}}
+{{$.Importer.Import "github.com/stephenafamo/bob/mods"}}
+// Search filters the rows that match the FTS5 full text query
+func (w {{$tAlias.DownSingular}}Where[Q]) Search(query string) mods.Where[Q] {
+	return mods.Where[Q]{E: {{$.Dialect}}.Quote(w.cols.Alias(), {{quote $table.Name}}).Match({{$.Dialect}}.Arg(query))}
+}
+
+{{end -}}
 {{if $.Relationships.Get $table.Key -}}
 // {{$tAlias.DownSingular}}WhereR holds the relationship-based filters of
 // {{$tAlias.DownSingular}}Where under the R namespace — mirroring the model's
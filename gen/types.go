@@ -21,6 +21,16 @@ func isPrimitiveType(name string) bool {
 	}
 }
 
+func isIntegerType(name string) bool {
+	switch name {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64":
+		return true
+	default:
+		return false
+	}
+}
+
 // processTypeReplacements checks the config for type replacements
 // and performs them.
 func processTypeReplacements[C, I any](types drivers.Types, replacements []Replace, tables []drivers.Table[C, I]) {
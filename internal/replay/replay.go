@@ -0,0 +1,77 @@
+// Package replay returns values held in memory as [*sql.Rows], so that they are
+// scanned with the same conversions as rows from a database
+package replay
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+)
+
+var db = sql.OpenDB(connector{})
+
+// Rows returns the given rows as [*sql.Rows].
+// Each row must have a value for every column
+func Rows(ctx context.Context, columns []string, rows [][]any) (*sql.Rows, error) {
+	return db.QueryContext(ctx, "", data{columns: columns, rows: rows})
+}
+
+var errReplayOnly = errors.New("replay: the driver can only return rows held in memory")
+
+type data struct {
+	columns []string
+	rows    [][]any
+}
+
+type connector struct{}
+
+func (connector) Connect(context.Context) (driver.Conn, error) { return conn{}, nil }
+func (connector) Driver() driver.Driver                        { return replayDriver{} }
+
+type replayDriver struct{}
+
+func (replayDriver) Open(string) (driver.Conn, error) { return conn{}, nil }
+
+type conn struct{}
+
+func (conn) Prepare(string) (driver.Stmt, error) { return nil, errReplayOnly }
+func (conn) Close() error                        { return nil }
+func (conn) Begin() (driver.Tx, error)           { return nil, errReplayOnly }
+
+// CheckNamedValue lets the data be passed as an arg without conversion
+func (conn) CheckNamedValue(*driver.NamedValue) error { return nil }
+
+func (conn) QueryContext(_ context.Context, _ string, args []driver.NamedValue) (driver.Rows, error) {
+	if len(args) != 1 {
+		return nil, errReplayOnly
+	}
+
+	d, ok := args[0].Value.(data)
+	if !ok {
+		return nil, errReplayOnly
+	}
+
+	return &rows{data: d}, nil
+}
+
+type rows struct {
+	data
+	next int
+}
+
+func (r *rows) Columns() []string { return r.columns }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.next >= len(r.rows) {
+		return io.EOF
+	}
+
+	for i, v := range r.rows[r.next] {
+		dest[i] = v
+	}
+	r.next++
+	return nil
+}
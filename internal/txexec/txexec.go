@@ -0,0 +1,114 @@
+// Package txexec runs single queries on an executor prepared for them,
+// such as a transaction that is ended once the query is done
+package txexec
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/scan"
+)
+
+// EndFunc ends the use of the executor after a query.
+// It is given the error of the query, and returns it or an error from ending
+type EndFunc func(ctx context.Context, err error) error
+
+// Keep is an [EndFunc] for executors that need nothing done after the query
+func Keep(_ context.Context, err error) error {
+	return err
+}
+
+// Commit returns an [EndFunc] that commits tx if the query succeeded,
+// and rolls it back otherwise
+func Commit(tx bob.Transaction) EndFunc {
+	return func(ctx context.Context, err error) error {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			return err
+		}
+
+		return tx.Commit(ctx)
+	}
+}
+
+// BeginFunc returns the executor to run a query on, the context to run it with
+// and the function to call once it is done
+type BeginFunc func(ctx context.Context) (context.Context, bob.Executor, EndFunc, error)
+
+// ConvertFunc converts the error of a query run with the given context.
+// A nil ConvertFunc returns errors unchanged
+type ConvertFunc func(ctx context.Context, err error) error
+
+func (c ConvertFunc) convert(ctx context.Context, err error) error {
+	if c == nil || err == nil {
+		return err
+	}
+
+	return c(ctx, err)
+}
+
+// Exec runs the query on the executor returned by begin and ends it
+func Exec(ctx context.Context, begin BeginFunc, convert ConvertFunc, query string, args ...any) (sql.Result, error) {
+	queryCtx, exec, end, err := begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := exec.ExecContext(queryCtx, query, args...)
+	if err := end(ctx, convert.convert(queryCtx, err)); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Query runs the query on the executor returned by begin.
+// It is ended when the returned rows are closed
+func Query(ctx context.Context, begin BeginFunc, convert ConvertFunc, query string, args ...any) (scan.Rows, error) {
+	queryCtx, exec, end, err := begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := exec.QueryContext(queryCtx, query, args...)
+	if err != nil {
+		return nil, end(ctx, convert.convert(queryCtx, err))
+	}
+
+	return &endRows{
+		Rows:     rows,
+		ctx:      ctx,
+		queryCtx: queryCtx,
+		end:      end,
+		convert:  convert,
+	}, nil
+}
+
+// endRows ends the use of the executor when the rows are closed
+type endRows struct {
+	scan.Rows
+	ctx      context.Context
+	queryCtx context.Context
+	end      EndFunc
+	convert  ConvertFunc
+	closed   bool
+}
+
+func (r *endRows) Err() error {
+	return r.convert.convert(r.queryCtx, r.Rows.Err())
+}
+
+func (r *endRows) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+
+	err := r.Rows.Close()
+	if rowsErr := r.Rows.Err(); rowsErr != nil {
+		err = rowsErr
+	}
+
+	return r.end(r.ctx, r.convert.convert(r.queryCtx, err))
+}
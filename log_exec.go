@@ -0,0 +1,317 @@
+package bob
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/stephenafamo/scan"
+)
+
+// LogOptions configures the logging done by [Log], [LogTx] and [LogTransactor]
+type LogOptions struct {
+	// Logger is used to emit the records.
+	// if nil, it fallsback to [slog.Default]
+	Logger *slog.Logger
+
+	// Level is the level used for successful queries.
+	// if nil, it fallsback to [slog.LevelDebug]
+	Level slog.Leveler
+
+	// SlowLevel is the level used for queries that took at least SlowThreshold.
+	// if nil, it fallsback to [slog.LevelWarn]
+	SlowLevel slog.Leveler
+
+	// ErrorLevel is the level used for queries that returned an error.
+	// if nil, it fallsback to [slog.LevelError]
+	ErrorLevel slog.Leveler
+
+	// SlowThreshold marks queries that took at least this long as slow.
+	// A zero value disables slow query detection
+	SlowThreshold time.Duration
+
+	// RedactArgs is called with the args of every query before they are logged.
+	// Use [RedactAllArgs] to omit the values completely.
+	// if nil, the args are logged as-is
+	RedactArgs func(query string, args []any) []any
+}
+
+// RedactAllArgs can be used as [LogOptions.RedactArgs] to replace the value
+// of every argument with a placeholder
+func RedactAllArgs(_ string, args []any) []any {
+	redacted := make([]any, len(args))
+	for i := range args {
+		redacted[i] = "[REDACTED]"
+	}
+	return redacted
+}
+
+// Log wraps an [Executor] and logs every query with the given options.
+// Each record contains the SQL, the args, the elapsed time, the number of rows
+// affected or scanned, the [QueryType] (when known) and the error if any.
+//
+// Rows returned by QueryContext are logged when they are closed
+// so that the duration and row count include scanning.
+func Log(exec Executor, opts LogOptions) LoggedExecutor {
+	return LoggedExecutor{exec: exec, logger: newQueryLogger(opts)}
+}
+
+// LogTx wraps a [Transaction] and logs every query with the given options.
+// Commit and Rollback are also logged.
+func LogTx(tx Transaction, opts LogOptions) LoggedTransaction {
+	return LoggedTransaction{
+		LoggedExecutor: LoggedExecutor{exec: tx, logger: newQueryLogger(opts)},
+		tx:             tx,
+	}
+}
+
+// LogTransactor wraps a [Transactor] such as [DB] and logs every query
+// with the given options. Transactions started with Begin are logged too.
+func LogTransactor[Tx Transaction](t Transactor[Tx], opts LogOptions) LoggedTransactor[Tx] {
+	return LoggedTransactor[Tx]{
+		LoggedExecutor: LoggedExecutor{exec: t, logger: newQueryLogger(opts)},
+		t:              t,
+	}
+}
+
+// LoggedExecutor is an [Executor] that logs every query using [log/slog]
+type LoggedExecutor struct {
+	exec   Executor
+	logger queryLogger
+}
+
+// ExecContext executes the query and logs it along with the rows affected
+func (l LoggedExecutor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	result, err := l.exec.ExecContext(ctx, query, args...)
+
+	rows := int64(-1)
+	if err == nil && result != nil {
+		if affected, rowsErr := result.RowsAffected(); rowsErr == nil {
+			rows = affected
+		}
+	}
+
+	l.logger.log(ctx, "exec", query, args, time.Since(start), rows, err)
+	return result, err
+}
+
+// QueryContext executes the query. The query is logged when the returned rows are closed
+// or immediately if the query fails
+func (l LoggedExecutor) QueryContext(ctx context.Context, query string, args ...any) (scan.Rows, error) {
+	start := time.Now()
+	rows, err := l.exec.QueryContext(ctx, query, args...)
+	if err != nil || rows == nil {
+		l.logger.log(ctx, "query", query, args, time.Since(start), -1, err)
+		return rows, err
+	}
+
+	return &loggedRows{
+		Rows:   rows,
+		ctx:    ctx,
+		logger: l.logger,
+		query:  query,
+		args:   args,
+		start:  start,
+	}, nil
+}
+
+// LoggedTransaction is a [Transaction] that logs every query using [log/slog]
+type LoggedTransaction struct {
+	LoggedExecutor
+	tx Transaction
+}
+
+// Commit commits the wrapped transaction and logs the result
+func (l LoggedTransaction) Commit(ctx context.Context) error {
+	start := time.Now()
+	err := l.tx.Commit(ctx)
+	l.logger.log(ctx, "commit", "COMMIT", nil, time.Since(start), -1, err)
+	return err
+}
+
+// Rollback rolls back the wrapped transaction and logs the result
+func (l LoggedTransaction) Rollback(ctx context.Context) error {
+	start := time.Now()
+	err := l.tx.Rollback(ctx)
+	l.logger.log(ctx, "rollback", "ROLLBACK", nil, time.Since(start), -1, err)
+	return err
+}
+
+// LoggedTransactor is a [Transactor] that logs every query using [log/slog]
+// including those run in transactions started with Begin
+type LoggedTransactor[Tx Transaction] struct {
+	LoggedExecutor
+	t Transactor[Tx]
+}
+
+// Begin starts a transaction that is logged with the same options
+func (l LoggedTransactor[Tx]) Begin(ctx context.Context) (LoggedTransaction, error) {
+	return l.begin(ctx, l.t.Begin)
+}
+
+// BeginTx starts a transaction with the given options that is logged with the same options.
+// The wrapped transactor must have a BeginTx method that takes [*sql.TxOptions], such as [DB].
+// If it does not, only nil options are accepted and the transaction is started with Begin
+func (l LoggedTransactor[Tx]) BeginTx(ctx context.Context, opts *sql.TxOptions) (LoggedTransaction, error) {
+	if t, ok := l.t.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (Tx, error)
+	}); ok {
+		return l.begin(ctx, func(ctx context.Context) (Tx, error) {
+			return t.BeginTx(ctx, opts)
+		})
+	}
+
+	if opts != nil {
+		return LoggedTransaction{}, fmt.Errorf("%T does not support transaction options", l.t)
+	}
+
+	return l.Begin(ctx)
+}
+
+func (l LoggedTransactor[Tx]) begin(ctx context.Context, begin func(context.Context) (Tx, error)) (LoggedTransaction, error) {
+	start := time.Now()
+	tx, err := begin(ctx)
+	l.logger.log(ctx, "begin", "BEGIN", nil, time.Since(start), -1, err)
+	if err != nil {
+		return LoggedTransaction{}, err
+	}
+
+	return LoggedTransaction{
+		LoggedExecutor: LoggedExecutor{exec: tx, logger: l.logger},
+		tx:             tx,
+	}, nil
+}
+
+// RunInTx runs the provided function in a logged transaction started with BeginTx.
+// If the function returns an error, the transaction is rolled back.
+// Otherwise, the transaction is committed.
+func (l LoggedTransactor[Tx]) RunInTx(ctx context.Context, txOptions *sql.TxOptions, fn func(context.Context, Transaction) error) error {
+	tx, err := l.BeginTx(ctx, txOptions)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	return RunTx(ctx, tx, fn)
+}
+
+func newQueryLogger(opts LogOptions) queryLogger {
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	if opts.Level == nil {
+		opts.Level = slog.LevelDebug
+	}
+	if opts.SlowLevel == nil {
+		opts.SlowLevel = slog.LevelWarn
+	}
+	if opts.ErrorLevel == nil {
+		opts.ErrorLevel = slog.LevelError
+	}
+
+	return queryLogger{opts}
+}
+
+type queryLogger struct {
+	opts LogOptions
+}
+
+// log emits a single record. rows is omitted when negative
+func (l queryLogger) log(ctx context.Context, msg, query string, args []any, elapsed time.Duration, rows int64, err error) {
+	slow := l.opts.SlowThreshold > 0 && elapsed >= l.opts.SlowThreshold
+
+	var level slog.Level
+	switch {
+	case err != nil:
+		level = l.opts.ErrorLevel.Level()
+	case slow:
+		level = l.opts.SlowLevel.Level()
+	default:
+		level = l.opts.Level.Level()
+	}
+
+	if !l.opts.Logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, 7)
+	attrs = append(attrs, slog.String("sql", query))
+
+	if len(args) > 0 {
+		if l.opts.RedactArgs != nil {
+			args = l.opts.RedactArgs(query, args)
+		}
+		attrs = append(attrs, slog.Any("args", logValues(args)))
+	}
+
+	if typ, ok := QueryTypeFromContext(ctx); ok {
+		attrs = append(attrs, slog.String("query_type", typ.String()))
+	}
+
+	attrs = append(attrs, slog.Duration("duration", elapsed))
+
+	if rows >= 0 {
+		attrs = append(attrs, slog.Int64("rows", rows))
+	}
+
+	if slow {
+		attrs = append(attrs, slog.Bool("slow", true))
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+
+	l.opts.Logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// logValues resolves any [driver.Valuer] so that the logged value
+// is what is sent to the database
+func logValues(args []any) []any {
+	vals := make([]any, len(args))
+	for i, arg := range args {
+		vals[i] = arg
+		if valuer, ok := arg.(driver.Valuer); ok {
+			if val, err := valuer.Value(); err == nil {
+				vals[i] = val
+			}
+		}
+	}
+	return vals
+}
+
+// loggedRows counts the scanned rows and logs the query once closed
+type loggedRows struct {
+	scan.Rows
+	ctx    context.Context
+	logger queryLogger
+	query  string
+	args   []any
+	start  time.Time
+	count  int64
+	once   sync.Once
+}
+
+func (r *loggedRows) Next() bool {
+	if r.Rows.Next() {
+		r.count++
+		return true
+	}
+	return false
+}
+
+func (r *loggedRows) Close() error {
+	err := r.Rows.Close()
+	r.once.Do(func() {
+		logErr := r.Rows.Err()
+		if logErr == nil {
+			logErr = err
+		}
+		r.logger.log(r.ctx, "query", r.query, r.args, time.Since(r.start), r.count, logErr)
+	})
+	return err
+}
@@ -0,0 +1,237 @@
+package bob
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stephenafamo/scan"
+)
+
+type logTestResult int64
+
+func (r logTestResult) LastInsertId() (int64, error) { return 0, nil }
+func (r logTestResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+type logTestRows struct{ remaining int }
+
+func (r *logTestRows) Scan(...any) error          { return nil }
+func (r *logTestRows) Columns() ([]string, error) { return nil, nil }
+func (r *logTestRows) Close() error               { return nil }
+func (r *logTestRows) Err() error                 { return nil }
+func (r *logTestRows) Next() bool {
+	if r.remaining == 0 {
+		return false
+	}
+	r.remaining--
+	return true
+}
+
+type logTestExecutor struct {
+	delay time.Duration
+	err   error
+}
+
+func (e logTestExecutor) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	time.Sleep(e.delay)
+	return logTestResult(3), e.err
+}
+
+func (e logTestExecutor) QueryContext(context.Context, string, ...any) (scan.Rows, error) {
+	time.Sleep(e.delay)
+	if e.err != nil {
+		return nil, e.err
+	}
+	return &logTestRows{remaining: 2}, nil
+}
+
+type logTestTx struct{ logTestExecutor }
+
+func (logTestTx) Commit(context.Context) error   { return nil }
+func (logTestTx) Rollback(context.Context) error { return nil }
+
+type logTestTransactor struct{ logTestExecutor }
+
+func (t logTestTransactor) Begin(context.Context) (logTestTx, error) {
+	return logTestTx(t), nil
+}
+
+type logTestTxOptionsTransactor struct {
+	logTestTransactor
+	opts *sql.TxOptions
+}
+
+func (t *logTestTxOptionsTransactor) BeginTx(_ context.Context, opts *sql.TxOptions) (logTestTx, error) {
+	t.opts = opts
+	return logTestTx{t.logTestExecutor}, nil
+}
+
+func readLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var records []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var record map[string]any
+		if err := dec.Decode(&record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+
+	return records
+}
+
+func TestLogExecutor(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	exec := Log(logTestExecutor{}, LogOptions{Logger: logger})
+
+	ctx := withQueryType(context.Background(), QueryTypeUpdate)
+	if _, err := exec.ExecContext(ctx, "UPDATE x SET a = $1", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := exec.QueryContext(context.Background(), "SELECT 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records := readLogRecords(t, buf)
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+
+	execRecord := records[0]
+	if execRecord["msg"] != "exec" || execRecord["level"] != "DEBUG" {
+		t.Fatalf("unexpected exec record: %v", execRecord)
+	}
+	if execRecord["sql"] != "UPDATE x SET a = $1" {
+		t.Fatalf("unexpected sql: %v", execRecord["sql"])
+	}
+	if execRecord["query_type"] != "UPDATE" {
+		t.Fatalf("unexpected query type: %v", execRecord["query_type"])
+	}
+	if execRecord["rows"] != float64(3) {
+		t.Fatalf("unexpected rows affected: %v", execRecord["rows"])
+	}
+	if args, _ := execRecord["args"].([]any); len(args) != 1 || args[0] != "secret" {
+		t.Fatalf("unexpected args: %v", execRecord["args"])
+	}
+
+	queryRecord := records[1]
+	if queryRecord["msg"] != "query" || queryRecord["rows"] != float64(2) {
+		t.Fatalf("unexpected query record: %v", queryRecord)
+	}
+	if _, ok := queryRecord["query_type"]; ok {
+		t.Fatalf("query type should not be set: %v", queryRecord)
+	}
+}
+
+func TestLogExecutorLevels(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	slow := Log(logTestExecutor{delay: 5 * time.Millisecond}, LogOptions{
+		Logger:        logger,
+		SlowThreshold: time.Millisecond,
+		RedactArgs:    RedactAllArgs,
+	})
+	if _, err := slow.ExecContext(context.Background(), "SELECT pg_sleep($1)", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	errQuery := errors.New("bad query")
+	failing := Log(logTestExecutor{err: errQuery}, LogOptions{Logger: logger})
+	if _, err := failing.QueryContext(context.Background(), "SELEC 1"); !errors.Is(err, errQuery) {
+		t.Fatalf("expected error %v, got %v", errQuery, err)
+	}
+
+	records := readLogRecords(t, buf)
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+
+	if records[0]["level"] != "WARN" || records[0]["slow"] != true {
+		t.Fatalf("unexpected slow record: %v", records[0])
+	}
+	if args, _ := records[0]["args"].([]any); len(args) != 1 || args[0] != "[REDACTED]" {
+		t.Fatalf("args were not redacted: %v", records[0]["args"])
+	}
+
+	if records[1]["level"] != "ERROR" || records[1]["error"] != errQuery.Error() {
+		t.Fatalf("unexpected error record: %v", records[1])
+	}
+}
+
+func TestLogTransactor(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	var transactor Transactor[logTestTx] = logTestTransactor{}
+	db := LogTransactor(transactor, LogOptions{Logger: logger})
+
+	err := db.RunInTx(context.Background(), nil, func(ctx context.Context, tx Transaction) error {
+		_, err := tx.ExecContext(ctx, "DELETE FROM x")
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := readLogRecords(t, buf)
+	var msgs []string
+	for _, r := range records {
+		msgs = append(msgs, r["msg"].(string))
+	}
+
+	expected := []string{"begin", "exec", "commit"}
+	if len(msgs) != len(expected) {
+		t.Fatalf("expected records %v, got %v", expected, msgs)
+	}
+	for i := range expected {
+		if msgs[i] != expected[i] {
+			t.Fatalf("expected records %v, got %v", expected, msgs)
+		}
+	}
+}
+
+func TestLogTransactorBeginTx(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(&bytes.Buffer{}, nil))
+
+	t.Run("forwards the options", func(t *testing.T) {
+		transactor := &logTestTxOptionsTransactor{}
+		db := LogTransactor[logTestTx](transactor, LogOptions{Logger: logger})
+
+		opts := &sql.TxOptions{ReadOnly: true}
+		if _, err := db.BeginTx(context.Background(), opts); err != nil {
+			t.Fatal(err)
+		}
+
+		if transactor.opts != opts {
+			t.Fatalf("expected the options to be forwarded, got %v", transactor.opts)
+		}
+	})
+
+	t.Run("rejects options it cannot forward", func(t *testing.T) {
+		db := LogTransactor[logTestTx](logTestTransactor{}, LogOptions{Logger: logger})
+
+		if _, err := db.BeginTx(context.Background(), nil); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := db.BeginTx(context.Background(), &sql.TxOptions{}); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
@@ -3,6 +3,7 @@ package mods
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/clause"
@@ -163,6 +164,18 @@ func (r Rows[Q]) Apply(q Q) {
 	}
 }
 
+type ChunkSize[Q interface{ SetChunkSize(int) }] int
+
+func (c ChunkSize[Q]) Apply(q Q) {
+	q.SetChunkSize(int(c))
+}
+
+type SizeLimit[Q interface{ SetSizeLimit(int) }] int
+
+func (s SizeLimit[Q]) Apply(q Q) {
+	q.SetSizeLimit(int(s))
+}
+
 type Returning[Q interface{ AppendReturning(vals ...any) }] []any
 
 func (s Returning[Q]) Apply(q Q) {
@@ -247,3 +260,9 @@ type Hook[Q interface{ AppendHooks(...bob.Hook[Q]) }] []bob.Hook[Q]
 func (h Hook[Q]) Apply(q Q) {
 	q.AppendHooks(h...)
 }
+
+type StatementTimeout[Q interface{ SetStatementTimeout(time.Duration) }] time.Duration
+
+func (t StatementTimeout[Q]) Apply(q Q) {
+	q.SetStatementTimeout(time.Duration(t))
+}
@@ -0,0 +1,241 @@
+package orm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/clause"
+	"github.com/stephenafamo/scan"
+)
+
+// chunkable is implemented by insert queries that can be split into
+// multiple statements, see [clause.Values.ChunkSize]
+type chunkable interface {
+	Chunks(ctx context.Context, d bob.Dialect, restSQL string, restArgs []any) ([][]clause.Value, error)
+	SetRows(rows ...clause.Value)
+	SetHooks(...func(context.Context, bob.Executor) (context.Context, error))
+	GetLoaders() []bob.Loader
+	SetLoaders(...bob.Loader)
+}
+
+// chunks returns a query for every chunk of rows.
+// The rest of the statement is built without the rows to count its parameters
+// and size, which are taken into account when splitting the rows.
+// The hooks and loaders are removed from the chunks since they should
+// run once for the whole query.
+// It returns nil if the query does not need to be split
+func (q ExecQuery[Q]) chunks(ctx context.Context) ([]bob.BaseQuery[Q], error) {
+	c, ok := any(q.Expression).(chunkable)
+	if !ok {
+		return nil, nil
+	}
+
+	rest := q.BaseQuery.Clone()
+	any(rest.Expression).(chunkable).SetRows()
+	restSQL, restArgs, err := bob.Build(ctx, rest)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.Chunks(ctx, q.Dialect, restSQL, restArgs)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) < 2 {
+		return nil, nil
+	}
+
+	queries := make([]bob.BaseQuery[Q], len(rows))
+	for i, chunk := range rows {
+		queries[i] = q.BaseQuery.Clone()
+		queries[i].QueryType = q.QueryType
+
+		expr := any(queries[i].Expression).(chunkable)
+		expr.SetRows(chunk...)
+		expr.SetHooks()
+		expr.SetLoaders()
+	}
+
+	return queries, nil
+}
+
+// inChunkTx runs fn in a transaction started on exec with [bob.Begin],
+// so that either all the chunks are inserted or none of them.
+// If exec is already a transaction, a savepoint is used if it supports them,
+// otherwise fn is run in the transaction directly, and it is up to the caller
+// to roll it back if an error is returned.
+// If exec cannot begin a transaction and is not one, [ErrChunksNotAtomic] is returned
+func inChunkTx(ctx context.Context, exec bob.Executor, fn func(bob.Executor) error) error {
+	tx, err := bob.Begin(ctx, exec)
+	if errors.Is(err, bob.ErrCannotBegin) {
+		if _, ok := exec.(bob.Transaction); ok {
+			return fn(exec)
+		}
+
+		return fmt.Errorf("%w: %T", ErrChunksNotAtomic, exec)
+	}
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback(ctx)
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// execChunks runs the hooks once and then executes every chunk in a transaction
+func (q ExecQuery[Q]) execChunks(ctx context.Context, exec bob.Executor, chunks []bob.BaseQuery[Q]) (int64, error) {
+	ctx, err := q.RunHooks(ctx, exec)
+	if err != nil {
+		return 0, err
+	}
+
+	var affected int64
+	err = inChunkTx(ctx, exec, func(exec bob.Executor) error {
+		for _, chunk := range chunks {
+			result, err := bob.Exec(ctx, exec, chunk)
+			if err != nil {
+				return err
+			}
+
+			rows, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			affected += rows
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	ctx = bob.LoaderContext(ctx, q)
+	for _, loader := range q.GetLoaders() {
+		if err := loader.Load(ctx, exec, nil); err != nil {
+			return affected, err
+		}
+	}
+
+	return affected, nil
+}
+
+// allChunks runs the hooks once, scans the rows of every chunk in a transaction
+// and then runs the loaders and the AfterQueryHook on the combined rows
+func (q Query[Q, T, Ts, Tr]) allChunks(ctx context.Context, exec bob.Executor, chunks []bob.BaseQuery[Q]) ([]T, Ts, error) {
+	var typedSlice Ts
+
+	ctx, err := q.RunHooks(ctx, exec)
+	if err != nil {
+		return nil, typedSlice, err
+	}
+
+	var rawSlice []T
+	err = inChunkTx(ctx, exec, func(exec bob.Executor) error {
+		for _, chunk := range chunks {
+			rows, err := bob.Allx[bob.SliceTransformer[T, chunkRows[T]]](ctx, exec, chunk, q.Scanner)
+			if err != nil {
+				return err
+			}
+			rawSlice = append(rawSlice, rows...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, typedSlice, err
+	}
+
+	var transformer Tr
+	typedSlice, err = transformer.TransformScanned(rawSlice)
+	if err != nil {
+		return nil, typedSlice, err
+	}
+
+	ctx = bob.LoaderContext(ctx, q)
+	for _, loader := range q.GetLoaders() {
+		if err := loader.Load(ctx, exec, typedSlice); err != nil {
+			return nil, typedSlice, err
+		}
+	}
+
+	if h, ok := any(typedSlice).(bob.HookableType); ok {
+		if err = h.AfterQueryHook(ctx, exec, q.Type()); err != nil {
+			return nil, typedSlice, err
+		}
+	}
+
+	return rawSlice, typedSlice, nil
+}
+
+// cursorChunks scans the rows of every chunk with allChunks
+// and returns a cursor over them
+func (q Query[Q, T, Ts, Tr]) cursorChunks(ctx context.Context, exec bob.Executor, chunks []bob.BaseQuery[Q]) (scan.ICursor[T], error) {
+	rows, _, err := q.allChunks(ctx, exec, chunks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sliceCursor[T]{rows: rows}, nil
+}
+
+// eachChunks scans the rows of every chunk with allChunks
+// and returns an iterator over them
+func (q Query[Q, T, Ts, Tr]) eachChunks(ctx context.Context, exec bob.Executor, chunks []bob.BaseQuery[Q]) (func(func(T, error) bool), error) {
+	rows, _, err := q.allChunks(ctx, exec, chunks)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(yield func(T, error) bool) {
+		for _, row := range rows {
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}, nil
+}
+
+// chunkRows holds the rows scanned from a single chunk.
+// The AfterQueryHook does nothing since it is run once over all the chunks
+type chunkRows[T any] []T
+
+func (chunkRows[T]) AfterQueryHook(context.Context, bob.Executor, bob.QueryType) error {
+	return nil
+}
+
+// sliceCursor is a [scan.ICursor] over rows that have already been scanned
+type sliceCursor[T any] struct {
+	rows []T
+	idx  int
+}
+
+func (c *sliceCursor[T]) Close() error {
+	return nil
+}
+
+func (c *sliceCursor[T]) Next() bool {
+	if c.idx >= len(c.rows) {
+		return false
+	}
+
+	c.idx++
+	return true
+}
+
+func (c *sliceCursor[T]) Get() (T, error) {
+	if c.idx == 0 || c.idx > len(c.rows) {
+		return *new(T), sql.ErrNoRows
+	}
+
+	return c.rows[c.idx-1], nil
+}
+
+func (c *sliceCursor[T]) Err() error {
+	return nil
+}
@@ -1,8 +1,87 @@
 package orm
 
+import (
+	"context"
+	"time"
+)
+
 type ctxKey int
 
 const (
 	// A schema to use when non was specified during generation
 	CtxUseSchema ctxKey = iota
+	// The table or view that the executing query was started from
+	CtxQueryTable
+	// Include soft deleted rows in queries
+	CtxWithDeleted
+	// The clock used by generated code to get the current time
+	CtxClock
+	// The tenant that queries on tables with a tenant column are scoped to
+	CtxTenant
+	// Do not scope queries on tables with a tenant column
+	CtxAllTenants
 )
+
+// QueryTableFromContext returns the name of the table or view that the
+// executing query was started from, e.g. with View.Query or Table.Update.
+// The name is qualified with the schema if one was given when generating.
+func QueryTableFromContext(ctx context.Context) (string, bool) {
+	table, ok := ctx.Value(CtxQueryTable).(string)
+	return table, ok && table != ""
+}
+
+// WithDeleted modifies a context so that queries on tables with a soft delete
+// column also return rows that have been soft deleted
+func WithDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, CtxWithDeleted, true)
+}
+
+// DeletedIncluded reports if soft deleted rows should be returned by queries
+// executed with the context
+func DeletedIncluded(ctx context.Context) bool {
+	included, _ := ctx.Value(CtxWithDeleted).(bool)
+	return included
+}
+
+// WithClock modifies a context so that the given function is used to get the
+// current time, e.g. to fill timestamp columns or to soft delete rows.
+// This is mostly useful to keep tests deterministic
+func WithClock(ctx context.Context, now func() time.Time) context.Context {
+	return context.WithValue(ctx, CtxClock, now)
+}
+
+// Now returns the current time from the clock set with WithClock,
+// falling back to time.Now if there is none
+func Now(ctx context.Context) time.Time {
+	if now, ok := ctx.Value(CtxClock).(func() time.Time); ok && now != nil {
+		return now()
+	}
+
+	return time.Now()
+}
+
+// WithTenant modifies a context so that queries on tables with a tenant column
+// only read and write the rows of the given tenant
+func WithTenant(ctx context.Context, tenant any) context.Context {
+	return context.WithValue(ctx, CtxTenant, tenant)
+}
+
+// TenantFromContext returns the tenant set with WithTenant
+func TenantFromContext(ctx context.Context) (any, bool) {
+	tenant := ctx.Value(CtxTenant)
+	return tenant, tenant != nil
+}
+
+// WithAllTenants modifies a context so that queries on tables with a tenant
+// column are not scoped to a tenant. This is meant for maintenance tasks
+// that work across tenants
+func WithAllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, CtxAllTenants, true)
+}
+
+// AllTenantsIncluded reports if queries executed with the context should
+// not be scoped to a tenant
+func AllTenantsIncluded(ctx context.Context) bool {
+	included, _ := ctx.Value(CtxAllTenants).(bool)
+	return included
+}
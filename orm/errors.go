@@ -9,8 +9,43 @@ var (
 	ErrNothingToUpdate   = errors.New("nothing to update")
 	ErrCannotRetrieveRow = errors.New("cannot retrieve inserted row")
 	ErrCannotPrepare     = errors.New("supplied executor does not implement bob.Preparer")
+	ErrStaleObject       = errors.New("stale object")
+	ErrMissingTenant     = errors.New("no tenant in the context")
+	// ErrMixedUpsertSetters is returned when upserting with setters that do not
+	// set the same columns, since the conflicting rows of some setters would be
+	// updated with the default values of the columns they did not set
+	ErrMixedUpsertSetters = errors.New("upsert setters do not set the same columns")
+	// ErrUpsertWithoutTenant is returned when upserting into a table with a tenant column
+	// on conflict columns that do not include it, where the database cannot
+	// be told to leave the conflicting rows of other tenants alone
+	ErrUpsertWithoutTenant = errors.New("upsert conflict columns do not include the tenant column")
+	// ErrChunksNotAtomic is returned when an insert is split into chunks on an executor
+	// that cannot begin a transaction, since a failed chunk would leave the previous
+	// chunks inserted. Run the insert in a transaction instead
+	ErrChunksNotAtomic = errors.New("insert split into chunks needs an executor that can begin a transaction")
 )
 
+// StaleObjectError is returned by the generated Update, UpdateAll and Delete methods
+// of models with a version column when fewer rows than expected were affected.
+// This happens when the rows were changed or deleted since they were loaded.
+// It matches ErrStaleObject with errors.Is
+type StaleObjectError struct {
+	Table    string
+	Expected int64
+	Affected int64
+}
+
+func (e *StaleObjectError) Error() string {
+	return fmt.Sprintf(
+		"stale object: %s: expected %d rows to be affected, got %d",
+		e.Table, e.Expected, e.Affected,
+	)
+}
+
+func (e *StaleObjectError) Is(target error) bool {
+	return target == ErrStaleObject
+}
+
 // RelationshipChainError is the error returned when a wrong value is encountered in a relationship chain
 type RelationshipChainError struct {
 	Table1  string
@@ -0,0 +1,421 @@
+package orm
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/clause"
+	"github.com/stephenafamo/bob/expr"
+	"github.com/stephenafamo/scan"
+)
+
+var (
+	ErrInvalidCursor    = errors.New("invalid pagination cursor")
+	ErrNoKeysetOrder    = errors.New("keyset pagination requires an ORDER BY clause")
+	ErrUnsupportedOrder = errors.New("unsupported ORDER BY expression for keyset pagination")
+)
+
+// cursorColumnPrefix is used to alias the ORDER BY expressions
+// so that their values can be read from each returned row
+const cursorColumnPrefix = "__bob_cursor_"
+
+// Cursor is an opaque token that marks a position in a keyset-paginated query.
+// The zero value starts from the first page
+type Cursor string
+
+// Page is a page of results returned by keyset pagination
+type Page[T any, Ts ~[]T] struct {
+	Items Ts
+	// Next is the cursor for the page after this one.
+	// It is empty if there are no more rows
+	Next Cursor
+	// Prev is the cursor for the page before this one.
+	// It is empty if this is the first page
+	Prev Cursor
+}
+
+// KeysetQuery is a query that can be keyset paginated
+type KeysetQuery interface {
+	bob.Expression
+	AppendWhere(...any)
+	SetLimit(any)
+	AppendPreloadSelect(...any)
+	AppendMapperMod(scan.MapperMod)
+}
+
+// KeysetOptions describes how a dialect builds keyset predicates
+type KeysetOptions[Q KeysetQuery] struct {
+	// OrderBy returns the ORDER BY clause of the query
+	OrderBy func(Q) *clause.OrderBy
+	// RowValues makes the predicate use row value comparisons such as
+	// (a, b) > ($1, $2) when all columns are sorted in the same direction.
+	// Otherwise, an expanded OR chain is used
+	RowValues bool
+}
+
+// Paginate runs the query, returning at most limit rows after the given cursor.
+// The query must be ordered by a combination of columns that is unique
+// and does not contain NULLs, e.g. ORDER BY created_at DESC, id DESC.
+// The ORDER BY expressions are used in the WHERE clause, so they must not
+// reference aliases from the select list. The collation of an ORDER BY expression
+// is also used in the comparisons, so that they match the order of the rows.
+func Paginate[Q KeysetQuery, T any, Ts ~[]T, Tr bob.Transformer[T, Ts]](ctx context.Context, exec bob.Executor, q Query[Q, T, Ts, Tr], opts KeysetOptions[Q], after Cursor, limit int) (Page[T, Ts], error) {
+	var page Page[T, Ts]
+
+	if limit < 1 {
+		return page, fmt.Errorf("keyset pagination limit must be positive, got %d", limit)
+	}
+
+	backward, vals, err := decodeCursor(after)
+	if err != nil {
+		return page, err
+	}
+
+	q = q.Clone()
+	orderBy := opts.OrderBy(q.Expression)
+
+	orders := make([]clause.OrderDef, len(orderBy.Expressions))
+	for i, e := range orderBy.Expressions {
+		order, ok := e.(clause.OrderDef)
+		if !ok {
+			return page, fmt.Errorf("%w: %T", ErrUnsupportedOrder, e)
+		}
+
+		switch strings.ToUpper(order.Direction) {
+		case "", "ASC", "DESC":
+		default:
+			return page, fmt.Errorf("%w: %s", ErrUnsupportedOrder, order.Direction)
+		}
+
+		if order.Nulls != "" {
+			return page, fmt.Errorf("%w: NULLS %s", ErrUnsupportedOrder, order.Nulls)
+		}
+
+		orders[i] = order
+	}
+
+	if len(orders) == 0 {
+		return page, ErrNoKeysetOrder
+	}
+
+	if vals != nil && len(vals) != len(orders) {
+		return page, fmt.Errorf("%w: has %d values for %d ORDER BY expressions", ErrInvalidCursor, len(vals), len(orders))
+	}
+
+	if backward {
+		reversed := make([]bob.Expression, len(orders))
+		for i, order := range orders {
+			orders[i] = reverseOrder(order)
+			reversed[i] = orders[i]
+		}
+		orderBy.Expressions = reversed
+	}
+
+	if vals != nil {
+		q.Expression.AppendWhere(keysetPredicate(orders, vals, opts.RowValues))
+	}
+
+	keys := make([][]any, 0, limit+1)
+	for i, order := range orders {
+		q.Expression.AppendPreloadSelect(aliasedExpr{
+			expr:  order.Expression,
+			alias: cursorColumnPrefix + strconv.Itoa(i),
+		})
+	}
+	q.Expression.AppendMapperMod(cursorMapperMod(len(orders), &keys))
+	q.Expression.SetLimit(limit + 1)
+
+	items, err := bob.Allx[Tr](ctx, exec, q, q.Scanner)
+	if err != nil {
+		return page, err
+	}
+
+	// the transformer may have changed the number of items
+	// in that case, the keys can no longer be matched to the items
+	if len(items) != len(keys) {
+		return page, fmt.Errorf("keyset pagination: scanned %d rows but got %d items", len(keys), len(items))
+	}
+
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+		keys = keys[:limit]
+	}
+
+	if backward {
+		slices.Reverse(items)
+		slices.Reverse(keys)
+	}
+
+	page.Items = items
+	if len(items) == 0 {
+		return page, nil
+	}
+
+	first, last := keys[0], keys[len(keys)-1]
+
+	if backward {
+		if hasMore {
+			if page.Prev, err = encodeCursor(true, first); err != nil {
+				return page, err
+			}
+		}
+
+		page.Next, err = encodeCursor(false, last)
+		return page, err
+	}
+
+	if hasMore {
+		if page.Next, err = encodeCursor(false, last); err != nil {
+			return page, err
+		}
+	}
+
+	// a previous page only exists if we started after a cursor
+	if vals != nil {
+		if page.Prev, err = encodeCursor(true, first); err != nil {
+			return page, err
+		}
+	}
+
+	return page, nil
+}
+
+// reverseOrder flips the direction of the order
+func reverseOrder(order clause.OrderDef) clause.OrderDef {
+	if strings.EqualFold(order.Direction, "DESC") {
+		order.Direction = "ASC"
+	} else {
+		order.Direction = "DESC"
+	}
+
+	return order
+}
+
+func keysetOperator(order clause.OrderDef) string {
+	if strings.EqualFold(order.Direction, "DESC") {
+		return "<"
+	}
+	return ">"
+}
+
+// keysetExpr is the expression of the order as it is compared in the predicate.
+// If the order has a collation, the comparison must use it too,
+// otherwise rows are skipped or repeated when the collations sort differently
+func keysetExpr(order clause.OrderDef) any {
+	if order.Collation == "" {
+		return order.Expression
+	}
+
+	return clause.OrderDef{Expression: order.Expression, Collation: order.Collation}
+}
+
+// keysetPredicate builds the condition that selects rows after vals.
+// If possible, a row value comparison is used, e.g. (a, b) > ($1, $2)
+// Otherwise, it is expanded to (a > $1) OR (a = $1 AND b > $2)
+func keysetPredicate(orders []clause.OrderDef, vals []any, rowValues bool) bob.Expression {
+	if len(orders) == 1 {
+		return expr.OP(keysetOperator(orders[0]), keysetExpr(orders[0]), expr.Arg(vals[0]))
+	}
+
+	sameDirection := true
+	for _, order := range orders[1:] {
+		if keysetOperator(order) != keysetOperator(orders[0]) {
+			sameDirection = false
+			break
+		}
+	}
+
+	if rowValues && sameDirection {
+		exprs := make([]any, len(orders))
+		for i, order := range orders {
+			exprs[i] = keysetExpr(order)
+		}
+
+		return expr.OP(keysetOperator(orders[0]), groupExpr(exprs), expr.ArgGroup(vals...))
+	}
+
+	ors := make([]bob.Expression, len(orders))
+	for i := range orders {
+		ands := make([]bob.Expression, i+1)
+		for j := range i {
+			ands[j] = expr.OP("=", keysetExpr(orders[j]), expr.Arg(vals[j]))
+		}
+		ands[i] = expr.OP(keysetOperator(orders[i]), keysetExpr(orders[i]), expr.Arg(vals[i]))
+
+		ors[i] = groupExpr{expr.Join{Exprs: ands, Sep: " AND "}}
+	}
+
+	return groupExpr{expr.Join{Exprs: ors, Sep: " OR "}}
+}
+
+// groupExpr wraps the expressions in parentheses, separated by commas
+type groupExpr []any
+
+func (g groupExpr) WriteSQL(ctx context.Context, w io.StringWriter, d bob.Dialect, start int) ([]any, error) {
+	return bob.ExpressSlice(ctx, w, d, start, g, "(", ", ", ")")
+}
+
+type aliasedExpr struct {
+	expr  any
+	alias string
+}
+
+func (a aliasedExpr) WriteSQL(ctx context.Context, w io.StringWriter, d bob.Dialect, start int) ([]any, error) {
+	args, err := bob.Express(ctx, w, d, start, a.expr)
+	if err != nil {
+		return nil, err
+	}
+
+	w.WriteString(" AS ")
+	d.WriteQuoted(w, a.alias)
+
+	return args, nil
+}
+
+// cursorMapperMod scans the aliased ORDER BY values of every row into keys
+func cursorMapperMod(n int, keys *[][]any) scan.MapperMod {
+	return func(ctx context.Context, cols []string) (scan.BeforeFunc, scan.AfterMod) {
+		return func(r *scan.Row) (any, error) {
+				vals := make([]any, n)
+				for i := range vals {
+					r.ScheduleScanByName(cursorColumnPrefix+strconv.Itoa(i), &vals[i])
+				}
+				return vals, nil
+			}, func(link, _ any) error {
+				*keys = append(*keys, link.([]any))
+				return nil
+			}
+	}
+}
+
+type cursorPayload struct {
+	Backward bool          `json:"b,omitempty"`
+	Values   []cursorValue `json:"v"`
+}
+
+type cursorValue struct {
+	Type  string `json:"t"`
+	Value string `json:"v,omitempty"`
+}
+
+func encodeCursor(backward bool, vals []any) (Cursor, error) {
+	payload := cursorPayload{
+		Backward: backward,
+		Values:   make([]cursorValue, len(vals)),
+	}
+
+	for i, val := range vals {
+		encoded, err := encodeCursorValue(val)
+		if err != nil {
+			return "", err
+		}
+		payload.Values[i] = encoded
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	return Cursor(base64.RawURLEncoding.EncodeToString(b)), nil
+}
+
+func encodeCursorValue(val any) (cursorValue, error) {
+	if valuer, ok := val.(driver.Valuer); ok {
+		var err error
+		if val, err = valuer.Value(); err != nil {
+			return cursorValue{}, err
+		}
+	}
+
+	// byte arrays such as UUIDs scanned by some drivers
+	if rv := reflect.ValueOf(val); rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(b), rv)
+		val = b
+	}
+
+	converted, err := driver.DefaultParameterConverter.ConvertValue(val)
+	if err != nil {
+		return cursorValue{}, fmt.Errorf("keyset pagination: %w", err)
+	}
+
+	switch v := converted.(type) {
+	case nil:
+		return cursorValue{Type: "n"}, nil
+	case int64:
+		return cursorValue{Type: "i", Value: strconv.FormatInt(v, 10)}, nil
+	case float64:
+		return cursorValue{Type: "f", Value: strconv.FormatFloat(v, 'g', -1, 64)}, nil
+	case bool:
+		return cursorValue{Type: "b", Value: strconv.FormatBool(v)}, nil
+	case []byte:
+		return cursorValue{Type: "x", Value: base64.RawStdEncoding.EncodeToString(v)}, nil
+	case string:
+		return cursorValue{Type: "s", Value: v}, nil
+	case time.Time:
+		return cursorValue{Type: "t", Value: v.Format(time.RFC3339Nano)}, nil
+	default:
+		return cursorValue{}, fmt.Errorf("keyset pagination: unsupported cursor value %T", v)
+	}
+}
+
+func decodeCursor(c Cursor) (bool, []any, error) {
+	if c == "" {
+		return false, nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(string(c))
+	if err != nil {
+		return false, nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(b, &payload); err != nil {
+		return false, nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	if len(payload.Values) == 0 {
+		return false, nil, fmt.Errorf("%w: no values", ErrInvalidCursor)
+	}
+
+	vals := make([]any, len(payload.Values))
+	for i, v := range payload.Values {
+		var err error
+		switch v.Type {
+		case "n":
+			vals[i] = nil
+		case "i":
+			vals[i], err = strconv.ParseInt(v.Value, 10, 64)
+		case "f":
+			vals[i], err = strconv.ParseFloat(v.Value, 64)
+		case "b":
+			vals[i], err = strconv.ParseBool(v.Value)
+		case "x":
+			vals[i], err = base64.RawStdEncoding.DecodeString(v.Value)
+		case "s":
+			vals[i] = v.Value
+		case "t":
+			vals[i], err = time.Parse(time.RFC3339Nano, v.Value)
+		default:
+			err = fmt.Errorf("unknown value type %q", v.Type)
+		}
+		if err != nil {
+			return false, nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+		}
+	}
+
+	return payload.Backward, vals, nil
+}
@@ -0,0 +1,111 @@
+package orm
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stephenafamo/bob/clause"
+	"github.com/stephenafamo/bob/dialect/psql/dialect"
+)
+
+func TestKeysetPredicate(t *testing.T) {
+	asc := func(col string) clause.OrderDef { return clause.OrderDef{Expression: col} }
+	desc := func(col string) clause.OrderDef { return clause.OrderDef{Expression: col, Direction: "DESC"} }
+
+	tests := map[string]struct {
+		orders    []clause.OrderDef
+		rowValues bool
+		expected  string
+		args      int
+	}{
+		"single": {
+			orders:   []clause.OrderDef{desc("id")},
+			expected: "id < $1",
+			args:     1,
+		},
+		"row values": {
+			orders:    []clause.OrderDef{asc("a"), asc("b")},
+			rowValues: true,
+			expected:  "(a, b) > ($1, $2)",
+			args:      2,
+		},
+		"expanded": {
+			orders:   []clause.OrderDef{asc("a"), asc("b")},
+			expected: "((a > $1) OR (a = $2 AND b > $3))",
+			args:     3,
+		},
+		"collation": {
+			orders:   []clause.OrderDef{{Expression: "a", Collation: "C"}, asc("b")},
+			expected: `((a COLLATE "C" > $1) OR (a COLLATE "C" = $2 AND b > $3))`,
+			args:     3,
+		},
+		"mixed": {
+			orders:    []clause.OrderDef{asc("a"), desc("b"), asc("c")},
+			rowValues: true,
+			expected:  "((a > $1) OR (a = $2 AND b < $3) OR (a = $4 AND b = $5 AND c > $6))",
+			args:      6,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			vals := make([]any, len(tc.orders))
+			predicate := keysetPredicate(tc.orders, vals, tc.rowValues)
+
+			buf := &bytes.Buffer{}
+			args, err := predicate.WriteSQL(context.Background(), buf, dialect.Dialect, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if buf.String() != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, buf.String())
+			}
+
+			if len(args) != tc.args {
+				t.Fatalf("expected %d args, got %d", tc.args, len(args))
+			}
+		})
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+	vals := []any{int64(7), 1.5, "name", true, []byte{1, 2}, now, nil}
+
+	for _, backward := range []bool{false, true} {
+		cursor, err := encodeCursor(backward, vals)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gotBackward, got, err := decodeCursor(cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if gotBackward != backward {
+			t.Fatalf("expected backward=%t, got %t", backward, gotBackward)
+		}
+
+		for i := range vals {
+			switch v := vals[i].(type) {
+			case []byte:
+				if !slices.Equal(v, got[i].([]byte)) {
+					t.Fatalf("value %d: expected %v, got %v", i, v, got[i])
+				}
+			case time.Time:
+				if !v.Equal(got[i].(time.Time)) {
+					t.Fatalf("value %d: expected %v, got %v", i, v, got[i])
+				}
+			default:
+				if v != got[i] {
+					t.Fatalf("value %d: expected %v, got %v", i, v, got[i])
+				}
+			}
+		}
+	}
+}
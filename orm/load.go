@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"io"
 	"reflect"
 	"slices"
 	"strconv"
@@ -207,6 +208,28 @@ type nameable[E bob.Expression] interface {
 	Alias() string
 }
 
+// softDeletable is implemented by tables with a soft delete column
+type softDeletable interface {
+	SoftDeleteColumn() string
+}
+
+// notDeletedOn writes the ON conditions of a join to a table with a soft
+// delete column. Soft deleted rows are not joined unless the context
+// was modified with [WithDeleted]
+type notDeletedOn struct {
+	on     []bob.Expression
+	column bob.Expression
+}
+
+func (n notDeletedOn) WriteSQL(ctx context.Context, w io.StringWriter, d bob.Dialect, start int) ([]any, error) {
+	on := n.on
+	if !DeletedIncluded(ctx) {
+		on = append(slices.Clip(on), expr.Join{Exprs: []bob.Expression{n.column, expr.Raw("IS NULL")}})
+	}
+
+	return bob.ExpressSlice(ctx, w, d, start, on, "", " AND ", "")
+}
+
 type PreloadSide[E bob.Expression] struct {
 	From        nameable[E]
 	To          nameable[E]
@@ -298,6 +321,21 @@ func Preload[T Preloadable, Ts ~[]T, E bob.Expression, Q PreloadableQuery](rel P
 				}
 			}
 
+			if to, ok := side.To.(tenantScoped); ok && to.TenantColumn() != "" {
+				on = []bob.Expression{tenantOn{
+					on:     on,
+					table:  side.To.Alias(),
+					column: expr.Quote(alias, to.TenantColumn()),
+				}}
+			}
+
+			if to, ok := side.To.(softDeletable); ok && to.SoftDeleteColumn() != "" {
+				on = []bob.Expression{notDeletedOn{
+					on:     on,
+					column: expr.Quote(alias, to.SoftDeleteColumn()),
+				}}
+			}
+
 			queryMods = append(queryMods, mods.Join[Q](clause.Join{
 				Type: clause.LeftJoin,
 				To: clause.TableRef{
@@ -3,6 +3,7 @@ package orm
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/aarondl/opt"
 	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/clause"
+	"github.com/stephenafamo/bob/dialect/psql/dialect"
 	"github.com/stephenafamo/bob/expr"
 	"github.com/stephenafamo/scan"
 )
@@ -337,3 +339,128 @@ func printTestParents(ps []*testPreloadParent) string {
 	b.WriteString("]")
 	return b.String()
 }
+
+type testSoftDeleteNameable struct{ testNameable }
+
+func (testSoftDeleteNameable) SoftDeleteColumn() string { return "deleted_at" }
+
+type testTenantNameable struct{ testNameable }
+
+func (testTenantNameable) TenantColumn() string { return "tenant_id" }
+
+type testJoinQuery struct {
+	testPreloadQuery
+	joins []clause.Join
+}
+
+func (q *testJoinQuery) AppendJoin(j clause.Join) { q.joins = append(q.joins, j) }
+
+func TestPreloadSoftDelete(t *testing.T) {
+	rel := PreloadRel[bob.Expression]{
+		Name: "Child",
+		Sides: []PreloadSide[bob.Expression]{{
+			From:        testNameable{name: "parents", alias: "parents"},
+			To:          testSoftDeleteNameable{testNameable{name: "children", alias: "children"}},
+			FromColumns: []string{"child_id"},
+			ToColumns:   []string{"id"},
+		}},
+	}
+
+	q := &testJoinQuery{}
+	Preload[*testPreloadChild, testPreloadChildSlice](
+		rel, []string{"id", "name"}, nil, PreloadAs[*testJoinQuery]("c"),
+	).Apply(q)
+
+	if len(q.joins) != 1 {
+		t.Fatalf("expected 1 join, got %d", len(q.joins))
+	}
+
+	tests := map[string]struct {
+		ctx      context.Context
+		expected string
+	}{
+		"default": {
+			ctx:      context.Background(),
+			expected: `LEFT JOIN "children" AS "c" ON "parents"."child_id" = "c"."id" AND "c"."deleted_at" IS NULL`,
+		},
+		"with deleted": {
+			ctx:      WithDeleted(context.Background()),
+			expected: `LEFT JOIN "children" AS "c" ON "parents"."child_id" = "c"."id"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := &strings.Builder{}
+			if _, err := q.joins[0].WriteSQL(tc.ctx, buf, dialect.Dialect, 1); err != nil {
+				t.Fatal(err)
+			}
+
+			if buf.String() != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestPreloadTenant(t *testing.T) {
+	rel := PreloadRel[bob.Expression]{
+		Name: "Child",
+		Sides: []PreloadSide[bob.Expression]{{
+			From:        testNameable{name: "parents", alias: "parents"},
+			To:          testTenantNameable{testNameable{name: "children", alias: "children"}},
+			FromColumns: []string{"child_id"},
+			ToColumns:   []string{"id"},
+		}},
+	}
+
+	q := &testJoinQuery{}
+	Preload[*testPreloadChild, testPreloadChildSlice](
+		rel, []string{"id", "name"}, nil, PreloadAs[*testJoinQuery]("c"),
+	).Apply(q)
+
+	if len(q.joins) != 1 {
+		t.Fatalf("expected 1 join, got %d", len(q.joins))
+	}
+
+	tests := map[string]struct {
+		ctx      context.Context
+		expected string
+		args     []any
+		err      error
+	}{
+		"tenant": {
+			ctx:      WithTenant(context.Background(), 7),
+			expected: `LEFT JOIN "children" AS "c" ON "parents"."child_id" = "c"."id" AND "c"."tenant_id" = $1`,
+			args:     []any{7},
+		},
+		"all tenants": {
+			ctx:      WithAllTenants(context.Background()),
+			expected: `LEFT JOIN "children" AS "c" ON "parents"."child_id" = "c"."id"`,
+		},
+		"missing tenant": {
+			ctx: context.Background(),
+			err: ErrMissingTenant,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			buf := &strings.Builder{}
+			args, err := q.joins[0].WriteSQL(tc.ctx, buf, dialect.Dialect, 1)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected error %v, got %v", tc.err, err)
+			}
+			if tc.err != nil {
+				return
+			}
+
+			if buf.String() != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, buf.String())
+			}
+			if !reflect.DeepEqual(args, tc.args) {
+				t.Fatalf("expected args %v, got %v", tc.args, args)
+			}
+		})
+	}
+}
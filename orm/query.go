@@ -2,6 +2,7 @@ package orm
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"io"
 	"iter"
@@ -14,18 +15,27 @@ import (
 type ExecQuery[Q bob.Expression] struct {
 	bob.BaseQuery[Q]
 	Hooks *bob.Hooks[Q, bob.SkipQueryHooksKey]
+	// Table is the name of the table or view the query was started from.
+	// If set, it is added to the context when the hooks are run
+	// and can be retrieved with [QueryTableFromContext]
+	Table string
 }
 
 func (q ExecQuery[Q]) Clone() ExecQuery[Q] {
 	return ExecQuery[Q]{
 		BaseQuery: q.BaseQuery.Clone(),
 		Hooks:     q.Hooks,
+		Table:     q.Table,
 	}
 }
 
 func (q ExecQuery[Q]) RunHooks(ctx context.Context, exec bob.Executor) (context.Context, error) {
 	var err error
 
+	if q.Table != "" {
+		ctx = context.WithValue(ctx, CtxQueryTable, q.Table)
+	}
+
 	ctx, err = q.BaseQuery.RunHooks(ctx, exec)
 	if err != nil {
 		return ctx, err
@@ -38,8 +48,22 @@ func (q ExecQuery[Q]) RunHooks(ctx context.Context, exec bob.Executor) (context.
 	return q.Hooks.RunHooks(ctx, exec, q.BaseQuery.Expression)
 }
 
+// AfterQueryContext removes the table the query was started from,
+// so that the queries run by its loaders and AfterQueryHook are not attributed to it
+func (q ExecQuery[Q]) AfterQueryContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, CtxQueryTable, "")
+}
+
 // Execute the query
 func (q ExecQuery[Q]) Exec(ctx context.Context, exec bob.Executor) (int64, error) {
+	chunks, err := q.chunks(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if chunks != nil {
+		return q.execChunks(ctx, exec, chunks)
+	}
+
 	result, err := bob.Exec(ctx, exec, q)
 	if err != nil {
 		return 0, err
@@ -62,24 +86,96 @@ func (q Query[Q, T, Ts, Tr]) Clone() Query[Q, T, Ts, Tr] {
 
 // First matching row
 func (q Query[Q, T, Ts, Tr]) One(ctx context.Context, exec bob.Executor) (T, error) {
+	chunks, err := q.chunks(ctx)
+	if err != nil {
+		return *new(T), err
+	}
+	if chunks != nil {
+		rows, _, err := q.allChunks(ctx, exec, chunks)
+		if err != nil {
+			return *new(T), err
+		}
+		if len(rows) == 0 {
+			return *new(T), sql.ErrNoRows
+		}
+		return rows[0], nil
+	}
+
 	return bob.One(ctx, exec, q, q.Scanner)
 }
 
 // All matching rows
 func (q Query[Q, T, Ts, Tr]) All(ctx context.Context, exec bob.Executor) (Ts, error) {
+	chunks, err := q.chunks(ctx)
+	if err != nil {
+		return *new(Ts), err
+	}
+	if chunks != nil {
+		_, all, err := q.allChunks(ctx, exec, chunks)
+		return all, err
+	}
+
 	return bob.Allx[Tr](ctx, exec, q, q.Scanner)
 }
 
 // Cursor to scan through the results
+// If the query is split into chunks, all the rows are scanned before returning
 func (q Query[Q, T, Ts, Tr]) Cursor(ctx context.Context, exec bob.Executor) (scan.ICursor[T], error) {
+	chunks, err := q.chunks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if chunks != nil {
+		return q.cursorChunks(ctx, exec, chunks)
+	}
+
 	return bob.Cursor(ctx, exec, q, q.Scanner)
 }
 
 // Each to scan through the results
+// If the query is split into chunks, all the rows are scanned before returning
 func (q Query[Q, T, Ts, Tr]) Each(ctx context.Context, exec bob.Executor) (func(func(T, error) bool), error) {
+	chunks, err := q.chunks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if chunks != nil {
+		return q.eachChunks(ctx, exec, chunks)
+	}
+
 	return bob.Each(ctx, exec, q, q.Scanner)
 }
 
+// CursorChunked works like Cursor, but the loaders and hooks are run on loadExec
+// once for every chunk of up to size rows instead of once per row.
+// See [bob.CursorChunked]
+func (q Query[Q, T, Ts, Tr]) CursorChunked(ctx context.Context, exec, loadExec bob.Executor, size int) (scan.ICursor[T], error) {
+	chunks, err := q.chunks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if chunks != nil {
+		return q.cursorChunks(ctx, exec, chunks)
+	}
+
+	return bob.CursorChunked[Tr](ctx, exec, loadExec, q, q.Scanner, size)
+}
+
+// EachChunked works like Each, but the loaders and hooks are run on loadExec
+// once for every chunk of up to size rows instead of once per row.
+// See [bob.EachChunked]
+func (q Query[Q, T, Ts, Tr]) EachChunked(ctx context.Context, exec, loadExec bob.Executor, size int) (func(func(T, error) bool), error) {
+	chunks, err := q.chunks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if chunks != nil {
+		return q.eachChunks(ctx, exec, chunks)
+	}
+
+	return bob.EachChunked[Tr](ctx, exec, loadExec, q, q.Scanner, size)
+}
+
 type ModExecQuery[Q bob.Expression, E bob.Expression] struct {
 	ExecQuery[E]
 	Mod   bob.Mod[Q]
@@ -5,9 +5,11 @@ import (
 	"io"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/stephenafamo/scan"
 
 	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/bobtest"
 	"github.com/stephenafamo/bob/dialect/psql"
 	"github.com/stephenafamo/bob/dialect/psql/dialect"
 	"github.com/stephenafamo/bob/dialect/psql/sm"
@@ -179,3 +181,42 @@ func TestQueryClonePreservesScanner(t *testing.T) {
 		t.Fatal("Clone() did not preserve the original scanner")
 	}
 }
+
+// tableRecorder records the table in the context of each query
+type tableRecorder struct {
+	bob.Executor
+	tables []string
+}
+
+func (e *tableRecorder) QueryContext(ctx context.Context, query string, args ...any) (scan.Rows, error) {
+	table, _ := orm.QueryTableFromContext(ctx)
+	e.tables = append(e.tables, table)
+	return e.Executor.QueryContext(ctx, query, args...)
+}
+
+func TestQueryTableNotInLoaderContext(t *testing.T) {
+	mock := bobtest.New(t)
+	mock.ExpectQuery("SELECT 1").WillReturnRows(bobtest.NewRows("n").AddRow(1))
+	mock.ExpectQuery("SELECT 2").WillReturnRows(bobtest.NewRows("n").AddRow(2))
+	exec := &tableRecorder{Executor: mock}
+
+	q := orm.Query[*dialect.SelectQuery, int, []int, bob.SliceTransformer[int, []int]]{
+		ExecQuery: orm.ExecQuery[*dialect.SelectQuery]{
+			BaseQuery: psql.Select(sm.Columns(psql.Raw("1"))),
+			Table:     "todo",
+		},
+		Scanner: scan.SingleColumnMapper[int],
+	}
+	q.Expression.AppendLoader(bob.LoaderFunc(func(ctx context.Context, exec bob.Executor, _ any) error {
+		_, err := exec.QueryContext(ctx, "SELECT 2")
+		return err
+	}))
+
+	if _, err := q.All(context.Background(), exec); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"todo", ""}, exec.tables); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -0,0 +1,69 @@
+package orm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/expr"
+)
+
+// Tenant returns the tenant to scope queries on the table to.
+// ok is false if the context was modified with WithAllTenants.
+// If there is no tenant in the context, an error wrapping ErrMissingTenant is returned,
+// so that a query is never run unscoped by mistake
+func Tenant(ctx context.Context, table string) (tenant any, ok bool, err error) {
+	if AllTenantsIncluded(ctx) {
+		return nil, false, nil
+	}
+
+	tenant, ok = TenantFromContext(ctx)
+	if !ok {
+		return nil, false, fmt.Errorf("%w: cannot query %s", ErrMissingTenant, table)
+	}
+
+	return tenant, true, nil
+}
+
+// TenantMod returns a contextual mod that scopes a query on the table
+// to the tenant in the context. See [Tenant]
+func TenantMod[Q interface{ AppendWhere(...any) }](table, column string) bob.ContextualModFunc[Q] {
+	return func(ctx context.Context, q Q) (context.Context, error) {
+		tenant, ok, err := Tenant(ctx, table)
+		if err != nil || !ok {
+			return ctx, err
+		}
+
+		q.AppendWhere(expr.OP("=", expr.Quote(table, column), expr.Arg(tenant)))
+		return ctx, nil
+	}
+}
+
+// tenantScoped is implemented by tables with a tenant column
+type tenantScoped interface {
+	TenantColumn() string
+}
+
+// tenantOn writes the ON conditions of a join to a table with a tenant column,
+// so that only the rows of the tenant in the context are joined
+type tenantOn struct {
+	on     []bob.Expression
+	table  string
+	column bob.Expression
+}
+
+func (t tenantOn) WriteSQL(ctx context.Context, w io.StringWriter, d bob.Dialect, start int) ([]any, error) {
+	tenant, ok, err := Tenant(ctx, t.table)
+	if err != nil {
+		return nil, err
+	}
+
+	on := t.on
+	if ok {
+		on = append(slices.Clip(on), expr.OP("=", t.column, expr.Arg(tenant)))
+	}
+
+	return bob.ExpressSlice(ctx, w, d, start, on, "", " AND ", "")
+}
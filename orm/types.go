@@ -3,6 +3,7 @@ package orm
 import (
 	"context"
 	"io"
+	"slices"
 
 	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/expr"
@@ -32,3 +33,33 @@ type ArgWithPosition struct {
 	Stop       int
 	Expression bob.Expression
 }
+
+// UpsertColumns returns the columns that should be updated when upserting
+// with the given setters. These are the columns set in the setters,
+// except the conflict columns.
+// It returns [ErrMixedUpsertSetters] if the setters do not all set the same columns
+func UpsertColumns[T interface{ SetColumns() []string }](conflictCols []string, setters ...T) ([]string, error) {
+	if len(setters) == 0 {
+		return nil, nil
+	}
+
+	set := setters[0].SetColumns()
+	for _, s := range setters[1:] {
+		other := s.SetColumns()
+		if len(other) != len(set) || slices.ContainsFunc(other, func(col string) bool {
+			return !slices.Contains(set, col)
+		}) {
+			return nil, ErrMixedUpsertSetters
+		}
+	}
+
+	cols := make([]string, 0, len(set))
+	for _, col := range set {
+		if slices.Contains(conflictCols, col) || slices.Contains(cols, col) {
+			continue
+		}
+		cols = append(cols, col)
+	}
+
+	return cols, nil
+}
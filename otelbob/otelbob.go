@@ -0,0 +1,356 @@
+// Package otelbob instruments a [bob.Executor] with OpenTelemetry tracing and metrics.
+//
+// Every query creates a client span with the db.system, db.statement and
+// db.operation attributes. When the query was started from a generated
+// table or view (e.g. models.Users.Query()), the table name is added as db.sql.table.
+// The query duration and the number of rows returned are recorded as histograms.
+package otelbob
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/orm"
+	"github.com/stephenafamo/scan"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name used for the tracer and meter
+const ScopeName = "github.com/stephenafamo/bob/otelbob"
+
+// Attribute keys set on spans and metrics
+const (
+	DBSystemKey       = attribute.Key("db.system")
+	DBStatementKey    = attribute.Key("db.statement")
+	DBOperationKey    = attribute.Key("db.operation")
+	DBTableKey        = attribute.Key("db.sql.table")
+	DBRowsAffectedKey = attribute.Key("db.rows_affected")
+	DBRowsReturnedKey = attribute.Key("db.rows_returned")
+)
+
+// Metric names
+const (
+	DurationMetric     = "db.client.operation.duration"
+	RowsReturnedMetric = "db.client.response.returned_rows"
+)
+
+// Option configures the instrumentation
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider used to create spans.
+// Defaults to the global provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider used to record metrics.
+// Defaults to the global provider
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// WithDBSystem sets the db.system attribute, e.g. "postgresql", "mysql" or "sqlite"
+func WithDBSystem(system string) Option {
+	return func(c *config) {
+		c.attrs = append(c.attrs, DBSystemKey.String(system))
+	}
+}
+
+// WithAttributes adds attributes to every span and metric
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(c *config) {
+		c.attrs = append(c.attrs, attrs...)
+	}
+}
+
+// WithStatementFormatter sets a function to transform the query before it is
+// recorded as db.statement. Return an empty string to omit the attribute
+func WithStatementFormatter(f func(query string) string) Option {
+	return func(c *config) {
+		c.formatStatement = f
+	}
+}
+
+type config struct {
+	tracerProvider  trace.TracerProvider
+	meterProvider   metric.MeterProvider
+	attrs           []attribute.KeyValue
+	formatStatement func(string) string
+}
+
+func newInstrument(opts []Option) *instrument {
+	c := config{}
+	for _, o := range opts {
+		o(&c)
+	}
+
+	if c.tracerProvider == nil {
+		c.tracerProvider = otel.GetTracerProvider()
+	}
+
+	if c.meterProvider == nil {
+		c.meterProvider = otel.GetMeterProvider()
+	}
+
+	meter := c.meterProvider.Meter(ScopeName)
+
+	// errors are only returned for invalid names or when the provider fails
+	// in both cases, the returned instruments are no-ops that are safe to use
+	duration, err := meter.Float64Histogram(
+		DurationMetric,
+		metric.WithDescription("Duration of database client operations."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	rows, err := meter.Int64Histogram(
+		RowsReturnedMetric,
+		metric.WithDescription("The number of rows returned by the operation."),
+		metric.WithUnit("{row}"),
+	)
+	if err != nil {
+		otel.Handle(err)
+	}
+
+	return &instrument{
+		config:   c,
+		tracer:   c.tracerProvider.Tracer(ScopeName),
+		duration: duration,
+		rows:     rows,
+	}
+}
+
+type instrument struct {
+	config
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	rows     metric.Int64Histogram
+}
+
+// start creates a span for the query and returns the attributes used for metrics
+func (i *instrument) start(ctx context.Context, query string) (context.Context, trace.Span, []attribute.KeyValue) {
+	attrs := make([]attribute.KeyValue, 0, len(i.attrs)+2)
+	attrs = append(attrs, i.attrs...)
+
+	operation := operationName(ctx, query)
+	if operation != "" {
+		attrs = append(attrs, DBOperationKey.String(operation))
+	}
+
+	spanName := operation
+	if table, ok := orm.QueryTableFromContext(ctx); ok {
+		attrs = append(attrs, DBTableKey.String(table))
+		spanName += " " + table
+	}
+
+	if spanName == "" {
+		spanName = "query"
+	}
+
+	spanAttrs := attrs
+	statement := query
+	if i.formatStatement != nil {
+		statement = i.formatStatement(query)
+	}
+	if statement != "" {
+		spanAttrs = append(spanAttrs[:len(attrs):len(attrs)], DBStatementKey.String(statement))
+	}
+
+	ctx, span := i.tracer.Start(ctx, strings.TrimSpace(spanName),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(spanAttrs...),
+	)
+
+	return ctx, span, attrs
+}
+
+// end records the duration and finishes the span
+func (i *instrument) end(ctx context.Context, span trace.Span, attrs []attribute.KeyValue, start time.Time, err error) {
+	i.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// operationName uses the [bob.QueryType] when available
+// and falls back to the first keyword of the query
+func operationName(ctx context.Context, query string) string {
+	if typ, ok := bob.QueryTypeFromContext(ctx); ok && typ != bob.QueryTypeUnknown {
+		return typ.String()
+	}
+
+	query = strings.TrimSpace(query)
+	if idx := strings.IndexFunc(query, func(r rune) bool {
+		return r == ' ' || r == '\n' || r == '\t' || r == '('
+	}); idx > 0 {
+		query = query[:idx]
+	}
+
+	return strings.ToUpper(query)
+}
+
+// Wrap instruments an [bob.Executor]
+func Wrap(exec bob.Executor, opts ...Option) Executor {
+	return Executor{exec: exec, inst: newInstrument(opts)}
+}
+
+// WrapTx instruments a [bob.Transaction]
+func WrapTx(tx bob.Transaction, opts ...Option) Transaction {
+	return Transaction{
+		Executor: Executor{exec: tx, inst: newInstrument(opts)},
+		tx:       tx,
+	}
+}
+
+// WrapTransactor instruments a [bob.Transactor] such as [bob.DB] or
+// the types in [github.com/stephenafamo/bob/drivers/pgx].
+// Transactions started with Begin are instrumented too.
+func WrapTransactor[Tx bob.Transaction](t bob.Transactor[Tx], opts ...Option) Transactor[Tx] {
+	return Transactor[Tx]{
+		Executor: Executor{exec: t, inst: newInstrument(opts)},
+		t:        t,
+	}
+}
+
+// Executor is an instrumented [bob.Executor]
+type Executor struct {
+	exec bob.Executor
+	inst *instrument
+}
+
+// ExecContext executes the query in a span
+func (e Executor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	ctx, span, attrs := e.inst.start(ctx, query)
+
+	result, err := e.exec.ExecContext(ctx, query, args...)
+	if err == nil && result != nil {
+		if affected, rowsErr := result.RowsAffected(); rowsErr == nil {
+			span.SetAttributes(DBRowsAffectedKey.Int64(affected))
+		}
+	}
+
+	e.inst.end(ctx, span, attrs, start, err)
+	return result, err
+}
+
+// QueryContext executes the query in a span.
+// The span is ended when the returned rows are closed
+func (e Executor) QueryContext(ctx context.Context, query string, args ...any) (scan.Rows, error) {
+	start := time.Now()
+	ctx, span, attrs := e.inst.start(ctx, query)
+
+	rows, err := e.exec.QueryContext(ctx, query, args...)
+	if err != nil || rows == nil {
+		e.inst.end(ctx, span, attrs, start, err)
+		return rows, err
+	}
+
+	return &tracedRows{
+		Rows:  rows,
+		ctx:   ctx,
+		inst:  e.inst,
+		span:  span,
+		attrs: attrs,
+		start: start,
+	}, nil
+}
+
+// Transaction is an instrumented [bob.Transaction]
+type Transaction struct {
+	Executor
+	tx bob.Transaction
+}
+
+// Commit commits the transaction in a span
+func (t Transaction) Commit(ctx context.Context) error {
+	start := time.Now()
+	ctx, span, attrs := t.inst.start(ctx, "COMMIT")
+	err := t.tx.Commit(ctx)
+	t.inst.end(ctx, span, attrs, start, err)
+	return err
+}
+
+// Rollback rolls back the transaction in a span
+func (t Transaction) Rollback(ctx context.Context) error {
+	start := time.Now()
+	ctx, span, attrs := t.inst.start(ctx, "ROLLBACK")
+	err := t.tx.Rollback(ctx)
+	t.inst.end(ctx, span, attrs, start, err)
+	return err
+}
+
+// Transactor is an instrumented [bob.Transactor]
+type Transactor[Tx bob.Transaction] struct {
+	Executor
+	t bob.Transactor[Tx]
+}
+
+// Begin starts an instrumented transaction
+func (t Transactor[Tx]) Begin(ctx context.Context) (Transaction, error) {
+	start := time.Now()
+	spanCtx, span, attrs := t.inst.start(ctx, "BEGIN")
+	tx, err := t.t.Begin(ctx)
+	t.inst.end(spanCtx, span, attrs, start, err)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	return Transaction{
+		Executor: Executor{exec: tx, inst: t.inst},
+		tx:       tx,
+	}, nil
+}
+
+// tracedRows counts the scanned rows and ends the span once closed
+type tracedRows struct {
+	scan.Rows
+	ctx   context.Context
+	inst  *instrument
+	span  trace.Span
+	attrs []attribute.KeyValue
+	start time.Time
+	count int64
+	once  sync.Once
+}
+
+func (r *tracedRows) Next() bool {
+	if r.Rows.Next() {
+		r.count++
+		return true
+	}
+	return false
+}
+
+func (r *tracedRows) Close() error {
+	err := r.Rows.Close()
+	r.once.Do(func() {
+		endErr := r.Rows.Err()
+		if endErr == nil {
+			endErr = err
+		}
+
+		r.span.SetAttributes(DBRowsReturnedKey.Int64(r.count))
+		r.inst.rows.Record(r.ctx, r.count, metric.WithAttributes(r.attrs...))
+		r.inst.end(r.ctx, r.span, r.attrs, r.start, endErr)
+	})
+	return err
+}
@@ -0,0 +1,219 @@
+package otelbob_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/sqlite"
+	"github.com/stephenafamo/bob/dialect/sqlite/dialect"
+	"github.com/stephenafamo/bob/dialect/sqlite/sm"
+	"github.com/stephenafamo/bob/drivers/pgx"
+	"github.com/stephenafamo/bob/otelbob"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	_ "modernc.org/sqlite"
+)
+
+var (
+	_ bob.Executor                        = otelbob.Wrap(bob.DB{})
+	_ bob.Transactor[otelbob.Transaction] = otelbob.WrapTransactor(bob.DB{})
+	_ bob.Transactor[otelbob.Transaction] = otelbob.WrapTransactor(pgx.Pool{})
+	_ bob.Transactor[otelbob.Transaction] = otelbob.WrapTransactor(pgx.Conn{})
+	_ bob.Transaction                     = otelbob.WrapTx(pgx.Tx{})
+	_ bob.Transactor[otelbob.Transaction] = otelbob.WrapTransactor[bob.Tx](bob.Conn{})
+)
+
+type user struct {
+	ID   int64  `db:"id,pk"`
+	Name string `db:"name"`
+}
+
+type userSetter struct {
+	Name *string `db:"name"`
+}
+
+func (s userSetter) SetColumns() []string { return []string{"name"} }
+
+func (s userSetter) Apply(q *dialect.InsertQuery) {}
+
+func (s userSetter) UpdateMod() bob.Mod[*dialect.UpdateQuery] {
+	return bob.ModFunc[*dialect.UpdateQuery](func(*dialect.UpdateQuery) {})
+}
+
+var userTable = sqlite.NewTable[user, userSetter]("", "users", sqlite.Quote("users", "*"))
+
+func setup(t *testing.T) (*tracetest.InMemoryExporter, *sdkmetric.ManualReader, otelbob.Transactor[bob.Tx]) {
+	t.Helper()
+
+	db, err := bob.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.ExecContext(context.Background(), `
+		CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+		INSERT INTO users (name) VALUES ('alice'), ('bob');
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	exporter := tracetest.NewInMemoryExporter()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	exec := otelbob.WrapTransactor(db,
+		otelbob.WithTracerProvider(tracerProvider),
+		otelbob.WithMeterProvider(meterProvider),
+		otelbob.WithDBSystem("sqlite"),
+	)
+
+	return exporter, reader, exec
+}
+
+func spanAttr(s tracetest.SpanStub, key attribute.Key) (attribute.Value, bool) {
+	for _, a := range s.Attributes {
+		if a.Key == key {
+			return a.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestTableQuerySpan(t *testing.T) {
+	ctx := context.Background()
+	exporter, reader, exec := setup(t)
+
+	users, err := userTable.Query(sm.OrderBy("id")).All(ctx, exec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(users))
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name != "SELECT users" {
+		t.Fatalf("unexpected span name %q", span.Name)
+	}
+
+	expected := map[attribute.Key]attribute.Value{
+		otelbob.DBSystemKey:       attribute.StringValue("sqlite"),
+		otelbob.DBOperationKey:    attribute.StringValue("SELECT"),
+		otelbob.DBTableKey:        attribute.StringValue("users"),
+		otelbob.DBRowsReturnedKey: attribute.Int64Value(2),
+	}
+	for key, val := range expected {
+		got, ok := spanAttr(span, key)
+		if !ok {
+			t.Fatalf("missing attribute %s", key)
+		}
+		if got != val {
+			t.Fatalf("attribute %s: expected %v, got %v", key, val.Emit(), got.Emit())
+		}
+	}
+
+	statement, _ := spanAttr(span, otelbob.DBStatementKey)
+	if statement.AsString() == "" {
+		t.Fatal("missing db.statement")
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	found := map[string]bool{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			found[m.Name] = true
+		}
+	}
+
+	for _, name := range []string{otelbob.DurationMetric, otelbob.RowsReturnedMetric} {
+		if !found[name] {
+			t.Fatalf("metric %s was not recorded", name)
+		}
+	}
+}
+
+func TestRawQuerySpan(t *testing.T) {
+	ctx := context.Background()
+	exporter, _, exec := setup(t)
+
+	_, err := bob.Exec(ctx, exec, sqlite.RawQuery("DELETE FROM users WHERE id = ?", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = exec.ExecContext(ctx, "UPDATE missing SET a = 1")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	// raw queries have an unknown query type, so the first keyword is used
+	if spans[0].Name != "DELETE" {
+		t.Fatalf("unexpected span name %q", spans[0].Name)
+	}
+	if affected, _ := spanAttr(spans[0], otelbob.DBRowsAffectedKey); affected.AsInt64() != 1 {
+		t.Fatalf("expected 1 row affected, got %v", affected.Emit())
+	}
+
+	if spans[1].Name != "UPDATE" {
+		t.Fatalf("unexpected span name %q", spans[1].Name)
+	}
+	if spans[1].Status.Code != codes.Error {
+		t.Fatalf("expected error status, got %v", spans[1].Status.Code)
+	}
+}
+
+func TestTransactionSpans(t *testing.T) {
+	ctx := context.Background()
+	exporter, _, exec := setup(t)
+
+	tx, err := exec.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM users"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, s := range exporter.GetSpans() {
+		names = append(names, s.Name)
+	}
+
+	expected := []string{"BEGIN", "DELETE", "ROLLBACK"}
+	if len(names) != len(expected) {
+		t.Fatalf("expected spans %v, got %v", expected, names)
+	}
+	for i := range expected {
+		if names[i] != expected[i] {
+			t.Fatalf("expected spans %v, got %v", expected, names)
+		}
+	}
+}
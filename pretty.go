@@ -0,0 +1,520 @@
+package bob
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Pretty formats a query over multiple indented lines for reading.
+// Each clause starts on a new line, and subqueries are indented.
+// Only whitespace outside of strings, quoted identifiers and comments is changed.
+func Pretty(query string) string {
+	return prettyPrinter{indent: "  "}.print(tokenize(query, nil))
+}
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenQuoted
+	tokenPunct
+	tokenOpen
+	tokenClose
+	tokenComma
+	tokenSemicolon
+	tokenLineComment
+	tokenBlockComment
+	tokenPlaceholder
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// space is true if there was whitespace before the token
+	space bool
+	// arg is the 1-based index of the arg of a placeholder
+	arg int
+}
+
+// placeholders describes how a dialect writes arg placeholders
+type placeholders struct {
+	// sequential placeholders are all the same, e.g. ? in MySQL
+	sequential bool
+	// text is the placeholder, or its prefix when it is followed by the position
+	text string
+}
+
+// newPlaceholders detects how the dialect writes placeholders.
+// It returns nil if the placeholders are not in a known format
+func newPlaceholders(d Dialect) *placeholders {
+	first, second := &strings.Builder{}, &strings.Builder{}
+	d.WriteArg(first, 1)
+	d.WriteArg(second, 2)
+
+	switch {
+	case first.String() == "":
+		return nil
+	case first.String() == second.String():
+		return &placeholders{sequential: true, text: first.String()}
+	case strings.HasSuffix(first.String(), "1") &&
+		strings.TrimSuffix(first.String(), "1")+"2" == second.String():
+		return &placeholders{text: strings.TrimSuffix(first.String(), "1")}
+	default:
+		return nil
+	}
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// tokenize splits the query into tokens. If p is not nil,
+// placeholders written by the dialect are recognized
+func tokenize(query string, p *placeholders) []token {
+	var tokens []token
+	runes := []rune(query)
+	space := false
+	nextArg := 1
+
+	hasPrefix := func(i int, prefix string) bool {
+		return strings.HasPrefix(string(runes[i:min(i+len([]rune(prefix)), len(runes))]), prefix)
+	}
+
+	add := func(kind tokenKind, text string) {
+		tokens = append(tokens, token{kind: kind, text: text, space: space})
+		space = false
+	}
+
+	// quoted reads until the closing quote, which is escaped by doubling it
+	// or, if backslash is true, with a backslash
+	quoted := func(i int, quote rune, backslash bool) int {
+		j := i + 1
+		for j < len(runes) {
+			switch {
+			case backslash && runes[j] == '\\':
+				j += 2
+				continue
+			case runes[j] == quote && j+1 < len(runes) && runes[j+1] == quote:
+				j += 2
+				continue
+			case runes[j] == quote:
+				return j + 1
+			}
+			j++
+		}
+		return len(runes)
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case unicode.IsSpace(r):
+			space = true
+			i++
+
+		case hasPrefix(i, "--"):
+			end := strings.IndexRune(string(runes[i:]), '\n')
+			j := len(runes)
+			if end >= 0 {
+				j = i + len([]rune(string(runes[i:])[:end]))
+			}
+			add(tokenLineComment, string(runes[i:j]))
+			i = j
+
+		case hasPrefix(i, "/*"):
+			end := strings.Index(string(runes[i+2:]), "*/")
+			j := len(runes)
+			if end >= 0 {
+				j = i + 2 + len([]rune(string(runes[i+2:])[:end])) + 2
+			}
+			add(tokenBlockComment, string(runes[i:j]))
+			i = j
+
+		case r == '\'':
+			// E'...' strings in PostgreSQL use backslash escapes
+			escaped := len(tokens) > 0 && !space &&
+				strings.EqualFold(tokens[len(tokens)-1].text, "E")
+			j := quoted(i, '\'', escaped)
+			add(tokenQuoted, string(runes[i:j]))
+			i = j
+
+		case r == '"' || r == '`':
+			j := quoted(i, r, false)
+			add(tokenQuoted, string(runes[i:j]))
+			i = j
+
+		case p != nil && hasPrefix(i, p.text) && p.sequential:
+			tokens = append(tokens, token{kind: tokenPlaceholder, text: p.text, space: space, arg: nextArg})
+			space = false
+			nextArg++
+			i += len([]rune(p.text))
+
+		case p != nil && hasPrefix(i, p.text) && i+len([]rune(p.text)) < len(runes) &&
+			unicode.IsDigit(runes[i+len([]rune(p.text))]):
+			j := i + len([]rune(p.text))
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			arg, _ := strconv.Atoi(string(runes[i+len([]rune(p.text)) : j]))
+			tokens = append(tokens, token{kind: tokenPlaceholder, text: string(runes[i:j]), space: space, arg: arg})
+			space = false
+			i = j
+
+		case r == '$':
+			// dollar quoted strings in PostgreSQL, e.g. $$text$$ or $tag$text$tag$
+			j := i + 1
+			for j < len(runes) && (runes[j] == '_' || unicode.IsLetter(runes[j]) || (j > i+1 && unicode.IsDigit(runes[j]))) {
+				j++
+			}
+			if j < len(runes) && runes[j] == '$' {
+				tag := string(runes[i : j+1])
+				end := strings.Index(string(runes[j+1:]), tag)
+				k := len(runes)
+				if end >= 0 {
+					k = j + 1 + len([]rune(string(runes[j+1:])[:end])) + len([]rune(tag))
+				}
+				add(tokenQuoted, string(runes[i:k]))
+				i = k
+				continue
+			}
+			add(tokenPunct, "$")
+			i++
+
+		case isWordRune(r):
+			j := i
+			for j < len(runes) && isWordRune(runes[j]) {
+				j++
+			}
+			add(tokenWord, string(runes[i:j]))
+			i = j
+
+		case r == '(':
+			add(tokenOpen, "(")
+			i++
+
+		case r == ')':
+			add(tokenClose, ")")
+			i++
+
+		case r == ',':
+			add(tokenComma, ",")
+			i++
+
+		case r == ';':
+			add(tokenSemicolon, ";")
+			i++
+
+		default:
+			add(tokenPunct, string(r))
+			i++
+		}
+	}
+
+	return tokens
+}
+
+type keywordKind int
+
+const (
+	// the clause starts a new line, and its items are on the following lines
+	keywordClause keywordKind = iota + 1
+	// the keyword starts a new line, and is followed on the same line
+	keywordLine
+	// joins start a new line in the FROM clause
+	keywordJoin
+)
+
+//nolint:gochecknoglobals
+var keywords = []struct {
+	words []string
+	kind  keywordKind
+}{
+	{[]string{"ON", "DUPLICATE", "KEY", "UPDATE"}, keywordClause},
+	{[]string{"GROUP", "BY"}, keywordClause},
+	{[]string{"ORDER", "BY"}, keywordClause},
+	{[]string{"SELECT"}, keywordClause},
+	{[]string{"FROM"}, keywordClause},
+	{[]string{"WHERE"}, keywordClause},
+	{[]string{"HAVING"}, keywordClause},
+	{[]string{"WINDOW"}, keywordClause},
+	{[]string{"RETURNING"}, keywordClause},
+	{[]string{"SET"}, keywordClause},
+	{[]string{"VALUES"}, keywordClause},
+	{[]string{"WITH"}, keywordClause},
+	{[]string{"DELETE", "FROM"}, keywordLine},
+	{[]string{"MERGE", "INTO"}, keywordLine},
+	{[]string{"REPLACE", "INTO"}, keywordLine},
+	{[]string{"ON", "CONFLICT"}, keywordLine},
+	{[]string{"UNION", "ALL"}, keywordLine},
+	{[]string{"UNION", "DISTINCT"}, keywordLine},
+	{[]string{"INTERSECT", "ALL"}, keywordLine},
+	{[]string{"EXCEPT", "ALL"}, keywordLine},
+	{[]string{"INSERT"}, keywordLine},
+	{[]string{"UPDATE"}, keywordLine},
+	{[]string{"DELETE"}, keywordLine},
+	{[]string{"USING"}, keywordLine},
+	{[]string{"WHEN"}, keywordLine},
+	{[]string{"LIMIT"}, keywordLine},
+	{[]string{"OFFSET"}, keywordLine},
+	{[]string{"FETCH"}, keywordLine},
+	{[]string{"FOR"}, keywordLine},
+	{[]string{"UNION"}, keywordLine},
+	{[]string{"INTERSECT"}, keywordLine},
+	{[]string{"EXCEPT"}, keywordLine},
+}
+
+//nolint:gochecknoglobals
+var joinModifiers = map[string]bool{
+	"NATURAL": true, "LEFT": true, "RIGHT": true, "FULL": true,
+	"INNER": true, "CROSS": true, "OUTER": true,
+}
+
+// frame is the state of a query or of a pair of parentheses
+type frame struct {
+	// query is true for the whole query and for subqueries.
+	// Clauses are only put on new lines in a query frame
+	query bool
+	// level is the indentation of the clauses
+	level int
+	// openLevel is the indentation of the line with the opening parenthesis
+	openLevel int
+	// clause is the last clause keyword
+	clause string
+	// empty is true until a token is written in the frame
+	empty    bool
+	caseDeep int
+	// between is true after BETWEEN until its AND
+	between bool
+}
+
+type line struct {
+	level int
+	text  strings.Builder
+}
+
+type prettyPrinter struct {
+	indent string
+}
+
+func (p prettyPrinter) print(tokens []token) string {
+	lines := []*line{{}}
+	frames := []*frame{{query: true, empty: true}}
+
+	current := func() *line { return lines[len(lines)-1] }
+
+	newline := func(level int) {
+		if current().text.Len() == 0 {
+			current().level = level
+			return
+		}
+		lines = append(lines, &line{level: level})
+	}
+
+	// prev is the kind of the last token written
+	prev := tokenPunct
+	write := func(t token) {
+		space := t.space
+		switch {
+		case prev == tokenOpen, t.kind == tokenClose:
+			space = false
+		case prev == tokenClose && t.kind == tokenWord:
+			space = true
+		}
+
+		l := current()
+		if l.text.Len() > 0 && space {
+			l.text.WriteString(" ")
+		}
+		l.text.WriteString(t.text)
+		prev = t.kind
+	}
+
+	upper := func(i int) string {
+		if i < 0 || i >= len(tokens) || tokens[i].kind != tokenWord {
+			return ""
+		}
+		return strings.ToUpper(tokens[i].text)
+	}
+
+	// matchKeyword returns the keyword that starts at the token
+	// and the number of tokens it has
+	matchKeyword := func(i int) (string, keywordKind, int) {
+		// a word directly followed by a parenthesis is a function
+		if i+1 < len(tokens) && tokens[i+1].kind == tokenOpen && !tokens[i+1].space {
+			return "", 0, 0
+		}
+
+		for _, kw := range keywords {
+			matched := true
+			for j, word := range kw.words {
+				if upper(i+j) != word {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				return strings.Join(kw.words, " "), kw.kind, len(kw.words)
+			}
+		}
+
+		j := i
+		for joinModifiers[upper(j)] {
+			j++
+		}
+		if upper(j) == "JOIN" || (j == i && upper(j) == "STRAIGHT_JOIN") {
+			return "JOIN", keywordJoin, j - i + 1
+		}
+
+		return "", 0, 0
+	}
+
+	// isKeyword reports whether the keyword should start a new line
+	isKeyword := func(f *frame, i int, name string) bool {
+		prev := upper(i - 1)
+		switch name {
+		case "FROM":
+			// IS [NOT] DISTINCT FROM
+			return prev != "DISTINCT"
+		case "WITH":
+			return f.empty
+		case "FOR":
+			switch upper(i + 1) {
+			case "UPDATE", "SHARE", "NO", "KEY":
+				return true
+			}
+			return false
+		case "UPDATE", "INSERT", "DELETE":
+			// DO UPDATE, THEN INSERT, FOR UPDATE, ...
+			return prev != "DO" && prev != "THEN" && prev != "FOR"
+		case "USING":
+			// JOIN ... USING (...)
+			return f.clause != "FROM"
+		case "SET":
+			// DO UPDATE SET ..., THEN UPDATE SET ...
+			return f.clause != "ON CONFLICT" && f.clause != "WHEN"
+		case "VALUES":
+			// DEFAULT VALUES, THEN INSERT VALUES ...
+			return prev != "DEFAULT" && f.clause != "WHEN"
+		}
+
+		return true
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		f := frames[len(frames)-1]
+
+		if t.kind == tokenWord && f.query && f.caseDeep == 0 {
+			if name, kind, n := matchKeyword(i); kind != 0 && isKeyword(f, i, name) {
+				words := make([]string, n)
+				for j := range n {
+					words[j] = tokens[i+j].text
+				}
+
+				switch kind {
+				case keywordClause:
+					newline(f.level)
+					write(token{text: strings.Join(words, " "), space: true})
+					newline(f.level + 1)
+					f.clause = name
+				case keywordLine:
+					newline(f.level)
+					write(token{text: strings.Join(words, " "), space: true})
+					f.clause = name
+				case keywordJoin:
+					newline(f.level + 1)
+					write(token{text: strings.Join(words, " "), space: true})
+				}
+
+				f.empty = false
+				i += n - 1
+				continue
+			}
+		}
+
+		f.empty = false
+
+		switch t.kind {
+		case tokenWord:
+			switch upper(i) {
+			case "CASE":
+				f.caseDeep++
+			case "END":
+				if f.caseDeep > 0 {
+					f.caseDeep--
+				}
+			case "BETWEEN":
+				f.between = true
+			case "AND", "OR":
+				if f.between && upper(i) == "AND" {
+					f.between = false
+					break
+				}
+				if f.query && f.caseDeep == 0 && (f.clause == "WHERE" || f.clause == "HAVING") {
+					newline(f.level + 1)
+				}
+			}
+			write(t)
+
+		case tokenOpen:
+			write(t)
+			next := upper(i + 1)
+			if next == "SELECT" || next == "WITH" || next == "VALUES" {
+				openLevel := current().level
+				frames = append(frames, &frame{query: true, level: openLevel + 1, openLevel: openLevel, empty: true})
+				newline(openLevel + 1)
+				continue
+			}
+			frames = append(frames, &frame{openLevel: current().level, empty: true})
+
+		case tokenClose:
+			if len(frames) > 1 {
+				frames = frames[:len(frames)-1]
+				if f.query {
+					newline(f.openLevel)
+					t.space = false
+				}
+			}
+			write(t)
+
+		case tokenComma:
+			write(t)
+			if f.query && f.caseDeep == 0 {
+				for _, kw := range keywords {
+					if strings.Join(kw.words, " ") == f.clause && kw.kind == keywordClause {
+						newline(f.level + 1)
+						break
+					}
+				}
+			}
+
+		case tokenSemicolon:
+			write(t)
+			newline(0)
+			lines = append(lines, &line{})
+			frames = []*frame{{query: true, empty: true}}
+
+		case tokenLineComment:
+			write(t)
+			newline(current().level)
+
+		default:
+			write(t)
+		}
+	}
+
+	var b strings.Builder
+	for i, l := range lines {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if l.text.Len() > 0 {
+			b.WriteString(strings.Repeat(p.indent, l.level))
+			b.WriteString(l.text.String())
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,164 @@
+package bob
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPretty(t *testing.T) {
+	tests := map[string]struct {
+		query    string
+		expected string
+	}{
+		"select": {
+			query: `SELECT id, name FROM users WHERE (id = $1) AND (name <> 'x') ORDER BY id LIMIT 10`,
+			expected: `SELECT
+  id,
+  name
+FROM
+  users
+WHERE
+  (id = $1)
+  AND (name <> 'x')
+ORDER BY
+  id
+LIMIT 10`,
+		},
+		"keywords in strings and identifiers are kept": {
+			query: `SELECT 'a FROM b, c' AS "where", $$ WHERE $$ FROM t`,
+			expected: `SELECT
+  'a FROM b, c' AS "where",
+  $$ WHERE $$
+FROM
+  t`,
+		},
+		"escaped strings": {
+			query: `SELECT E'it\'s FROM' FROM t`,
+			expected: `SELECT
+  E'it\'s FROM'
+FROM
+  t`,
+		},
+		"subquery": {
+			query: `SELECT id FROM (SELECT id FROM users WHERE active) AS u WHERE id IN (SELECT user_id FROM orders)`,
+			expected: `SELECT
+  id
+FROM
+  (
+    SELECT
+      id
+    FROM
+      users
+    WHERE
+      active
+  ) AS u
+WHERE
+  id IN (
+    SELECT
+      user_id
+    FROM
+      orders
+  )`,
+		},
+		"functions, case and between": {
+			query: `SELECT extract(year FROM d), CASE WHEN a THEN 1 ELSE 2 END FROM t WHERE a BETWEEN 1 AND 2 AND b IS DISTINCT FROM c`,
+			expected: `SELECT
+  extract(year FROM d),
+  CASE WHEN a THEN 1 ELSE 2 END
+FROM
+  t
+WHERE
+  a BETWEEN 1 AND 2
+  AND b IS DISTINCT FROM c`,
+		},
+		"joins": {
+			query: `SELECT * FROM a INNER JOIN b ON a.id = b.id LEFT OUTER JOIN c USING (id) CROSS JOIN d`,
+			expected: `SELECT
+  *
+FROM
+  a
+  INNER JOIN b ON a.id = b.id
+  LEFT OUTER JOIN c USING (id)
+  CROSS JOIN d`,
+		},
+		"comments": {
+			query: "SELECT a -- the a, FROM\n, b /* FROM */ FROM t",
+			expected: `SELECT
+  a -- the a, FROM
+  ,
+  b /* FROM */
+FROM
+  t`,
+		},
+		"update": {
+			query: `UPDATE t SET a = 1, b = DEFAULT WHERE id = 1 RETURNING *`,
+			expected: `UPDATE t
+SET
+  a = 1,
+  b = DEFAULT
+WHERE
+  id = 1
+RETURNING
+  *`,
+		},
+		"union and locking": {
+			query: `SELECT a FROM t UNION ALL SELECT a FROM u FOR UPDATE`,
+			expected: `SELECT
+  a
+FROM
+  t
+UNION ALL
+SELECT
+  a
+FROM
+  u
+FOR UPDATE`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.expected, Pretty(tc.query)); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+// questionDialect writes ? placeholders and cannot write literals
+type questionDialect struct{}
+
+func (questionDialect) WriteArg(w io.StringWriter, _ int) { w.WriteString("?") }
+
+func (questionDialect) WriteQuoted(w io.StringWriter, s string) {
+	w.WriteString(`"` + s + `"`)
+}
+
+func TestBuildDebugWithoutLiteralDialect(t *testing.T) {
+	q := BaseQuery[Expression]{
+		Expression: ExpressionFunc(func(ctx context.Context, w io.StringWriter, d Dialect, start int) ([]any, error) {
+			w.WriteString("SELECT * FROM t WHERE id = ")
+			d.WriteArg(w, start)
+			return []any{1}, nil
+		}),
+		Dialect:   questionDialect{},
+		QueryType: QueryTypeSelect,
+	}
+
+	got, err := BuildDebug(context.Background(), q, DebugOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(got.String(), "id = ?") {
+		t.Fatalf("unexpected query %q", got)
+	}
+
+	_, err = BuildDebug(context.Background(), q, DebugOptions{InlineArgs: true})
+	if err == nil {
+		t.Fatalf("expected an error for a dialect without literals, got %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"io"
+	"time"
 
 	"github.com/qdm12/reprint"
 	"github.com/stephenafamo/scan"
@@ -87,6 +88,11 @@ func (b BaseQuery[E]) Type() QueryType {
 	return b.QueryType
 }
 
+// GetDialect returns the dialect the query is written in
+func (b BaseQuery[E]) GetDialect() Dialect {
+	return b.Dialect
+}
+
 func (b BaseQuery[E]) Exec(ctx context.Context, exec Executor) (sql.Result, error) {
 	return Exec(ctx, exec, b)
 }
@@ -107,6 +113,15 @@ func (b BaseQuery[E]) GetLoaders() []Loader {
 	return nil
 }
 
+// GetStatementTimeout returns the statement timeout of the query, or zero if it has none
+func (b BaseQuery[E]) GetStatementTimeout() time.Duration {
+	if t, ok := any(b.Expression).(interface{ GetStatementTimeout() time.Duration }); ok {
+		return t.GetStatementTimeout()
+	}
+
+	return 0
+}
+
 func (b BaseQuery[E]) GetMapperMods() []scan.MapperMod {
 	if l, ok := any(b.Expression).(MapperModder); ok {
 		return l.GetMapperMods()
@@ -0,0 +1,111 @@
+package querycache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NewMemoryBackend returns a [Backend] that keeps the entries in memory.
+// Expired entries are removed when they are read or when their tables are invalidated
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		entries: map[string]memoryEntry{},
+		tables:  map[string]map[string]struct{}{},
+		now:     time.Now,
+	}
+}
+
+// MemoryBackend is an in-memory [Backend]. It is safe for concurrent use
+type MemoryBackend struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	tables  map[string]map[string]struct{}
+	now     func() time.Time
+}
+
+type memoryEntry struct {
+	Entry
+	tables  []string
+	expires time.Time
+}
+
+// Len returns the number of stored entries, including expired ones that
+// have not been removed yet
+func (m *MemoryBackend) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.entries)
+}
+
+// Get implements [Backend]
+func (m *MemoryBackend) Get(_ context.Context, key string) (Entry, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return Entry{}, false, nil
+	}
+
+	if !entry.expires.IsZero() && !m.now().Before(entry.expires) {
+		m.remove(key)
+		return Entry{}, false, nil
+	}
+
+	return entry.Entry, true, nil
+}
+
+// Set implements [Backend]
+func (m *MemoryBackend) Set(_ context.Context, key string, entry Entry, tables []string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.remove(key)
+
+	stored := memoryEntry{Entry: entry, tables: tables}
+	if ttl > 0 {
+		stored.expires = m.now().Add(ttl)
+	}
+	m.entries[key] = stored
+
+	for _, table := range tables {
+		if m.tables[table] == nil {
+			m.tables[table] = map[string]struct{}{}
+		}
+		m.tables[table][key] = struct{}{}
+	}
+
+	return nil
+}
+
+// Invalidate implements [Backend]
+func (m *MemoryBackend) Invalidate(_ context.Context, tables ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, table := range tables {
+		for key := range m.tables[table] {
+			m.remove(key)
+		}
+	}
+
+	return nil
+}
+
+// remove deletes the entry and its references. m.mu must be held
+func (m *MemoryBackend) remove(key string) {
+	entry, ok := m.entries[key]
+	if !ok {
+		return
+	}
+
+	delete(m.entries, key)
+	for _, table := range entry.tables {
+		delete(m.tables[table], key)
+		if len(m.tables[table]) == 0 {
+			delete(m.tables, table)
+		}
+	}
+}
@@ -0,0 +1,527 @@
+// Package querycache caches the results of read queries run through a [bob.Executor].
+//
+// Results are keyed on the SQL and the args of the query. SELECT queries started
+// from a generated table or view (e.g. models.Users.Query()) are cached and tagged
+// with the name of the table and of the tables it reads from in FROM and JOIN clauses.
+// Writes started from a generated table (e.g. models.Users.Update()) remove
+// the cached results of that table.
+//
+// Rows written with CopyFrom, or by anything that does not go through the cache,
+// never invalidate cached results.
+package querycache
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/internal/replay"
+	"github.com/stephenafamo/bob/orm"
+	"github.com/stephenafamo/scan"
+)
+
+// Entry is the cached result of a query
+type Entry struct {
+	Columns []string
+	Rows    [][]any
+}
+
+// Backend stores cached entries.
+// The values in an entry are those scanned from the database into an [any],
+// a backend that serializes entries must be able to restore them.
+type Backend interface {
+	// Get returns the entry stored with the key if it has not expired
+	Get(ctx context.Context, key string) (Entry, bool, error)
+	// Set stores the entry with the key and the tables it depends on.
+	// A ttl of zero means that the entry does not expire
+	Set(ctx context.Context, key string, entry Entry, tables []string, ttl time.Duration) error
+	// Invalidate removes all the entries that depend on any of the tables
+	Invalidate(ctx context.Context, tables ...string) error
+}
+
+// Option configures the cache
+type Option func(*config)
+
+// WithTTL sets how long cached results are kept.
+// Defaults to one minute. Zero keeps them until they are invalidated
+func WithTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+type config struct {
+	backend Backend
+	ttl     time.Duration
+
+	// versions counts the invalidations of each table,
+	// to detect the ones that happen while a result is fetched
+	mu       sync.Mutex
+	versions map[string]uint64
+}
+
+func newConfig(backend Backend, opts []Option) *config {
+	c := &config{backend: backend, ttl: time.Minute, versions: map[string]uint64{}}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
+}
+
+// version returns the sum of the invalidation counts of the tables
+func (c *config) version(tables []string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var v uint64
+	for _, table := range tables {
+		v += c.versions[table]
+	}
+
+	return v
+}
+
+// invalidate bumps the versions of the tables before removing their entries,
+// so that a result fetched concurrently is either removed or not stored
+func (c *config) invalidate(ctx context.Context, tables ...string) error {
+	if len(tables) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	for _, table := range tables {
+		c.versions[table]++
+	}
+	c.mu.Unlock()
+
+	return c.backend.Invalidate(ctx, tables...)
+}
+
+type ctxKey int
+
+const (
+	ctxSkip ctxKey = iota
+	ctxTables
+)
+
+// Skip modifies a context so that queries run with it are neither read from
+// nor stored in the cache
+func Skip(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxSkip, true)
+}
+
+// DependsOn modifies a context to add tables that queries run with it read from
+// or write to. Use it for queries that join other tables, or for raw writes
+// that were not started from a generated table
+func DependsOn(ctx context.Context, tables ...string) context.Context {
+	existing, _ := ctx.Value(ctxTables).([]string)
+	return context.WithValue(ctx, ctxTables, append(existing[:len(existing):len(existing)], tables...))
+}
+
+// readTables returns the tables that a SELECT query depends on,
+// adding the tables it reads from in FROM and JOIN clauses to those in the context
+func readTables(ctx context.Context, query string) []string {
+	tables := tables(ctx)
+	if len(tables) == 0 {
+		return nil
+	}
+
+	for _, table := range sourceTables(query) {
+		if !slices.Contains(tables, table) {
+			tables = append(tables, table)
+		}
+	}
+
+	return tables
+}
+
+// sourceTables returns the names of the tables that follow a FROM or JOIN keyword,
+// joining the parts of a qualified name with a dot.
+// Names are only unquoted, so the result may contain aliases of CTEs,
+// which is harmless since no write invalidates them
+func sourceTables(query string) []string {
+	var tables []string
+	var afterKeyword bool
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'':
+			// skip string literals, doubled quotes are escapes
+			for i++; i < len(query); i++ {
+				if query[i] == '\'' {
+					if i+1 < len(query) && query[i+1] == '\'' {
+						i++
+						continue
+					}
+					break
+				}
+			}
+			i++
+			afterKeyword = false
+
+		case c == '"' || c == '`' || isIdentByte(c):
+			var parts []string
+			for {
+				part, next := readIdent(query, i)
+				parts = append(parts, part)
+				i = next
+				if i+1 < len(query) && query[i] == '.' && (query[i+1] == '"' || query[i+1] == '`' || isIdentByte(query[i+1])) {
+					i++
+					continue
+				}
+				break
+			}
+
+			name := strings.Join(parts, ".")
+			switch {
+			case afterKeyword && !strings.EqualFold(name, "lateral") && !strings.EqualFold(name, "only"):
+				tables = append(tables, name)
+				afterKeyword = false
+			case len(parts) == 1 && c != '"' && c != '`' &&
+				(strings.EqualFold(name, "from") || strings.EqualFold(name, "join")):
+				afterKeyword = true
+			}
+
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+
+		default:
+			i++
+			afterKeyword = false
+		}
+	}
+
+	return tables
+}
+
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// readIdent reads a quoted or unquoted identifier starting at i
+// and returns it unquoted with the index after it
+func readIdent(query string, i int) (string, int) {
+	quote := query[i]
+	if quote != '"' && quote != '`' {
+		start := i
+		for i < len(query) && isIdentByte(query[i]) {
+			i++
+		}
+		return query[start:i], i
+	}
+
+	var b strings.Builder
+	for i++; i < len(query); i++ {
+		if query[i] == quote {
+			if i+1 < len(query) && query[i+1] == quote {
+				b.WriteByte(quote)
+				i++
+				continue
+			}
+			return b.String(), i + 1
+		}
+		b.WriteByte(query[i])
+	}
+
+	return b.String(), i
+}
+
+// tables returns the tables that the query being executed depends on
+func tables(ctx context.Context) []string {
+	tables, _ := ctx.Value(ctxTables).([]string)
+	if table, ok := orm.QueryTableFromContext(ctx); ok {
+		tables = append(tables[:len(tables):len(tables)], table)
+	}
+
+	return tables
+}
+
+// isWrite reports whether the query being executed writes to its tables.
+// Queries run with ExecContext are writes unless they are known to be a SELECT
+func isWrite(ctx context.Context, exec bool) bool {
+	typ, _ := bob.QueryTypeFromContext(ctx)
+	switch typ {
+	case bob.QueryTypeInsert, bob.QueryTypeUpdate, bob.QueryTypeDelete, bob.QueryTypeMerge:
+		return true
+	case bob.QueryTypeSelect:
+		return false
+	default:
+		return exec
+	}
+}
+
+// Wrap caches the results of queries run with the [bob.Executor]
+// in the given backend
+func Wrap(exec bob.Executor, backend Backend, opts ...Option) Executor {
+	return Executor{exec: exec, config: newConfig(backend, opts)}
+}
+
+// WrapTransactor caches the results of queries run with a [bob.Transactor] such as
+// [bob.DB] or the types in [github.com/stephenafamo/bob/drivers/pgx].
+//
+// Queries in transactions started with Begin are not cached since they may see
+// uncommitted changes. The tables written to in a transaction are invalidated
+// once it is committed.
+func WrapTransactor[Tx bob.Transaction](t bob.Transactor[Tx], backend Backend, opts ...Option) Transactor[Tx] {
+	return Transactor[Tx]{
+		Executor: Wrap(t, backend, opts...),
+		t:        t,
+	}
+}
+
+// Executor is a [bob.Executor] that caches the results of read queries
+type Executor struct {
+	exec bob.Executor
+	*config
+}
+
+// Invalidate removes the cached results of queries that depend on any of the tables
+func (e Executor) Invalidate(ctx context.Context, tables ...string) error {
+	return e.invalidate(ctx, tables...)
+}
+
+// ExecContext executes the query, and invalidates its tables
+// unless it is a SELECT query
+func (e Executor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	result, err := e.exec.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.invalidateWritten(ctx, true); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// QueryContext returns the cached result of SELECT queries that depend on a
+// known table, running the query and caching the result on a miss.
+// SELECT queries that lock rows or have args that cannot be keyed are not cached.
+// Other queries are executed, and the tables of INSERT, UPDATE, DELETE
+// and MERGE queries are invalidated
+func (e Executor) QueryContext(ctx context.Context, query string, args ...any) (scan.Rows, error) {
+	typ, _ := bob.QueryTypeFromContext(ctx)
+	tables := readTables(ctx, query)
+
+	key, ok := Key(query, args)
+
+	skip, _ := ctx.Value(ctxSkip).(bool)
+	if typ != bob.QueryTypeSelect || bob.IsLocking(ctx) || len(tables) == 0 || skip || !ok {
+		rows, err := e.exec.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := e.invalidateWritten(ctx, false); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		return rows, nil
+	}
+
+	entry, ok, err := e.backend.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("querycache: get: %w", err)
+	}
+
+	if !ok {
+		version := e.version(tables)
+		entry, err = e.fetch(ctx, query, args)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := e.backend.Set(ctx, key, entry, tables, e.ttl); err != nil {
+			return nil, fmt.Errorf("querycache: set: %w", err)
+		}
+
+		// A write invalidated the tables while the result was fetched,
+		// so the stored result may be stale
+		if e.version(tables) != version {
+			if err := e.backend.Invalidate(ctx, tables...); err != nil {
+				return nil, fmt.Errorf("querycache: invalidate: %w", err)
+			}
+		}
+	}
+
+	return replay.Rows(ctx, entry.Columns, entry.Rows)
+}
+
+func (e Executor) invalidateWritten(ctx context.Context, exec bool) error {
+	if !isWrite(ctx, exec) {
+		return nil
+	}
+
+	if err := e.Invalidate(ctx, tables(ctx)...); err != nil {
+		return fmt.Errorf("querycache: invalidate: %w", err)
+	}
+
+	return nil
+}
+
+// fetch runs the query and reads all the rows
+func (e Executor) fetch(ctx context.Context, query string, args []any) (Entry, error) {
+	rows, err := e.exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return Entry{}, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry := Entry{Columns: columns}
+	for rows.Next() {
+		row := make([]any, len(columns))
+		dest := make([]any, len(columns))
+		for i := range row {
+			dest[i] = &row[i]
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return Entry{}, err
+		}
+
+		entry.Rows = append(entry.Rows, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return Entry{}, err
+	}
+
+	return entry, rows.Close()
+}
+
+// Key returns the cache key of a query.
+// The args are converted with [driver.DefaultParameterConverter] first, so pointers are
+// keyed by the values they point to and [driver.Valuer]s by their values.
+// It returns false if an arg cannot be converted, and the query is then not cached
+func Key(query string, args []any) (string, bool) {
+	h := sha256.New()
+	h.Write([]byte(query))
+
+	for _, arg := range args {
+		val, err := driver.DefaultParameterConverter.ConvertValue(arg)
+		if err != nil {
+			return "", false
+		}
+
+		if t, ok := val.(time.Time); ok {
+			// the monotonic clock reading differs for equal times
+			val = t.Round(0)
+		}
+
+		fmt.Fprintf(h, "\x00%T:%v", val, val)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// Transaction is a [bob.Transaction] that invalidates the tables written to
+// once it is committed
+type Transaction struct {
+	tx      bob.Transaction
+	cache   *config
+	mu      *sync.Mutex
+	written map[string]struct{}
+}
+
+// ExecContext executes the query in the transaction
+func (t Transaction) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	result, err := t.tx.ExecContext(ctx, query, args...)
+	if err == nil {
+		t.recordWritten(ctx, true)
+	}
+
+	return result, err
+}
+
+// QueryContext executes the query in the transaction without using the cache
+func (t Transaction) QueryContext(ctx context.Context, query string, args ...any) (scan.Rows, error) {
+	rows, err := t.tx.QueryContext(ctx, query, args...)
+	if err == nil {
+		t.recordWritten(ctx, false)
+	}
+
+	return rows, err
+}
+
+func (t Transaction) recordWritten(ctx context.Context, exec bool) {
+	if !isWrite(ctx, exec) {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, table := range tables(ctx) {
+		t.written[table] = struct{}{}
+	}
+}
+
+// Commit commits the transaction and invalidates the tables written to
+func (t Transaction) Commit(ctx context.Context) error {
+	if err := t.tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.written) == 0 {
+		return nil
+	}
+
+	tables := make([]string, 0, len(t.written))
+	for table := range t.written {
+		tables = append(tables, table)
+	}
+	clear(t.written)
+
+	if err := t.cache.invalidate(ctx, tables...); err != nil {
+		return fmt.Errorf("querycache: invalidate: %w", err)
+	}
+
+	return nil
+}
+
+// Rollback rolls back the transaction
+func (t Transaction) Rollback(ctx context.Context) error {
+	return t.tx.Rollback(ctx)
+}
+
+// Transactor is a [bob.Transactor] that caches the results of read queries
+type Transactor[Tx bob.Transaction] struct {
+	Executor
+	t bob.Transactor[Tx]
+}
+
+// Begin starts a transaction whose queries are not cached
+func (t Transactor[Tx]) Begin(ctx context.Context) (Transaction, error) {
+	tx, err := t.t.Begin(ctx)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	return Transaction{
+		tx:      tx,
+		cache:   t.config,
+		mu:      &sync.Mutex{},
+		written: map[string]struct{}{},
+	}, nil
+}
@@ -0,0 +1,351 @@
+package querycache
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/sqlite"
+	"github.com/stephenafamo/bob/dialect/sqlite/dialect"
+	"github.com/stephenafamo/bob/dialect/sqlite/dm"
+	"github.com/stephenafamo/bob/dialect/sqlite/sm"
+	"github.com/stephenafamo/bob/dialect/sqlite/um"
+	"github.com/stephenafamo/scan"
+	_ "modernc.org/sqlite"
+)
+
+var (
+	_ bob.Executor                = Wrap(bob.DB{}, nil)
+	_ bob.Transactor[Transaction] = WrapTransactor(bob.DB{}, nil)
+	_ Backend                     = NewMemoryBackend()
+)
+
+type user struct {
+	ID   int64  `db:"id,pk"`
+	Name string `db:"name"`
+}
+
+type userSetter struct {
+	Name *string `db:"name"`
+}
+
+func (s userSetter) SetColumns() []string { return []string{"name"} }
+
+func (s userSetter) Apply(q *dialect.InsertQuery) {}
+
+func (s userSetter) UpdateMod() bob.Mod[*dialect.UpdateQuery] {
+	return bob.ModFunc[*dialect.UpdateQuery](func(*dialect.UpdateQuery) {})
+}
+
+var userTable = sqlite.NewTable[user, userSetter]("", "users", sqlite.Quote("users", "*"))
+
+func setup(t *testing.T) (bob.DB, *MemoryBackend, Transactor[bob.Tx]) {
+	t.Helper()
+
+	db, err := bob.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.ExecContext(context.Background(), `
+		CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+		INSERT INTO users (name) VALUES ('alice'), ('bob');
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := NewMemoryBackend()
+	return db, backend, WrapTransactor(db, backend, WithTTL(time.Minute))
+}
+
+func countUsers(t *testing.T, ctx context.Context, exec bob.Executor) int {
+	t.Helper()
+
+	users, err := userTable.Query(sm.OrderBy("id")).All(ctx, exec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return len(users)
+}
+
+func TestCacheHitAndInvalidate(t *testing.T) {
+	ctx := context.Background()
+	db, backend, exec := setup(t)
+
+	if n := countUsers(t, ctx, exec); n != 2 {
+		t.Fatalf("expected 2 users, got %d", n)
+	}
+	if backend.Len() != 1 {
+		t.Fatalf("expected 1 cached entry, got %d", backend.Len())
+	}
+
+	// Written without the cache, so the cached result is still returned
+	if _, err := db.ExecContext(ctx, "INSERT INTO users (name) VALUES ('carol')"); err != nil {
+		t.Fatal(err)
+	}
+	if n := countUsers(t, ctx, exec); n != 2 {
+		t.Fatalf("expected the cached 2 users, got %d", n)
+	}
+
+	user, err := userTable.Query(sm.Where(sqlite.Quote("id").EQ(sqlite.Arg(1)))).One(ctx, exec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "alice" {
+		t.Fatalf("unexpected user %v", user)
+	}
+	if backend.Len() != 2 {
+		t.Fatalf("expected 2 cached entries, got %d", backend.Len())
+	}
+
+	// Raw queries are not cached
+	if _, err := bob.All(ctx, exec, sqlite.RawQuery("SELECT name FROM users"), scan.SingleColumnMapper[string]); err != nil {
+		t.Fatal(err)
+	}
+	if backend.Len() != 2 {
+		t.Fatalf("expected 2 cached entries, got %d", backend.Len())
+	}
+
+	_, err = userTable.Update(
+		um.SetCol("name").ToArg("dave"),
+		um.Where(sqlite.Quote("id").EQ(sqlite.Arg(3))),
+	).Exec(ctx, exec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backend.Len() != 0 {
+		t.Fatalf("expected the entries to be invalidated, got %d", backend.Len())
+	}
+	if n := countUsers(t, ctx, exec); n != 3 {
+		t.Fatalf("expected 3 users, got %d", n)
+	}
+
+	_, err = userTable.Delete(dm.Where(sqlite.Quote("id").EQ(sqlite.Arg(3)))).Exec(ctx, exec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := countUsers(t, ctx, exec); n != 2 {
+		t.Fatalf("expected 2 users, got %d", n)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	db, backend, exec := setup(t)
+
+	now := time.Now()
+	backend.now = func() time.Time { return now }
+
+	countUsers(t, ctx, exec)
+	if _, err := db.ExecContext(ctx, "DELETE FROM users"); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(59 * time.Second)
+	if n := countUsers(t, ctx, exec); n != 2 {
+		t.Fatalf("expected the cached 2 users, got %d", n)
+	}
+
+	now = now.Add(time.Second)
+	if n := countUsers(t, ctx, exec); n != 0 {
+		t.Fatalf("expected the entry to expire, got %d users", n)
+	}
+}
+
+func TestCacheSkipAndDependsOn(t *testing.T) {
+	ctx := context.Background()
+	db, backend, exec := setup(t)
+
+	countUsers(t, Skip(ctx), exec)
+	if backend.Len() != 0 {
+		t.Fatalf("expected nothing to be cached, got %d", backend.Len())
+	}
+
+	countUsers(t, DependsOn(ctx, "posts"), exec)
+	if _, err := db.ExecContext(ctx, "DELETE FROM users"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Raw queries run with ExecContext invalidate the tables they declare
+	_, err := bob.Exec(DependsOn(ctx, "comments"), exec, sqlite.RawQuery("DELETE FROM users WHERE 1 = 0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backend.Len() != 1 {
+		t.Fatalf("expected the entry to be kept, got %d", backend.Len())
+	}
+
+	_, err = bob.Exec(DependsOn(ctx, "posts"), exec, sqlite.RawQuery("DELETE FROM users WHERE 1 = 0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backend.Len() != 0 {
+		t.Fatalf("expected the entry to be invalidated, got %d", backend.Len())
+	}
+
+	countUsers(t, ctx, exec)
+	if err := exec.Invalidate(ctx, "users"); err != nil {
+		t.Fatal(err)
+	}
+	if n := countUsers(t, ctx, exec); n != 0 {
+		t.Fatalf("expected the entry to be invalidated, got %d users", n)
+	}
+}
+
+func TestCacheTransaction(t *testing.T) {
+	ctx := context.Background()
+	_, backend, exec := setup(t)
+
+	countUsers(t, ctx, exec)
+
+	tx, err := exec.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = userTable.Delete(dm.Where(sqlite.Quote("id").EQ(sqlite.Arg(1)))).Exec(ctx, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := countUsers(t, ctx, tx); n != 1 {
+		t.Fatalf("expected queries in the transaction to skip the cache, got %d users", n)
+	}
+	if backend.Len() != 1 {
+		t.Fatalf("expected the entry to be kept until commit, got %d", backend.Len())
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if backend.Len() != 0 {
+		t.Fatalf("expected the entry to be invalidated on commit, got %d", backend.Len())
+	}
+}
+
+func TestSourceTables(t *testing.T) {
+	cases := map[string][]string{
+		`SELECT * FROM "users" AS "users"`:                                       {"users"},
+		`SELECT * FROM "public"."users" INNER JOIN "posts" ON "posts"."id" = 1`:  {"public.users", "posts"},
+		"SELECT * FROM `users` LEFT JOIN `db`.`posts` AS `p` ON true":            {"users", "db.posts"},
+		`SELECT 'FROM fake' FROM users, LATERAL (SELECT 1 FROM "we""ird") AS x`:  {"users", `we"ird`},
+		`SELECT * FROM (SELECT * FROM comments) AS c JOIN ONLY tags ON c.id = 1`: {"comments", "tags"},
+	}
+
+	for query, expected := range cases {
+		if got := sourceTables(query); !slices.Equal(got, expected) {
+			t.Errorf("%s: expected %v, got %v", query, expected, got)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	key := func(args ...any) string {
+		t.Helper()
+		k, ok := Key("SELECT 1", args)
+		if !ok {
+			t.Fatalf("expected %v to be cacheable", args)
+		}
+		return k
+	}
+
+	id := int64(1)
+	first := key(&id)
+	id = 2
+	if key(&id) == first {
+		t.Error("expected a pointer to be keyed by the value it points to")
+	}
+	if key(&id) != key(int64(2)) {
+		t.Error("expected a pointer to have the key of its value")
+	}
+
+	now := time.Now()
+	if key(now) != key(now.Round(0)) {
+		t.Error("expected the monotonic clock reading to be ignored")
+	}
+
+	if _, ok := Key("SELECT 1", []any{struct{ A int }{1}}); ok {
+		t.Error("expected an arg that cannot be converted not to be cacheable")
+	}
+}
+
+func TestCacheJoinedTables(t *testing.T) {
+	ctx := context.Background()
+	db, backend, exec := setup(t)
+
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL);
+		INSERT INTO posts (user_id) VALUES (1);
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	withPosts := func() int {
+		users, err := userTable.Query(
+			sm.InnerJoin("posts").On(sqlite.Quote("posts", "user_id").EQ(sqlite.Quote("users", "id"))),
+		).All(ctx, exec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(users)
+	}
+
+	if n := withPosts(); n != 1 {
+		t.Fatalf("expected 1 user, got %d", n)
+	}
+
+	_, err := bob.Exec(DependsOn(ctx, "posts"), exec, sqlite.RawQuery("INSERT INTO posts (user_id) VALUES (2)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backend.Len() != 0 {
+		t.Fatalf("expected the entry to be invalidated by a write to the joined table, got %d", backend.Len())
+	}
+	if n := withPosts(); n != 2 {
+		t.Fatalf("expected 2 users, got %d", n)
+	}
+}
+
+// writeDuringQuery runs write before the first query it executes
+type writeDuringQuery struct {
+	bob.Executor
+	write func()
+}
+
+func (w *writeDuringQuery) QueryContext(ctx context.Context, query string, args ...any) (scan.Rows, error) {
+	rows, err := w.Executor.QueryContext(ctx, query, args...)
+	if w.write != nil {
+		w.write()
+		w.write = nil
+	}
+	return rows, err
+}
+
+func TestCacheInvalidatedDuringFetch(t *testing.T) {
+	ctx := context.Background()
+	db, backend, _ := setup(t)
+
+	var exec Executor
+	exec = Wrap(&writeDuringQuery{
+		Executor: db,
+		write: func() {
+			if err := exec.Invalidate(ctx, "users"); err != nil {
+				t.Fatal(err)
+			}
+		},
+	}, backend, WithTTL(0))
+
+	countUsers(t, ctx, exec)
+	if backend.Len() != 0 {
+		t.Fatalf("expected the result fetched during a write not to be kept, got %d", backend.Len())
+	}
+
+	countUsers(t, ctx, exec)
+	if backend.Len() != 1 {
+		t.Fatalf("expected 1 cached entry, got %d", backend.Len())
+	}
+}
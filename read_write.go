@@ -0,0 +1,145 @@
+package bob
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/stephenafamo/scan"
+)
+
+type forcePrimaryCtxKey struct{}
+
+// ForcePrimary returns a context that makes a [ReadWriteExecutor] send every
+// query to the primary. This is useful after a write, when the replicas may not
+// have caught up yet
+func ForcePrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forcePrimaryCtxKey{}, true)
+}
+
+// IsPrimaryForced reports whether the context was returned by [ForcePrimary]
+func IsPrimaryForced(ctx context.Context) bool {
+	forced, _ := ctx.Value(forcePrimaryCtxKey{}).(bool)
+	return forced
+}
+
+// ReadWriteOptions configures how [SplitReads] and [SplitReadsTransactor]
+// choose a replica
+type ReadWriteOptions struct {
+	// Pick returns the replica to send a read query to.
+	// It is only called when there is at least one replica.
+	// if nil, the replicas are used in turn (round-robin)
+	Pick func(ctx context.Context, replicas []Executor) Executor
+}
+
+// RoundRobin returns a picker for [ReadWriteOptions] that uses the replicas in turn
+func RoundRobin() func(context.Context, []Executor) Executor {
+	var next atomic.Uint64
+	return func(_ context.Context, replicas []Executor) Executor {
+		return replicas[(next.Add(1)-1)%uint64(len(replicas))]
+	}
+}
+
+// SplitReads wraps a primary [Executor] and its read replicas.
+// Queries with the [QueryTypeSelect] type, such as those run with [One], [All],
+// [Cursor] and [Each], are sent to a replica. Everything else is sent to the primary,
+// including raw queries whose type is not known and SELECT queries that lock
+// the rows they read, such as those with FOR UPDATE or FOR SHARE (see [IsLocking]).
+//
+// Use [ForcePrimary] to send a read query to the primary
+func SplitReads(primary Executor, replicas []Executor, opts ReadWriteOptions) ReadWriteExecutor {
+	if opts.Pick == nil {
+		opts.Pick = RoundRobin()
+	}
+
+	return ReadWriteExecutor{primary: primary, replicas: replicas, pick: opts.Pick}
+}
+
+// SplitReadsTransactor works like [SplitReads] for a [Transactor] such as [DB].
+// Transactions are always started on the primary
+func SplitReadsTransactor[Tx Transaction](primary Transactor[Tx], replicas []Executor, opts ReadWriteOptions) ReadWriteTransactor[Tx] {
+	return ReadWriteTransactor[Tx]{
+		ReadWriteExecutor: SplitReads(primary, replicas, opts),
+		t:                 primary,
+	}
+}
+
+// ReadWriteExecutor is an [Executor] that sends read queries to replicas
+// and everything else to the primary
+type ReadWriteExecutor struct {
+	primary  Executor
+	replicas []Executor
+	pick     func(context.Context, []Executor) Executor
+}
+
+// Primary returns the executor that writes are sent to
+func (e ReadWriteExecutor) Primary() Executor {
+	return e.primary
+}
+
+// ExecContext always executes the query on the primary
+func (e ReadWriteExecutor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return e.primary.ExecContext(ctx, query, args...)
+}
+
+// QueryContext executes the query on a replica if it is a SELECT query
+// that does not lock rows, and on the primary otherwise
+func (e ReadWriteExecutor) QueryContext(ctx context.Context, query string, args ...any) (scan.Rows, error) {
+	return e.executorFor(ctx).QueryContext(ctx, query, args...)
+}
+
+func (e ReadWriteExecutor) executorFor(ctx context.Context) Executor {
+	if len(e.replicas) == 0 || IsPrimaryForced(ctx) {
+		return e.primary
+	}
+
+	if typ, _ := QueryTypeFromContext(ctx); typ != QueryTypeSelect || IsLocking(ctx) {
+		return e.primary
+	}
+
+	return e.pick(ctx, e.replicas)
+}
+
+// ReadWriteTransactor is a [Transactor] that sends read queries to replicas
+// and everything else, including transactions, to the primary
+type ReadWriteTransactor[Tx Transaction] struct {
+	ReadWriteExecutor
+	t Transactor[Tx]
+}
+
+// Begin starts a transaction on the primary
+func (t ReadWriteTransactor[Tx]) Begin(ctx context.Context) (Tx, error) {
+	return t.t.Begin(ctx)
+}
+
+// BeginTx starts a transaction with the given options on the primary.
+// The primary must have a BeginTx method that takes [*sql.TxOptions], such as [DB].
+// If it does not, only nil options are accepted and the transaction is started with Begin
+func (t ReadWriteTransactor[Tx]) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	if b, ok := t.t.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (Tx, error)
+	}); ok {
+		return b.BeginTx(ctx, opts)
+	}
+
+	if opts != nil {
+		var tx Tx
+		return tx, fmt.Errorf("%T does not support transaction options", t.t)
+	}
+
+	return t.Begin(ctx)
+}
+
+// RunInTx runs the provided function in a transaction on the primary
+// started with BeginTx.
+// If the function returns an error, the transaction is rolled back.
+// Otherwise, the transaction is committed.
+func (t ReadWriteTransactor[Tx]) RunInTx(ctx context.Context, txOptions *sql.TxOptions, fn func(context.Context, Transaction) error) error {
+	tx, err := t.BeginTx(ctx, txOptions)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	return RunTx(ctx, tx, fn)
+}
@@ -0,0 +1,253 @@
+package bob
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stephenafamo/scan"
+)
+
+// namedExecutor records the name of the executor that ran each query
+type namedExecutor struct {
+	name string
+	used *[]string
+}
+
+func (e namedExecutor) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	*e.used = append(*e.used, e.name)
+	return logTestResult(1), nil
+}
+
+func (e namedExecutor) QueryContext(context.Context, string, ...any) (scan.Rows, error) {
+	*e.used = append(*e.used, e.name)
+	return &oneColumnRows{logTestRows{remaining: 1}}, nil
+}
+
+// oneColumnRows returns a single row with a single column
+type oneColumnRows struct{ logTestRows }
+
+func (r *oneColumnRows) Columns() ([]string, error) { return []string{"n"}, nil }
+
+type namedTransactor struct{ namedExecutor }
+
+func (t namedTransactor) Begin(context.Context) (namedTx, error) {
+	return namedTx{namedExecutor{name: t.name + " tx", used: t.used}}, nil
+}
+
+type namedTx struct{ namedExecutor }
+
+func (namedTx) Commit(context.Context) error   { return nil }
+func (namedTx) Rollback(context.Context) error { return nil }
+
+func rawQuery(typ QueryType, sql string) BaseQuery[Expression] {
+	return BaseQuery[Expression]{
+		QueryType: typ,
+		Expression: ExpressionFunc(func(_ context.Context, w io.StringWriter, _ Dialect, _ int) ([]any, error) {
+			_, err := w.WriteString(sql)
+			return nil, err
+		}),
+	}
+}
+
+func TestReadWriteExecutor(t *testing.T) {
+	ctx := context.Background()
+	var used []string
+
+	exec := SplitReadsTransactor(
+		namedTransactor{namedExecutor{name: "primary", used: &used}},
+		[]Executor{
+			namedExecutor{name: "replica 1", used: &used},
+			namedExecutor{name: "replica 2", used: &used},
+		},
+		ReadWriteOptions{},
+	)
+
+	mapper := scan.SingleColumnMapper[int]
+	selectQuery := rawQuery(QueryTypeSelect, "SELECT 1")
+
+	if _, err := One(ctx, exec, selectQuery, mapper); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := All(ctx, exec, selectQuery, mapper); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := All(ctx, exec, selectQuery, mapper); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := All(ctx, exec, rawQuery(QueryTypeInsert, "INSERT INTO x VALUES (1) RETURNING 1"), mapper); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Exec(ctx, exec, rawQuery(QueryTypeUpdate, "UPDATE x SET a = 1")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := exec.QueryContext(ctx, "SELECT 1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := One(ForcePrimary(ctx), exec, selectQuery, mapper); err != nil {
+		t.Fatal(err)
+	}
+
+	// written the way the locking clauses are
+	lockingQuery := BaseQuery[Expression]{
+		QueryType: QueryTypeSelect,
+		Expression: ExpressionFunc(func(ctx context.Context, w io.StringWriter, _ Dialect, _ int) ([]any, error) {
+			MarkLocking(ctx)
+			_, err := w.WriteString("SELECT 1 FOR UPDATE")
+			return nil, err
+		}),
+	}
+	if _, err := One(ctx, exec, lockingQuery, mapper); err != nil {
+		t.Fatal(err)
+	}
+
+	err := exec.RunInTx(ctx, nil, func(ctx context.Context, tx Transaction) error {
+		_, err := All(ctx, tx, selectQuery, mapper)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the primary cannot take transaction options
+	err = exec.RunInTx(ctx, &sql.TxOptions{ReadOnly: true}, func(context.Context, Transaction) error {
+		t.Error("expected the transaction not to start")
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected an error for unsupported transaction options")
+	}
+
+	want := []string{
+		"replica 1",
+		"replica 2",
+		"replica 1",
+		"primary",    // INSERT ... RETURNING
+		"primary",    // UPDATE
+		"primary",    // raw query with an unknown type
+		"primary",    // forced
+		"primary",    // FOR UPDATE
+		"primary tx", // transactions
+	}
+	if diff := cmp.Diff(want, used); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+// optionsTransactor records the options of the transactions started with BeginTx
+type optionsTransactor struct {
+	namedTransactor
+	opts **sql.TxOptions
+}
+
+func (t optionsTransactor) BeginTx(ctx context.Context, opts *sql.TxOptions) (namedTx, error) {
+	*t.opts = opts
+	return t.Begin(ctx)
+}
+
+func TestReadWriteTransactorOptions(t *testing.T) {
+	ctx := context.Background()
+	var used []string
+	var got *sql.TxOptions
+
+	exec := SplitReadsTransactor(
+		optionsTransactor{namedTransactor{namedExecutor{name: "primary", used: &used}}, &got},
+		[]Executor{namedExecutor{name: "replica", used: &used}},
+		ReadWriteOptions{},
+	)
+
+	opts := &sql.TxOptions{Isolation: sql.LevelSerializable}
+	err := exec.RunInTx(ctx, opts, func(ctx context.Context, tx Transaction) error {
+		_, err := All(ctx, tx, rawQuery(QueryTypeSelect, "SELECT 1"), scan.SingleColumnMapper[int])
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != opts {
+		t.Fatalf("expected the options to be passed to the primary, got %v", got)
+	}
+	if diff := cmp.Diff([]string{"primary tx"}, used); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestReadWriteExecutorPick(t *testing.T) {
+	ctx := withQueryType(context.Background(), QueryTypeSelect)
+	var used []string
+
+	exec := SplitReads(
+		namedExecutor{name: "primary", used: &used},
+		[]Executor{
+			namedExecutor{name: "replica 1", used: &used},
+			namedExecutor{name: "replica 2", used: &used},
+		},
+		ReadWriteOptions{
+			Pick: func(_ context.Context, replicas []Executor) Executor {
+				return replicas[len(replicas)-1]
+			},
+		},
+	)
+
+	for range 2 {
+		if _, err := exec.QueryContext(ctx, "SELECT 1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	noReplicas := SplitReads(namedExecutor{name: "primary", used: &used}, nil, ReadWriteOptions{})
+	if _, err := noReplicas.QueryContext(ctx, "SELECT 1"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"replica 2", "replica 2", "primary"}
+	if diff := cmp.Diff(want, used); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+// loadedExpression is an expression with loaders
+type loadedExpression struct {
+	Expression
+	*Load
+}
+
+func TestReadWriteExecutorLoaders(t *testing.T) {
+	ctx := context.Background()
+	var used []string
+
+	exec := SplitReads(
+		namedExecutor{name: "primary", used: &used},
+		[]Executor{namedExecutor{name: "replica", used: &used}},
+		ReadWriteOptions{},
+	)
+
+	// the loader writes with a raw query whose type is not known
+	loader := LoaderFunc(func(ctx context.Context, exec Executor, _ any) error {
+		if _, ok := QueryTypeFromContext(ctx); ok {
+			t.Error("expected no query type in the context of the loader")
+		}
+
+		_, err := exec.QueryContext(ctx, "INSERT INTO x VALUES (1) RETURNING 1")
+		return err
+	})
+
+	expr := loadedExpression{Expression: rawQuery(QueryTypeSelect, "SELECT 1").Expression, Load: &Load{}}
+	expr.AppendLoader(loader)
+	q := BaseQuery[loadedExpression]{QueryType: QueryTypeSelect, Expression: expr}
+
+	if _, err := One(ctx, exec, q, scan.SingleColumnMapper[int]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := All(ctx, exec, q, scan.SingleColumnMapper[int]); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"replica", "primary", "replica", "primary"}
+	if diff := cmp.Diff(want, used); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -0,0 +1,120 @@
+package bob
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryOptions configures how a transaction is run again after a transient error
+// such as a serialization failure or a deadlock
+type RetryOptions struct {
+	// MaxAttempts is the number of times the transaction is run, including the first.
+	// Defaults to 3
+	MaxAttempts int
+	// Backoff returns how long to wait before the given retry, starting from 1.
+	// Defaults to ExponentialBackoff(10*time.Millisecond, time.Second)
+	Backoff func(retry int) time.Duration
+	// Jitter shortens each wait by a random fraction of it, up to this value.
+	// It should be between 0 and 1. Defaults to no jitter.
+	Jitter float64
+	// ShouldRetry reports whether running the transaction again may succeed.
+	// The generated dberrors package has an IsRetryable function for each dialect.
+	// Defaults to IsSerializationFailure
+	ShouldRetry func(error) bool
+}
+
+func (o RetryOptions) withDefaults() RetryOptions {
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 3
+	}
+	if o.Backoff == nil {
+		o.Backoff = ExponentialBackoff(10*time.Millisecond, time.Second)
+	}
+	if o.ShouldRetry == nil {
+		o.ShouldRetry = IsSerializationFailure
+	}
+
+	return o
+}
+
+func (o RetryOptions) wait(retry int) time.Duration {
+	wait := o.Backoff(retry)
+	if o.Jitter > 0 && wait > 0 {
+		wait -= time.Duration(rand.Float64() * o.Jitter * float64(wait))
+	}
+
+	return wait
+}
+
+// ExponentialBackoff doubles the wait on each retry, starting from base,
+// until it reaches max
+func ExponentialBackoff(base, max time.Duration) func(retry int) time.Duration {
+	return func(retry int) time.Duration {
+		wait := base
+		for i := 1; i < retry && wait < max; i++ {
+			wait *= 2
+		}
+
+		return min(wait, max)
+	}
+}
+
+// IsSerializationFailure reports whether the error has the SQLSTATE of a
+// serialization failure (40001) or a deadlock (40P01).
+// This works for errors that have a SQLState() method such as
+// those from github.com/jackc/pgx/v5 and github.com/lib/pq
+func IsSerializationFailure(err error) bool {
+	var stateErr interface{ SQLState() string }
+	if !errors.As(err, &stateErr) {
+		return false
+	}
+
+	switch stateErr.SQLState() {
+	case "40001", "40P01":
+		return true
+	default:
+		return false
+	}
+}
+
+// RunWithRetry calls fn until it succeeds, returns an error that should not be retried,
+// or has been called opts.MaxAttempts times. It waits between attempts and stops early if
+// the context is done.
+//
+// fn should run a whole transaction, since a transaction cannot continue after most errors
+func RunWithRetry(ctx context.Context, opts RetryOptions, fn func(context.Context) error) error {
+	opts = opts.withDefaults()
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || !opts.ShouldRetry(err) {
+			return err
+		}
+
+		if attempt >= opts.MaxAttempts {
+			return fmt.Errorf("after %d attempts: %w", attempt, err)
+		}
+
+		timer := time.NewTimer(opts.wait(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, context.Cause(ctx))
+		case <-timer.C:
+		}
+	}
+}
+
+// RunInTxWithRetry works like RunInTx, but runs the function again in a new
+// transaction if it fails with an error that the options say should be retried.
+// The function may be called more than once, so it should not have side effects
+// outside of the transaction
+func (d DB) RunInTxWithRetry(ctx context.Context, txOptions *sql.TxOptions, retry RetryOptions, fn func(context.Context, Transaction) error) error {
+	return RunWithRetry(ctx, retry, func(ctx context.Context) error {
+		return d.RunInTx(ctx, txOptions, fn)
+	})
+}
@@ -0,0 +1,131 @@
+package bob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type sqlStateErr string
+
+func (e sqlStateErr) Error() string    { return "sqlstate " + string(e) }
+func (e sqlStateErr) SQLState() string { return string(e) }
+
+func TestIsSerializationFailure(t *testing.T) {
+	tests := map[error]bool{
+		sqlStateErr("40001"):                         true,
+		sqlStateErr("40P01"):                         true,
+		fmt.Errorf("call: %w", sqlStateErr("40001")): true,
+		sqlStateErr("23505"):                         false,
+		errors.New("40001"):                          false,
+	}
+
+	for err, want := range tests {
+		if got := IsSerializationFailure(err); got != want {
+			t.Errorf("IsSerializationFailure(%v) = %t, want %t", err, got, want)
+		}
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+
+	want := []time.Duration{10, 20, 40, 50, 50}
+	for i, w := range want {
+		if got := backoff(i + 1); got != w*time.Millisecond {
+			t.Errorf("retry %d: got %s, want %s", i+1, got, w*time.Millisecond)
+		}
+	}
+}
+
+func TestRunWithRetry(t *testing.T) {
+	noWait := func(int) time.Duration { return 0 }
+
+	t.Run("retries until success", func(t *testing.T) {
+		var calls int
+		err := RunWithRetry(context.Background(), RetryOptions{Backoff: noWait}, func(context.Context) error {
+			calls++
+			if calls < 3 {
+				return sqlStateErr("40001")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if calls != 3 {
+			t.Fatalf("expected 3 calls, got %d", calls)
+		}
+	})
+
+	t.Run("stops after max attempts", func(t *testing.T) {
+		var calls int
+		err := RunWithRetry(context.Background(), RetryOptions{MaxAttempts: 2, Backoff: noWait}, func(context.Context) error {
+			calls++
+			return sqlStateErr("40P01")
+		})
+		if !errors.Is(err, sqlStateErr("40P01")) {
+			t.Fatalf("expected the last error, got %v", err)
+		}
+		if calls != 2 {
+			t.Fatalf("expected 2 calls, got %d", calls)
+		}
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		var calls int
+		callErr := errors.New("not retryable")
+		err := RunWithRetry(context.Background(), RetryOptions{Backoff: noWait}, func(context.Context) error {
+			calls++
+			return callErr
+		})
+		if !errors.Is(err, callErr) {
+			t.Fatalf("expected %v, got %v", callErr, err)
+		}
+		if calls != 1 {
+			t.Fatalf("expected 1 call, got %d", calls)
+		}
+	})
+
+	t.Run("custom classifier", func(t *testing.T) {
+		var calls int
+		busy := errors.New("busy")
+		err := RunWithRetry(context.Background(), RetryOptions{
+			Backoff:     noWait,
+			ShouldRetry: func(err error) bool { return errors.Is(err, busy) },
+		}, func(context.Context) error {
+			calls++
+			if calls == 1 {
+				return busy
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if calls != 2 {
+			t.Fatalf("expected 2 calls, got %d", calls)
+		}
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var calls int
+		err := RunWithRetry(ctx, RetryOptions{
+			Backoff: func(int) time.Duration { return time.Hour },
+			Jitter:  0.5,
+		}, func(context.Context) error {
+			calls++
+			cancel()
+			return sqlStateErr("40001")
+		})
+		if !errors.Is(err, context.Canceled) || !errors.Is(err, sqlStateErr("40001")) {
+			t.Fatalf("expected the call and context errors, got %v", err)
+		}
+		if calls != 1 {
+			t.Fatalf("expected 1 call, got %d", calls)
+		}
+	})
+}
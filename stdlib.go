@@ -6,6 +6,7 @@ import (
 	"database/sql/driver"
 	"errors"
 	"fmt"
+	"sync/atomic"
 
 	"github.com/stephenafamo/scan"
 )
@@ -70,6 +71,12 @@ func (d DB) RunInTx(ctx context.Context, txOptions *sql.TxOptions, fn func(conte
 		return fmt.Errorf("begin: %w", err)
 	}
 
+	return RunTx(ctx, tx, fn)
+}
+
+// RunTx calls fn with the given transaction, rolling it back if fn returns an
+// error and committing it otherwise
+func RunTx(ctx context.Context, tx Transaction, fn func(context.Context, Transaction) error) error {
 	if err := fn(ctx, tx); err != nil {
 		err = fmt.Errorf("call: %w", err)
 
@@ -91,12 +98,50 @@ func (d DB) RunInTx(ctx context.Context, txOptions *sql.TxOptions, fn func(conte
 // retains the expected methods used by *sql.Tx
 // This is useful when an existing *sql.Tx is used in other places in the codebase
 func NewTx(tx *sql.Tx) Tx {
-	return Tx{tx}
+	return Tx{Tx: tx}
 }
 
 // Tx is similar to *sql.Tx but implements [Queryer]
+//
+// It also implements [Transactor]. Calling Begin on a Tx starts a nested
+// transaction with a SAVEPOINT, which is supported by PostgreSQL, MySQL and SQLite.
 type Tx struct {
 	*sql.Tx
+	savepoint *savepoint
+}
+
+// savepoint is the state of a nested transaction
+type savepoint struct {
+	name string
+	done bool
+}
+
+// savepointCounter is used to generate unique savepoint names
+var savepointCounter atomic.Uint64
+
+// Begin starts a nested transaction by creating a savepoint.
+// Committing the returned transaction releases the savepoint and
+// rolling it back rolls back to the savepoint.
+// The changes are only saved when the outermost transaction is committed.
+func (t Tx) Begin(ctx context.Context) (Tx, error) {
+	name := fmt.Sprintf("bob_sp_%d", savepointCounter.Add(1))
+	if _, err := t.Tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return Tx{}, err
+	}
+
+	return Tx{Tx: t.Tx, savepoint: &savepoint{name: name}}, nil
+}
+
+// RunInTx runs the provided function in a nested transaction.
+// If the function returns an error, it is rolled back to the savepoint.
+// Otherwise, the savepoint is released.
+func (t Tx) RunInTx(ctx context.Context, fn func(context.Context, Transaction) error) error {
+	tx, err := t.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+
+	return RunTx(ctx, tx, fn)
 }
 
 // PrepareContext creates a prepared statement for later queries or executions
@@ -111,13 +156,45 @@ func (t Tx) QueryContext(ctx context.Context, query string, args ...any) (scan.R
 }
 
 // Commit works the same as [*sql.Tx.Commit]
-func (t Tx) Commit(_ context.Context) error {
-	return t.Tx.Commit()
+// For a nested transaction, it releases the savepoint instead
+func (t Tx) Commit(ctx context.Context) error {
+	if t.savepoint == nil {
+		return t.Tx.Commit()
+	}
+
+	if t.savepoint.done {
+		return sql.ErrTxDone
+	}
+
+	if _, err := t.Tx.ExecContext(ctx, "RELEASE SAVEPOINT "+t.savepoint.name); err != nil {
+		return err
+	}
+
+	t.savepoint.done = true
+	return nil
 }
 
 // Rollback works the same as [*sql.Tx.Rollback]
-func (t Tx) Rollback(_ context.Context) error {
-	return t.Tx.Rollback()
+// For a nested transaction, it rolls back to the savepoint and releases it
+func (t Tx) Rollback(ctx context.Context) error {
+	if t.savepoint == nil {
+		return t.Tx.Rollback()
+	}
+
+	if t.savepoint.done {
+		return sql.ErrTxDone
+	}
+
+	if _, err := t.Tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+t.savepoint.name); err != nil {
+		return err
+	}
+
+	if _, err := t.Tx.ExecContext(ctx, "RELEASE SAVEPOINT "+t.savepoint.name); err != nil {
+		return err
+	}
+
+	t.savepoint.done = true
+	return nil
 }
 
 func (tx Tx) StmtContext(ctx context.Context, stmt StdPrepared) StdPrepared {
@@ -1,5 +1,16 @@
 package bob
 
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	_ "modernc.org/sqlite"
+)
+
 var (
 	_ Preparer[StdPrepared] = DB{}
 	_ Executor              = DB{}
@@ -16,5 +27,93 @@ var (
 	_ Preparer[StdPrepared]  = Tx{}
 	_ Executor               = Tx{}
 	_ Transaction            = Tx{}
+	_ Transactor[Tx]         = Tx{}
 	_ txForStmt[StdPrepared] = Tx{}
 )
+
+func TestTxSavepoints(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(ctx, "CREATE TABLE items (name TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+
+	errFailed := errors.New("failed")
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx Transaction) error {
+		outer := tx.(Tx)
+		if _, err := outer.ExecContext(ctx, "INSERT INTO items VALUES ('outer')"); err != nil {
+			return err
+		}
+
+		// A committed nested transaction, with a rolled back one inside it
+		err := outer.RunInTx(ctx, func(ctx context.Context, tx Transaction) error {
+			if _, err := tx.ExecContext(ctx, "INSERT INTO items VALUES ('released')"); err != nil {
+				return err
+			}
+
+			err := tx.(Tx).RunInTx(ctx, func(ctx context.Context, tx Transaction) error {
+				if _, err := tx.ExecContext(ctx, "INSERT INTO items VALUES ('inner')"); err != nil {
+					return err
+				}
+				return errFailed
+			})
+			if !errors.Is(err, errFailed) {
+				return fmt.Errorf("expected %v, got %v", errFailed, err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		nested, err := outer.Begin(ctx)
+		if err != nil {
+			return err
+		}
+		if _, err := nested.ExecContext(ctx, "INSERT INTO items VALUES ('rolled back')"); err != nil {
+			return err
+		}
+		if err := nested.Rollback(ctx); err != nil {
+			return err
+		}
+		if err := nested.Commit(ctx); !errors.Is(err, sql.ErrTxDone) {
+			return fmt.Errorf("expected %v, got %v", sql.ErrTxDone, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT name FROM items ORDER BY rowid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"outer", "released"}
+	if diff := cmp.Diff(want, names); diff != "" {
+		t.Fatal(diff)
+	}
+}
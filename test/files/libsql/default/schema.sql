@@ -206,6 +206,27 @@ CREATE INDEX idx4 ON test_index_expressions (col3);
 CREATE INDEX idx5 ON test_index_expressions (col1 DESC, col2 DESC);
 CREATE INDEX idx6 ON test_index_expressions (POW(col3, 2));
 
+-- The version column is used for optimistic locking
+create table versioned_items (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name text not null,
+	version int not null default 1
+);
+
+-- Rows with a deleted_at value are soft deleted
+create table soft_deleted_items (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name text not null,
+	deleted_at timestamp
+);
+
+create table timestamped_items (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name text not null,
+	created_at timestamp not null,
+	updated_at timestamp not null
+);
+
 CREATE TABLE foo_bar (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     secret_col TEXT NOT NULL
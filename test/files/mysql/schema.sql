@@ -233,6 +233,44 @@ CREATE INDEX idx4 ON test_index_expressions (col3);
 CREATE INDEX idx5 ON test_index_expressions (col1 DESC, col2 DESC);
 CREATE INDEX idx6 ON test_index_expressions ((POW(col3, 2)));
 
+-- The version column is used for optimistic locking
+create table versioned_items (
+	id int primary key not null auto_increment,
+	name text not null,
+	version int not null default 1
+);
+
+-- Rows with a deleted_at value are soft deleted
+create table soft_deleted_items (
+	id int primary key not null auto_increment,
+	name text not null,
+	deleted_at timestamp null
+);
+
+-- Rows are scoped to the tenant in the context
+create table tenant_items (
+	id int primary key not null auto_increment,
+	tenant_id int not null,
+	name varchar(255) not null,
+	constraint tenant_items_tenant_id_name_key unique (tenant_id, name)
+);
+
+-- Notes of a tenant item, also scoped to the tenant in the context
+create table tenant_item_notes (
+	id int primary key not null auto_increment,
+	tenant_id int not null,
+	tenant_item_id int not null,
+	body text not null,
+	foreign key (tenant_item_id) references tenant_items(id)
+);
+
+create table timestamped_items (
+	id int primary key not null auto_increment,
+	name text not null,
+	created_at timestamp not null,
+	updated_at timestamp not null
+);
+
 CREATE TABLE foo_bar (
     id INT AUTO_INCREMENT PRIMARY KEY,
     secret_col VARCHAR(255) NOT NULL
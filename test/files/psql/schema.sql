@@ -346,6 +346,44 @@ CREATE INDEX idx4 ON test_index_expressions (col3);
 CREATE INDEX idx5 ON test_index_expressions (col1 DESC, col2 DESC);
 CREATE INDEX idx6 ON test_index_expressions (POW(col3, 2));
 
+-- The version column is used for optimistic locking
+create table versioned_items (
+	id serial primary key not null,
+	name text not null,
+	version integer not null default 1
+);
+
+-- Rows with a deleted_at value are soft deleted
+create table soft_deleted_items (
+	id serial primary key not null,
+	name text not null,
+	deleted_at timestamp
+);
+
+-- Rows are scoped to the tenant in the context
+create table tenant_items (
+	id serial primary key not null,
+	tenant_id integer not null,
+	name text not null,
+	constraint tenant_items_tenant_id_name_key unique (tenant_id, name)
+);
+
+-- Notes of a tenant item, also scoped to the tenant in the context
+create table tenant_item_notes (
+	id serial primary key not null,
+	tenant_id integer not null,
+	tenant_item_id integer not null,
+	body text not null,
+	foreign key (tenant_item_id) references tenant_items(id)
+);
+
+create table timestamped_items (
+	id serial primary key not null,
+	name text not null,
+	created_at timestamp not null,
+	updated_at timestamp not null
+);
+
 CREATE TABLE foo_bar (
     id SERIAL PRIMARY KEY,
     secret_col VARCHAR(255) NOT NULL
@@ -205,6 +205,45 @@ CREATE INDEX idx4 ON test_index_expressions (col3);
 CREATE INDEX idx5 ON test_index_expressions (col1 DESC, col2 DESC);
 CREATE INDEX idx6 ON test_index_expressions (POW(col3, 2));
 
+-- The version column is used for optimistic locking
+create table versioned_items (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name text not null,
+	version int not null default 1
+);
+
+-- Rows with a deleted_at value are soft deleted
+create table soft_deleted_items (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name text not null,
+	deleted_at timestamp
+);
+
+-- A full text search table. Its shadow tables are not generated
+create virtual table documents using fts5(title, body);
+
+-- Rows are scoped to the tenant in the context
+create table tenant_items (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	tenant_id int not null,
+	name text not null
+);
+
+-- Notes of a tenant item, also scoped to the tenant in the context
+create table tenant_item_notes (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	tenant_id int not null,
+	tenant_item_id int not null references tenant_items(id),
+	body text not null
+);
+
+create table timestamped_items (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name text not null,
+	created_at timestamp not null,
+	updated_at timestamp not null
+);
+
 CREATE TABLE foo_bar (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     secret_col TEXT NOT NULL
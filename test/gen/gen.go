@@ -26,6 +26,21 @@ const module = "github.com/stephenafamo/bob/orm/bob-gen-test"
 
 var rgxHasSpaces = regexp.MustCompile(`^\s+`)
 
+// versionColumn enables optimistic locking for the versioned_items table
+var versionColumn = gen.ColumnSelector{Name: "version", Tables: []string{"versioned_items"}}
+
+// softDeleteColumn enables soft deletes for the soft_deleted_items table
+var softDeleteColumn = gen.ColumnSelector{Name: "deleted_at", Tables: []string{"soft_deleted_items"}}
+
+// tenantColumn scopes the tenant tables to the tenant in the context
+var tenantColumn = gen.ColumnSelector{Name: "tenant_id", Tables: []string{"tenant_items", "tenant_item_notes"}}
+
+// timestamps fills the timestamp columns of the timestamped_items table
+var timestamps = plugins.TimestampsConfig{
+	CreatedAt: gen.ColumnSelector{Name: "created_at", Tables: []string{"timestamped_items"}},
+	UpdatedAt: gen.ColumnSelector{Name: "updated_at", Tables: []string{"timestamped_items"}},
+}
+
 type driverWrapper[T, C, I any] struct {
 	drivers.Interface[T, C, I]
 	info            *drivers.DBInfo[T, C, I]
@@ -168,7 +183,7 @@ func TestDriver[T, C, I any](t *testing.T, config DriverTestConfig[T, C, I]) {
 
 		testDriver(
 			t, defaultFolder, config.Templates,
-			gen.Config[C]{}, d, goModFilePath, config.GoTestArgs,
+			gen.Config[C]{VersionColumn: versionColumn, SoftDeleteColumn: softDeleteColumn, TenantColumn: tenantColumn}, d, goModFilePath, config.GoTestArgs,
 			&aliasPlugin[T, C, I]{},
 			queryPathPlugin[T, C, I]{
 				outputPath:   defaultFolder,
@@ -186,7 +201,7 @@ func TestDriver[T, C, I any](t *testing.T, config DriverTestConfig[T, C, I]) {
 
 		testDriver(
 			t, aliasesFolder, config.Templates,
-			gen.Config[C]{Aliases: aliases}, d, goModFilePath, config.GoTestArgs,
+			gen.Config[C]{Aliases: aliases, VersionColumn: versionColumn, SoftDeleteColumn: softDeleteColumn, TenantColumn: tenantColumn}, d, goModFilePath, config.GoTestArgs,
 			&aliasPlugin[T, C, I]{},
 			queryPathPlugin[T, C, I]{
 				outputPath:   aliasesFolder,
@@ -226,7 +241,7 @@ func testDriver[T, C, I any](t *testing.T, dst string, tpls gen.Templates, confi
 	}
 
 	state := &gen.State[C]{Config: config}
-	allPlugins := append(plugins.Setup[T, C, I](plugins.PresetAll, tpls), extraPlugins...)
+	allPlugins := append(plugins.Setup[T, C, I](plugins.PresetAll.Merge(plugins.Config{Timestamps: timestamps}), tpls), extraPlugins...)
 
 	currentDir, err := os.Getwd()
 	if err != nil {
@@ -1895,4 +1895,530 @@ func TestLoadedToOneAfterAttach(t *testing.T) {
 		t.Fatal("Expected video.R.Sponsor to be set after AttachSponsor")
 	}
 }
+
+// TestUpsertVideosByPrimaryKey checks that the generated upsert method updates
+// the conflicting row instead of inserting a new one
+func TestUpsertVideosByPrimaryKey(t *testing.T) {
+	if testDB == nil {
+		t.Skip("skipping test, no DSN provided")
+	}
+
+	ctx := context.Background()
+	tx, err := testDB.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	user := New().NewUserWithContext(ctx).CreateOrFail(ctx, t, tx)
+	user2 := New().NewUserWithContext(ctx).CreateOrFail(ctx, t, tx)
+	video := New().NewVideoWithContext(ctx,
+		VideoMods.WithExistingUser(user),
+	).CreateOrFail(ctx, t, tx)
+
+	countBefore, err := models.Videos.Query().Count(ctx, tx)
+	if err != nil {
+		t.Fatalf("Error counting Videos: %v", err)
+	}
+
+	{{$.Importer.Import "github.com/aarondl/opt/omit"}}
+	videos, err := models.Videos.UpsertByPRIMARY(ctx, tx, &models.VideoSetter{
+		ID:     omit.From(video.ID),
+		UserID: omit.From(user2.ID),
+	})
+	if err != nil {
+		t.Fatalf("Error upserting Video: %v", err)
+	}
+
+	if len(videos) != 1 || videos[0].ID != video.ID {
+		t.Fatalf("Expected the existing video to be returned, got %v", videos)
+	}
+	if videos[0].UserID != user2.ID {
+		t.Fatalf("Expected UserID %d after upsert, got %d", user2.ID, videos[0].UserID)
+	}
+
+	count, err := models.Videos.Query().Count(ctx, tx)
+	if err != nil {
+		t.Fatalf("Error counting Videos: %v", err)
+	}
+	if count != countBefore {
+		t.Fatalf("Expected %d videos after upsert, got %d", countBefore, count)
+	}
+}
+{{- end }}
+
+{{- if has "versioned_items" $.TableNames }}
+{{$.Importer.Import "errors"}}
+{{$.Importer.Import "github.com/aarondl/opt/omit"}}
+{{$.Importer.Import "github.com/stephenafamo/bob/orm"}}
+
+// TestVersionedItemOptimisticLocking checks that Update, UpdateAll and Delete
+// only affect rows with the loaded version, and increment it.
+func TestVersionedItemOptimisticLocking(t *testing.T) {
+	if testDB == nil {
+		t.Skip("skipping test, no DSN provided")
+	}
+
+	ctx := context.Background()
+	tx, err := testDB.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	item := New().NewVersionedItemWithContext(ctx, VersionedItemMods.Version(1)).CreateOrFail(ctx, t, tx)
+	stale := *item
+
+	if err := item.Update(ctx, tx, &models.VersionedItemSetter{Name: omit.From("updated")}); err != nil {
+		t.Fatalf("Error updating VersionedItem: %v", err)
+	}
+	if item.Version != 2 {
+		t.Fatalf("Expected version 2 after update, got %d", item.Version)
+	}
+
+	// The copy still has the old version
+	setter := models.VersionedItemSetter{Name: omit.From("stale")}
+	if err := stale.Update(ctx, tx, &setter); !errors.Is(err, orm.ErrStaleObject) {
+		t.Fatalf("Expected ErrStaleObject from Update, got %v", err)
+	}
+	if err := (models.VersionedItemSlice{&stale}).UpdateAll(ctx, tx, setter); !errors.Is(err, orm.ErrStaleObject) {
+		t.Fatalf("Expected ErrStaleObject from UpdateAll, got %v", err)
+	}
+	if err := stale.Delete(ctx, tx); !errors.Is(err, orm.ErrStaleObject) {
+		t.Fatalf("Expected ErrStaleObject from Delete, got %v", err)
+	}
+	if err := (models.VersionedItemSlice{&stale}).DeleteAll(ctx, tx); !errors.Is(err, orm.ErrStaleObject) {
+		t.Fatalf("Expected ErrStaleObject from DeleteAll, got %v", err)
+	}
+
+	// The stale writes did not change the row
+	if err := item.Reload(ctx, tx); err != nil {
+		t.Fatalf("Error reloading VersionedItem: %v", err)
+	}
+	if item.Version != 2 || item.Name != "updated" {
+		t.Fatalf("Expected version 2 and name %q, got %d and %q", "updated", item.Version, item.Name)
+	}
+
+	other := New().NewVersionedItemWithContext(ctx, VersionedItemMods.Version(1)).CreateOrFail(ctx, t, tx)
+	items := models.VersionedItemSlice{item, other}
+	if err := items.UpdateAll(ctx, tx, models.VersionedItemSetter{Name: omit.From("all")}); err != nil {
+		t.Fatalf("Error updating VersionedItemSlice: %v", err)
+	}
+	if items[0].Version != 3 || items[1].Version != 2 {
+		t.Fatalf("Expected versions 3 and 2 after UpdateAll, got %d and %d", items[0].Version, items[1].Version)
+	}
+
+	if err := items.DeleteAll(ctx, tx); err != nil {
+		t.Fatalf("Error deleting VersionedItemSlice: %v", err)
+	}
+}
+{{- end }}
+
+{{- if has "soft_deleted_items" $.TableNames }}
+{{$.Importer.Import "github.com/stephenafamo/bob/orm"}}
+
+// TestSoftDeletedItemSoftDelete checks that Delete and DeleteAll only mark rows
+// as deleted, and that deleted rows are excluded unless orm.WithDeleted is used.
+func TestSoftDeletedItemSoftDelete(t *testing.T) {
+	if testDB == nil {
+		t.Skip("skipping test, no DSN provided")
+	}
+
+	ctx := context.Background()
+	tx, err := testDB.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	items := models.SoftDeletedItemSlice{
+		New().NewSoftDeletedItemWithContext(ctx, SoftDeletedItemMods.UnsetDeletedAt()).CreateOrFail(ctx, t, tx),
+		New().NewSoftDeletedItemWithContext(ctx, SoftDeletedItemMods.UnsetDeletedAt()).CreateOrFail(ctx, t, tx),
+		New().NewSoftDeletedItemWithContext(ctx, SoftDeletedItemMods.UnsetDeletedAt()).CreateOrFail(ctx, t, tx),
+	}
+
+	count := func(ctx context.Context) int64 {
+		t.Helper()
+		count, err := models.SoftDeletedItems.Query(
+			models.SelectWhere.SoftDeletedItems.ID.In(items[0].ID, items[1].ID, items[2].ID),
+		).Count(ctx, tx)
+		if err != nil {
+			t.Fatalf("Error counting SoftDeletedItems: %v", err)
+		}
+		return count
+	}
+
+	if err := items[0].Delete(ctx, tx); err != nil {
+		t.Fatalf("Error deleting SoftDeletedItem: %v", err)
+	}
+	if c := count(ctx); c != 2 {
+		t.Fatalf("Expected 2 SoftDeletedItems after Delete, got %d", c)
+	}
+	if c := count(orm.WithDeleted(ctx)); c != 3 {
+		t.Fatalf("Expected 3 SoftDeletedItems with deleted, got %d", c)
+	}
+
+	deleted, err := models.FindSoftDeletedItem(orm.WithDeleted(ctx), tx, items[0].ID)
+	if err != nil {
+		t.Fatalf("Error finding deleted SoftDeletedItem: %v", err)
+	}
+	if deleted.DeletedAt.IsNull() {
+		t.Fatal("Expected DeletedAt to be set after Delete")
+	}
+
+	if err := items[1:].DeleteAll(ctx, tx); err != nil {
+		t.Fatalf("Error deleting SoftDeletedItemSlice: %v", err)
+	}
+	if c := count(ctx); c != 0 {
+		t.Fatalf("Expected 0 SoftDeletedItems after DeleteAll, got %d", c)
+	}
+
+	if err := items[0].HardDelete(ctx, tx); err != nil {
+		t.Fatalf("Error hard deleting SoftDeletedItem: %v", err)
+	}
+	if err := items[1:].HardDeleteAll(ctx, tx); err != nil {
+		t.Fatalf("Error hard deleting SoftDeletedItemSlice: %v", err)
+	}
+	if c := count(orm.WithDeleted(ctx)); c != 0 {
+		t.Fatalf("Expected 0 SoftDeletedItems after HardDelete, got %d", c)
+	}
+}
+{{- end }}
+
+{{- if has "tenant_items" $.TableNames }}
+{{$.Importer.Import "database/sql"}}
+{{$.Importer.Import "errors"}}
+{{$.Importer.Import "github.com/aarondl/opt/omit"}}
+{{$.Importer.Import "github.com/stephenafamo/bob/orm"}}
+
+// TestTenantItemTenantScope checks that inserts set the tenant in the context,
+// that queries and deletes only affect the rows of that tenant,
+// and that a missing tenant returns an error
+func TestTenantItemTenantScope(t *testing.T) {
+	if testDB == nil {
+		t.Skip("skipping test, no DSN provided")
+	}
+
+	ctx := context.Background()
+	tx, err := testDB.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tenant1 := orm.WithTenant(ctx, 1)
+	tenant2 := orm.WithTenant(ctx, 2)
+
+	item, err := models.TenantItems.Insert(&models.TenantItemSetter{Name: omit.From("first")}).One(tenant1, tx)
+	if err != nil {
+		t.Fatalf("Error inserting TenantItem: %v", err)
+	}
+	if item.TenantID != 1 {
+		t.Fatalf("Expected the TenantItem to be inserted with tenant 1, got %d", item.TenantID)
+	}
+
+	if _, err := models.TenantItems.Insert(&models.TenantItemSetter{Name: omit.From("second")}).One(tenant2, tx); err != nil {
+		t.Fatalf("Error inserting TenantItem: %v", err)
+	}
+
+	count := func(ctx context.Context) int64 {
+		t.Helper()
+		count, err := models.TenantItems.Query().Count(ctx, tx)
+		if err != nil {
+			t.Fatalf("Error counting TenantItems: %v", err)
+		}
+		return count
+	}
+
+	if c := count(tenant1); c != 1 {
+		t.Fatalf("Expected 1 TenantItem for tenant 1, got %d", c)
+	}
+	if c := count(orm.WithAllTenants(ctx)); c != 2 {
+		t.Fatalf("Expected 2 TenantItems for all tenants, got %d", c)
+	}
+
+	if _, err := models.FindTenantItem(tenant2, tx, item.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Expected the TenantItem of tenant 1 to be hidden from tenant 2, got %v", err)
+	}
+
+	if _, err := models.TenantItems.Query().All(ctx, tx); !errors.Is(err, orm.ErrMissingTenant) {
+		t.Fatalf("Expected orm.ErrMissingTenant without a tenant, got %v", err)
+	}
+
+	deleted, err := models.TenantItems.Delete().Exec(tenant2, tx)
+	if err != nil {
+		t.Fatalf("Error deleting TenantItems: %v", err)
+	}
+	if deleted != 1 {
+		t.Fatalf("Expected 1 TenantItem of tenant 2 to be deleted, got %d", deleted)
+	}
+	if c := count(tenant1); c != 1 {
+		t.Fatalf("Expected the TenantItem of tenant 1 to remain, got %d", c)
+	}
+}
+
+// TestTenantItemUpsert checks that the generated upsert methods never update
+// the rows of another tenant
+func TestTenantItemUpsert(t *testing.T) {
+	if testDB == nil {
+		t.Skip("skipping test, no DSN provided")
+	}
+
+	ctx := context.Background()
+	tx, err := testDB.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tenant1 := orm.WithTenant(ctx, 1)
+	tenant2 := orm.WithTenant(ctx, 2)
+
+	item, err := models.TenantItems.Insert(&models.TenantItemSetter{Name: omit.From("first")}).One(tenant1, tx)
+	if err != nil {
+		t.Fatalf("Error inserting TenantItem: %v", err)
+	}
+
+	// a conflict on the primary key could be a row of another tenant
+	_, err = models.TenantItems.Upsert([]string{"id"}, &models.TenantItemSetter{
+		ID:   omit.From(item.ID),
+		Name: omit.From("stolen"),
+	}).All(tenant2, tx)
+	if !errors.Is(err, orm.ErrUpsertWithoutTenant) {
+		t.Fatalf("Expected orm.ErrUpsertWithoutTenant, got %v", err)
+	}
+
+	// the same name is a different row for another tenant
+	items, err := models.TenantItems.UpsertByTenantItemsTenantIDNameKey(tenant2, tx, &models.TenantItemSetter{Name: omit.From("first")})
+	if err != nil {
+		t.Fatalf("Error upserting TenantItem: %v", err)
+	}
+	if len(items) != 1 || items[0].ID == item.ID || items[0].TenantID != 2 {
+		t.Fatalf("Expected a new TenantItem for tenant 2, got %v", items)
+	}
+
+	items, err = models.TenantItems.UpsertByTenantItemsTenantIDNameKey(tenant1, tx, &models.TenantItemSetter{Name: omit.From("first")})
+	if err != nil {
+		t.Fatalf("Error upserting TenantItem: %v", err)
+	}
+	if len(items) != 1 || items[0].ID != item.ID {
+		t.Fatalf("Expected the existing TenantItem to be returned, got %v", items)
+	}
+}
+{{- end }}
+
+{{- if has "tenant_item_notes" $.TableNames }}
+{{$.Importer.Import "errors"}}
+{{$.Importer.Import "github.com/aarondl/opt/omit"}}
+{{$.Importer.Import "github.com/stephenafamo/bob/orm"}}
+
+// TestTenantItemNotesTenantScope checks that loading and attaching related rows
+// never reads or modifies the rows of another tenant, even when they are linked,
+// and that a missing tenant returns an error
+func TestTenantItemNotesTenantScope(t *testing.T) {
+	if testDB == nil {
+		t.Skip("skipping test, no DSN provided")
+	}
+
+	ctx := context.Background()
+	tx, err := testDB.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tenant1 := orm.WithTenant(ctx, 1)
+	tenant2 := orm.WithTenant(ctx, 2)
+
+	item1, err := models.TenantItems.Insert(&models.TenantItemSetter{Name: omit.From("first")}).One(tenant1, tx)
+	if err != nil {
+		t.Fatalf("Error inserting TenantItem: %v", err)
+	}
+	item2, err := models.TenantItems.Insert(&models.TenantItemSetter{Name: omit.From("second")}).One(tenant2, tx)
+	if err != nil {
+		t.Fatalf("Error inserting TenantItem: %v", err)
+	}
+
+	note1, err := models.TenantItemNotes.Insert(&models.TenantItemNoteSetter{
+		TenantItemID: omit.From(item1.ID), Body: omit.From("note of tenant 1"),
+	}).One(tenant1, tx)
+	if err != nil {
+		t.Fatalf("Error inserting TenantItemNote: %v", err)
+	}
+	note2, err := models.TenantItemNotes.Insert(&models.TenantItemNoteSetter{
+		TenantItemID: omit.From(item2.ID), Body: omit.From("note of tenant 2"),
+	}).One(tenant2, tx)
+	if err != nil {
+		t.Fatalf("Error inserting TenantItemNote: %v", err)
+	}
+	// A note of tenant 2 linked to the item of tenant 1
+	crossNote, err := models.TenantItemNotes.Insert(&models.TenantItemNoteSetter{
+		TenantItemID: omit.From(item1.ID), Body: omit.From("linked note of tenant 2"),
+	}).One(tenant2, tx)
+	if err != nil {
+		t.Fatalf("Error inserting TenantItemNote: %v", err)
+	}
+
+	t.Run("Preload", func(t *testing.T) {
+		notes, err := models.TenantItemNotes.Query(
+			models.Preload.TenantItemNote.TenantItem(),
+		).All(tenant2, tx)
+		if err != nil {
+			t.Fatalf("Error preloading TenantItem: %v", err)
+		}
+		if len(notes) != 2 {
+			t.Fatalf("Expected the 2 TenantItemNotes of tenant 2, got %d", len(notes))
+		}
+
+		for _, note := range notes {
+			switch note.ID {
+			case note2.ID:
+				if note.R.TenantItem == nil || note.R.TenantItem.ID != item2.ID {
+					t.Fatalf("Expected the TenantItem of tenant 2 to be preloaded, got %v", note.R.TenantItem)
+				}
+			case crossNote.ID:
+				if note.R.TenantItem != nil {
+					t.Fatalf("Expected the TenantItem of tenant 1 not to be preloaded, got %v", note.R.TenantItem)
+				}
+			default:
+				t.Fatalf("Unexpected TenantItemNote %d", note.ID)
+			}
+		}
+
+		_, err = models.TenantItemNotes.Query(models.Preload.TenantItemNote.TenantItem()).All(ctx, tx)
+		if !errors.Is(err, orm.ErrMissingTenant) {
+			t.Fatalf("Expected orm.ErrMissingTenant without a tenant, got %v", err)
+		}
+	})
+
+	t.Run("ThenLoad", func(t *testing.T) {
+		item, err := models.TenantItems.Query(
+			models.SelectWhere.TenantItems.ID.EQ(item1.ID),
+			models.SelectThenLoad.TenantItem.TenantItemNotes(),
+		).One(tenant1, tx)
+		if err != nil {
+			t.Fatalf("Error loading TenantItemNotes: %v", err)
+		}
+		if len(item.R.TenantItemNotes) != 1 || item.R.TenantItemNotes[0].ID != note1.ID {
+			t.Fatalf("Expected only the TenantItemNote of tenant 1 to be loaded, got %v", item.R.TenantItemNotes)
+		}
+
+		if err := item1.LoadTenantItemNotes(ctx, tx); !errors.Is(err, orm.ErrMissingTenant) {
+			t.Fatalf("Expected orm.ErrMissingTenant without a tenant, got %v", err)
+		}
+	})
+
+	t.Run("LoadCount", func(t *testing.T) {
+		item, err := models.TenantItems.Query(
+			models.SelectWhere.TenantItems.ID.EQ(item1.ID),
+			models.PreloadCount.TenantItem.TenantItemNotes(),
+		).One(tenant1, tx)
+		if err != nil {
+			t.Fatalf("Error preloading the count of TenantItemNotes: %v", err)
+		}
+		if item.C.TenantItemNotes == nil || *item.C.TenantItemNotes != 1 {
+			t.Fatalf("Expected a preloaded count of 1 TenantItemNote, got %v", item.C.TenantItemNotes)
+		}
+
+		items := models.TenantItemSlice{item1}
+		if err := items.LoadCountTenantItemNotes(tenant1, tx); err != nil {
+			t.Fatalf("Error loading the count of TenantItemNotes: %v", err)
+		}
+		if item1.C.TenantItemNotes == nil || *item1.C.TenantItemNotes != 1 {
+			t.Fatalf("Expected a loaded count of 1 TenantItemNote, got %v", item1.C.TenantItemNotes)
+		}
+
+		if err := item1.LoadCountTenantItemNotes(ctx, tx); !errors.Is(err, orm.ErrMissingTenant) {
+			t.Fatalf("Expected orm.ErrMissingTenant without a tenant, got %v", err)
+		}
+	})
+
+	t.Run("AttachX", func(t *testing.T) {
+		other := *note2
+		if err := item1.AttachTenantItemNotes(tenant1, tx, &other); err != nil {
+			t.Fatalf("Error attaching TenantItemNotes: %v", err)
+		}
+
+		note, err := models.FindTenantItemNote(tenant2, tx, note2.ID)
+		if err != nil {
+			t.Fatalf("Error finding TenantItemNote: %v", err)
+		}
+		if note.TenantItemID != item2.ID {
+			t.Fatalf("Expected the TenantItemNote of tenant 2 to stay on its item, got item %d", note.TenantItemID)
+		}
+
+		if err := item1.AttachTenantItemNotes(ctx, tx, note1); !errors.Is(err, orm.ErrMissingTenant) {
+			t.Fatalf("Expected orm.ErrMissingTenant without a tenant, got %v", err)
+		}
+	})
+}
+{{- end }}
+
+{{- if has "timestamped_items" $.TableNames }}
+{{$.Importer.Import "time"}}
+{{$.Importer.Import "github.com/aarondl/opt/omit"}}
+{{$.Importer.Import "github.com/stephenafamo/bob/orm"}}
+
+// TestTimestampedItemTimestamps checks that created_at and updated_at are
+// filled from the clock in the context when they are not set
+func TestTimestampedItemTimestamps(t *testing.T) {
+	if testDB == nil {
+		t.Skip("skipping test, no DSN provided")
+	}
+
+	ctx := context.Background()
+	tx, err := testDB.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Error starting transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	insertedAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	updatedAt := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	item, err := models.TimestampedItems.Insert(
+		&models.TimestampedItemSetter{Name: omit.From("item")},
+	).One(orm.WithClock(ctx, func() time.Time { return insertedAt }), tx)
+	if err != nil {
+		t.Fatalf("Error inserting TimestampedItem: %v", err)
+	}
+
+	if err := item.Reload(ctx, tx); err != nil {
+		t.Fatalf("Error reloading TimestampedItem: %v", err)
+	}
+	if !item.CreatedAt.Equal(insertedAt) || !item.UpdatedAt.Equal(insertedAt) {
+		t.Fatalf("Expected timestamps to be %v after insert, got %v and %v", insertedAt, item.CreatedAt, item.UpdatedAt)
+	}
+
+	err = item.Update(
+		orm.WithClock(ctx, func() time.Time { return updatedAt }), tx,
+		&models.TimestampedItemSetter{Name: omit.From("updated")},
+	)
+	if err != nil {
+		t.Fatalf("Error updating TimestampedItem: %v", err)
+	}
+
+	if err := item.Reload(ctx, tx); err != nil {
+		t.Fatalf("Error reloading TimestampedItem: %v", err)
+	}
+	if !item.CreatedAt.Equal(insertedAt) {
+		t.Fatalf("Expected CreatedAt to stay %v after update, got %v", insertedAt, item.CreatedAt)
+	}
+	if !item.UpdatedAt.Equal(updatedAt) {
+		t.Fatalf("Expected UpdatedAt to be %v after update, got %v", updatedAt, item.UpdatedAt)
+	}
+
+	// An explicitly set value is not replaced
+	err = item.Update(ctx, tx, &models.TimestampedItemSetter{UpdatedAt: omit.From(insertedAt)})
+	if err != nil {
+		t.Fatalf("Error updating TimestampedItem: %v", err)
+	}
+
+	if err := item.Reload(ctx, tx); err != nil {
+		t.Fatalf("Error reloading TimestampedItem: %v", err)
+	}
+	if !item.UpdatedAt.Equal(insertedAt) {
+		t.Fatalf("Expected UpdatedAt to be %v after setting it, got %v", insertedAt, item.UpdatedAt)
+	}
+}
 {{- end }}
@@ -0,0 +1,75 @@
+package bob
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrStatementTimeout matches every [*StatementTimeoutError] with [errors.Is]
+var ErrStatementTimeout = errors.New("statement timeout")
+
+// StatementTimeoutError is returned when a query is stopped by the database
+// because it ran for longer than its statement timeout
+type StatementTimeoutError struct {
+	Timeout time.Duration
+	// Err is the error returned by the driver
+	Err error
+}
+
+func (e *StatementTimeoutError) Error() string {
+	return fmt.Sprintf("statement timeout of %s exceeded: %v", e.Timeout, e.Err)
+}
+
+func (e *StatementTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+func (e *StatementTimeoutError) Is(target error) bool {
+	return target == ErrStatementTimeout
+}
+
+// StatementTimeout is an embeddable struct that holds the statement timeout of a query
+type StatementTimeout struct {
+	Duration time.Duration
+}
+
+// SetStatementTimeout sets the statement timeout of the query.
+// A timeout of zero means the query has no timeout
+func (s *StatementTimeout) SetStatementTimeout(d time.Duration) {
+	s.Duration = d
+}
+
+// GetStatementTimeout returns the statement timeout of the query
+func (s StatementTimeout) GetStatementTimeout() time.Duration {
+	return s.Duration
+}
+
+// TimeoutDialect is a [Dialect] that can have the database enforce a statement timeout
+type TimeoutDialect interface {
+	Dialect
+	// WithStatementTimeout returns an executor that runs queries with the timeout,
+	// and returns a [*StatementTimeoutError] when a query exceeds it
+	WithStatementTimeout(exec Executor, timeout time.Duration) Executor
+}
+
+// timeoutExecutor returns an executor to run the query with its statement timeout.
+// If the query has no timeout, or its dialect cannot enforce one, exec is returned
+func timeoutExecutor(q Query, exec Executor) Executor {
+	t, ok := q.(interface{ GetStatementTimeout() time.Duration })
+	if !ok || t.GetStatementTimeout() <= 0 {
+		return exec
+	}
+
+	dq, ok := q.(interface{ GetDialect() Dialect })
+	if !ok {
+		return exec
+	}
+
+	d, ok := dq.GetDialect().(TimeoutDialect)
+	if !ok {
+		return exec
+	}
+
+	return d.WithStatementTimeout(exec, t.GetStatementTimeout())
+}
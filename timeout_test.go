@@ -0,0 +1,91 @@
+package bob
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestBegin(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// DB.Begin returns a Tx, so it is found with reflection
+	tx, err := Begin(ctx, NewDB(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tx.(Tx); !ok {
+		t.Fatalf("expected a Tx, got %T", tx)
+	}
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Begin(ctx, NoopExecutor{}); !errors.Is(err, ErrCannotBegin) {
+		t.Fatalf("expected ErrCannotBegin, got %v", err)
+	}
+}
+
+func TestBeginTx(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// DB.BeginTx takes options, so they are passed to it
+	tx, err := BeginTx(ctx, NewDB(db), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tx.(Tx); !ok {
+		t.Fatalf("expected a Tx, got %T", tx)
+	}
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without BeginTx, only nil options are accepted
+	conn := struct{ Transactor[Tx] }{NewDB(db)}
+	if _, err := BeginTx(ctx, conn, &sql.TxOptions{ReadOnly: true}); err == nil {
+		t.Fatal("expected an error for options without BeginTx")
+	}
+
+	tx, err = BeginTx(ctx, conn, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Rollback(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := BeginTx(ctx, NoopExecutor{}, nil); !errors.Is(err, ErrCannotBegin) {
+		t.Fatalf("expected ErrCannotBegin, got %v", err)
+	}
+}
+
+func TestStatementTimeoutError(t *testing.T) {
+	driverErr := errors.New("driver error")
+	var err error = &StatementTimeoutError{Timeout: 1, Err: driverErr}
+
+	if !errors.Is(err, ErrStatementTimeout) {
+		t.Fatal("expected the error to match ErrStatementTimeout")
+	}
+	if !errors.Is(err, driverErr) {
+		t.Fatal("expected the error to wrap the driver error")
+	}
+	if errors.Is(driverErr, ErrStatementTimeout) {
+		t.Fatal("did not expect the driver error to match ErrStatementTimeout")
+	}
+}